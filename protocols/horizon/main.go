@@ -40,12 +40,16 @@ type Account struct {
 	ID                   string            `json:"id"`
 	AccountID            string            `json:"account_id"`
 	Sequence             string            `json:"sequence"`
+	SequenceLedger       uint32            `json:"sequence_ledger"`
+	SequenceBump         uint32            `json:"sequence_bump"`
 	SubentryCount        int32             `json:"subentry_count"`
 	InflationDestination string            `json:"inflation_destination,omitempty"`
 	HomeDomain           string            `json:"home_domain,omitempty"`
 	LastModifiedLedger   uint32            `json:"last_modified_ledger"`
 	LastModifiedTime     *time.Time        `json:"last_modified_time"`
 	Thresholds           AccountThresholds `json:"thresholds"`
+	MasterKeyWeight      int32             `json:"master_key_weight"`
+	MasterKeyDisabled    bool              `json:"master_key_disabled,omitempty"`
 	Flags                AccountFlags      `json:"flags"`
 	Balances             []Balance         `json:"balances"`
 	Signers              []Signer          `json:"signers"`
@@ -53,7 +57,66 @@ type Account struct {
 	NumSponsoring        uint32            `json:"num_sponsoring"`
 	NumSponsored         uint32            `json:"num_sponsored"`
 	Sponsor              string            `json:"sponsor,omitempty"`
+	Sponsoring           []SponsoredSigner `json:"sponsoring,omitempty"`
+	Summary              *AccountSummary   `json:"summary,omitempty"`
+	MinBalanceStroops    string            `json:"min_balance_stroops,omitempty"`
+	IsImmutable          bool              `json:"is_immutable"`
+	IssuedAssets         []Asset           `json:"issued_assets,omitempty"`
+	CreatedAtOperationID string            `json:"created_at_operation_id,omitempty"`
+	CreatedLedger        int32             `json:"created_ledger,omitempty"`
+	OffersCount          *int32            `json:"offers_count,omitempty"`
+	Partial              bool              `json:"partial,omitempty"`
+	Warnings             []string          `json:"warnings,omitempty"`
 	PT                   string            `json:"paging_token"`
+	Embedded             *AccountEmbedded  `json:"_embedded,omitempty"`
+}
+
+// AccountSummary counts the sub-entries of an account, it is included instead
+// of the signers and the data of the account on request
+type AccountSummary struct {
+	NumSigners    int `json:"num_signers"`
+	NumTrustLines int `json:"num_trustlines"`
+	NumData       int `json:"num_data"`
+}
+
+// AccountActivitySummary is a compact summary of an account, counting its
+// sub-entries instead of including them
+type AccountActivitySummary struct {
+	AccountID     string       `json:"account_id"`
+	NumBalances   int          `json:"num_balances"`
+	NativeBalance string       `json:"native_balance"`
+	NumSigners    int          `json:"num_signers"`
+	NumData       int          `json:"num_data"`
+	Flags         AccountFlags `json:"flags"`
+	// LastActivityLedger is the last ledger in which the account, one of its
+	// trust lines or one of its data entries changed.
+	LastActivityLedger uint32 `json:"last_activity_ledger"`
+}
+
+// SponsoredSigner is a signer of another account whose reserve is paid by
+// the sponsoring account
+type SponsoredSigner struct {
+	AccountID string `json:"account_id"`
+	Key       string `json:"key"`
+}
+
+// AccountEmbedded contains the resources embedded in an account on request
+type AccountEmbedded struct {
+	InflationDestination *Account       `json:"inflation_destination,omitempty"`
+	RecentSignerChanges  []SignerChange `json:"recent_signer_changes,omitempty"`
+	// CycleDetected is set when the inflation destination is not embedded
+	// because it is already embedded higher up in the chain.
+	CycleDetected bool `json:"cycle_detected,omitempty"`
+}
+
+// SignerChange is a signer of an account created, updated or removed by an
+// operation
+type SignerChange struct {
+	Type        string `json:"type"`
+	Key         string `json:"key"`
+	Weight      int32  `json:"weight"`
+	OperationID string `json:"operation_id"`
+	Ledger      int32  `json:"ledger"`
 }
 
 // PagingToken implementation for hal.Pageable
@@ -140,6 +203,33 @@ func (a *Account) SignerSummary() map[string]int32 {
 	return m
 }
 
+// IsThresholdWeak returns true when the medium or the high threshold of the
+// account is lower than the weight of the master key, so the master key
+// alone can bypass them.
+func (a Account) IsThresholdWeak() bool {
+	masterWeight := a.SignerSummary()[a.AccountID]
+	return int32(a.Thresholds.MedThreshold) < masterWeight ||
+		int32(a.Thresholds.HighThreshold) < masterWeight
+}
+
+// MarshalJSON implements a custom marshaler for Account.
+// The data field is omitted if and only if Data is nil, an account
+// without data entries is rendered with an empty object otherwise.
+func (a Account) MarshalJSON() ([]byte, error) {
+	type Alias Account
+	type withData struct {
+		Data *map[string]string `json:"data,omitempty"`
+		*Alias
+	}
+	v := &withData{
+		Alias: (*Alias)(&a),
+	}
+	if a.Data != nil {
+		v.Data = &a.Data
+	}
+	return json.Marshal(v)
+}
+
 // AccountFlags represents the state of an account's flags
 type AccountFlags struct {
 	AuthRequired        bool `json:"auth_required"`
@@ -148,6 +238,13 @@ type AccountFlags struct {
 	AuthClawbackEnabled bool `json:"auth_clawback_enabled"`
 }
 
+// TrustLineFlags represents the state of a trust line's flags
+type TrustLineFlags struct {
+	Authorized                      bool `json:"authorized"`
+	AuthorizedToMaintainLiabilities bool `json:"authorized_to_maintain_liabilities"`
+	ClawbackEnabled                 bool `json:"clawback_enabled"`
+}
+
 // AccountThresholds represents an accounts "thresholds", the numerical values
 // needed to satisfy the authorization of a given operation.
 type AccountThresholds struct {
@@ -199,18 +296,57 @@ type AssetStatAccounts struct {
 
 // Balance represents an account's holdings for a single currency type
 type Balance struct {
-	Balance                           string `json:"balance"`
-	Limit                             string `json:"limit,omitempty"`
-	BuyingLiabilities                 string `json:"buying_liabilities"`
-	SellingLiabilities                string `json:"selling_liabilities"`
+	Balance string `json:"balance"`
+	Limit   string `json:"limit,omitempty"`
+	// BuyingLiabilities and SellingLiabilities are always present. Accounts
+	// and trust lines without the liabilities extension (V0 entries) report
+	// "0.0000000", since horizon doesn't record the entry version.
+	BuyingLiabilities  string `json:"buying_liabilities"`
+	SellingLiabilities string `json:"selling_liabilities"`
+	// BuyingLiabilitiesStroops and SellingLiabilitiesStroops are the
+	// liabilities as integer amounts of stroops, encoded as strings.
+	BuyingLiabilitiesStroops          string `json:"buying_liabilities_stroops"`
+	SellingLiabilitiesStroops         string `json:"selling_liabilities_stroops"`
 	Sponsor                           string `json:"sponsor,omitempty"`
 	LastModifiedLedger                uint32 `json:"last_modified_ledger,omitempty"`
 	IsAuthorized                      *bool  `json:"is_authorized,omitempty"`
 	IsAuthorizedToMaintainLiabilities *bool  `json:"is_authorized_to_maintain_liabilities,omitempty"`
 	IsClawbackEnabled                 *bool  `json:"is_clawback_enabled,omitempty"`
+	// Flags are the flags of the trust line, each flag decoded separately
+	// unlike is_authorized_to_maintain_liabilities which is also set on
+	// authorized trust lines. It is nil for the native balance.
+	Flags *TrustLineFlags `json:"flags,omitempty"`
+	// IsDisabled is set on trust lines with a zero limit and a zero
+	// balance, which can't hold the asset anymore. It is nil for the native
+	// balance.
+	IsDisabled *bool `json:"is_disabled,omitempty"`
+	// IsAtLimit is set on trust lines which can't receive more of the asset:
+	// the balance plus the buying liabilities reach the limit. It is nil for
+	// the native balance.
+	IsAtLimit *bool `json:"is_at_limit,omitempty"`
+	// AuthorizedLedger is the last ledger in which the trust line was
+	// authorized, it is omitted if the authorization was never recorded.
+	AuthorizedLedger uint32 `json:"authorized_ledger,omitempty"`
+	// IssuerFlags are the flags of the issuer of the asset, included on
+	// request.
+	IssuerFlags *AccountFlags `json:"issuer_flags,omitempty"`
+	// ReserveStroops is the base reserve paid by the sponsor of the trust
+	// line, included on request.
+	ReserveStroops string `json:"reserve_stroops,omitempty"`
 	base.Asset
 }
 
+// AccountBalance is a balance in the collection of balances of an account
+type AccountBalance struct {
+	Balance
+	PT string `json:"paging_token"`
+}
+
+// PagingToken implementation for hal.Pageable
+func (res AccountBalance) PagingToken() string {
+	return res.PT
+}
+
 // Ledger represents a single closed ledger
 type Ledger struct {
 	Links struct {
@@ -349,6 +485,14 @@ type Signer struct {
 	Key     string `json:"key"`
 	Type    string `json:"type"`
 	Sponsor string `json:"sponsor,omitempty"`
+	// HashHex is the hex encoded hash of sha256_hash signers.
+	HashHex string `json:"hash_hex,omitempty"`
+	// PreauthTxHashHex is the hex encoded transaction hash of preauth_tx
+	// signers.
+	PreauthTxHashHex string `json:"preauth_tx_hash_hex,omitempty"`
+	// ReserveStroops is the base reserve paid by the sponsor of the signer,
+	// included on request.
+	ReserveStroops string `json:"reserve_stroops,omitempty"`
 }
 
 // Trade represents a horizon digested trade
@@ -590,6 +734,89 @@ type AccountData struct {
 	Sponsor string `json:"sponsor,omitempty"`
 }
 
+// AccountDataEntry represents a data entry of an account in the pages of the
+// /accounts/{account_id}/data end-point
+type AccountDataEntry struct {
+	Name               string `json:"name"`
+	Value              string `json:"value"`
+	Size               int    `json:"size"`
+	LastModifiedLedger uint32 `json:"last_modified_ledger"`
+	Sponsor            string `json:"sponsor,omitempty"`
+	// ReserveStroops is the base reserve paid by the sponsor of the data
+	// entry, included on request.
+	ReserveStroops string `json:"reserve_stroops,omitempty"`
+	PT             string `json:"paging_token"`
+}
+
+// PagingToken implementation for hal.Pageable
+func (res AccountDataEntry) PagingToken() string {
+	return res.PT
+}
+
+// TrustLineAuthorization represents the authorization flags of a trust line
+// set in a given ledger
+type TrustLineAuthorization struct {
+	Links struct {
+		Ledger hal.Link `json:"ledger"`
+	} `json:"_links"`
+	PT                                string `json:"paging_token"`
+	Ledger                            uint32 `json:"ledger"`
+	AccountID                         string `json:"account_id"`
+	IsAuthorized                      bool   `json:"is_authorized"`
+	IsAuthorizedToMaintainLiabilities bool   `json:"is_authorized_to_maintain_liabilities"`
+	base.Asset
+}
+
+// PagingToken implementation for hal.Pageable
+func (res TrustLineAuthorization) PagingToken() string {
+	return res.PT
+}
+
+// TrustLineCountHistogram represents the number of accounts by the number of
+// trust lines they hold
+type TrustLineCountHistogram struct {
+	Buckets []TrustLineCountBucket `json:"buckets"`
+}
+
+// TrustLineCountBucket is the number of accounts holding a number of trust
+// lines in the range of the bucket, like "2-10"
+type TrustLineCountBucket struct {
+	Bucket   string `json:"bucket"`
+	Accounts int64  `json:"accounts"`
+}
+
+// AccountThresholdSimulation represents the operations a set of signers can
+// authorize on an account, given the weights of the signers and the
+// thresholds of the account
+type AccountThresholdSimulation struct {
+	AccountID     string `json:"account_id"`
+	Weight        int32  `json:"weight"`
+	AuthorizeLow  bool   `json:"authorize_low"`
+	AuthorizeMed  bool   `json:"authorize_med"`
+	AuthorizeHigh bool   `json:"authorize_high"`
+}
+
+// AccountIDPrefixMatches represents the ids of the accounts starting with a
+// prefix
+type AccountIDPrefixMatches struct {
+	Prefix     string   `json:"prefix"`
+	AccountIDs []string `json:"account_ids"`
+}
+
+// TrustLineAuthStates represents the authorization state of the trust lines
+// to an asset held by a list of accounts
+type TrustLineAuthStates struct {
+	Asset  string               `json:"asset"`
+	States []TrustLineAuthState `json:"states"`
+}
+
+// TrustLineAuthState is the authorization state of the trust line of an
+// account: "authorized", "authorized_to_maintain_liabilities" or "none"
+type TrustLineAuthState struct {
+	AccountID string `json:"account_id"`
+	State     string `json:"state"`
+}
+
 // AccountsPage returns a list of account records
 type AccountsPage struct {
 	Links    hal.Links `json:"_links"`
@@ -48,6 +48,27 @@ func TestAccount_MustGetData(t *testing.T) {
 	assert.Panics(t, func() { exampleAccount.MustGetData("invalid") }, "panics on invalid input")
 }
 
+func TestAccountJSONMarshalData(t *testing.T) {
+	marshaled, err := json.Marshal(Account{Data: map[string]string{}})
+	assert.NoError(t, err)
+	assert.Contains(t, string(marshaled), `"data":{}`)
+
+	marshaled, err = json.Marshal(exampleAccount)
+	assert.NoError(t, err)
+	assert.Contains(t, string(marshaled), `"data":{"invalid":"a_*\u0026^*","test":"aGVsbG8="}`)
+	var result Account
+	assert.NoError(t, json.Unmarshal(marshaled, &result))
+	assert.Equal(t, exampleAccount.Data, result.Data)
+
+	// nil data is omitted, only the data link is left
+	marshaled, err = json.Marshal(Account{})
+	assert.NoError(t, err)
+	var fields map[string]json.RawMessage
+	assert.NoError(t, json.Unmarshal(marshaled, &fields))
+	assert.NotContains(t, fields, "data")
+	assert.Contains(t, fields, "_links")
+}
+
 // Transaction Tests
 func TestTransactionJSONMarshal(t *testing.T) {
 	transaction := Transaction{
@@ -238,3 +259,35 @@ func TestTradeAggregation_PagingToken(t *testing.T) {
 	ta := TradeAggregation{Timestamp: 64}
 	assert.Equal(t, "64", ta.PagingToken())
 }
+
+func TestAccount_IsThresholdWeak(t *testing.T) {
+	// account1's thresholds {1, 2, 3, 4}: master weight 1, low 2, med 3, high 4
+	account := Account{
+		AccountID: "GAOQJGUAB7NI7K7I62ORBXMN3J4SSWQUQ7FOEPSDJ322W2HMCNWPHXFB",
+		Thresholds: AccountThresholds{
+			LowThreshold:  2,
+			MedThreshold:  3,
+			HighThreshold: 4,
+		},
+		Signers: []Signer{
+			{Key: "GAOQJGUAB7NI7K7I62ORBXMN3J4SSWQUQ7FOEPSDJ322W2HMCNWPHXFB", Weight: 1},
+		},
+	}
+	assert.False(t, account.IsThresholdWeak())
+
+	account.Signers[0].Weight = 3
+	assert.False(t, account.IsThresholdWeak(), "master weight equal to the medium threshold")
+
+	account.Signers[0].Weight = 4
+	assert.True(t, account.IsThresholdWeak(), "medium threshold is lower")
+
+	account.Signers[0].Weight = 5
+	assert.True(t, account.IsThresholdWeak())
+
+	account.Thresholds.HighThreshold = 5
+	assert.True(t, account.IsThresholdWeak(), "medium threshold is still lower")
+
+	// without the master key there is nothing to bypass the thresholds
+	account.Signers = nil
+	assert.False(t, account.IsThresholdWeak())
+}
@@ -2,58 +2,82 @@ package actions
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"math"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	protocol "github.com/stellar/go/protocols/horizon"
 	horizonContext "github.com/stellar/go/services/horizon/internal/context"
+	"github.com/stellar/go/services/horizon/internal/db2"
 	"github.com/stellar/go/services/horizon/internal/db2/history"
 	"github.com/stellar/go/services/horizon/internal/ledger"
 	"github.com/stellar/go/services/horizon/internal/resourceadapter"
 	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/support/log"
 	"github.com/stellar/go/support/render/hal"
+	"github.com/stellar/go/support/render/httpjson"
 	"github.com/stellar/go/support/render/problem"
 	"github.com/stellar/go/xdr"
 )
 
 // AccountInfo returns the information about an account identified by addr.
 func AccountInfo(ctx context.Context, hq *history.Q, addr string) (*protocol.Account, error) {
+	account, _, err := accountInfo(ctx, hq, addr, true)
+	if err != nil {
+		return nil, err
+	}
+	return &account.Account, nil
+}
+
+// accountInfo returns the information about an account identified by addr
+// and the last ledger in which the account or one of the loaded sub-entries
+// changed. Without withTrustLines, the trust lines of the account are not
+// loaded and its only balance is the native balance.
+func accountInfo(ctx context.Context, hq *history.Q, addr string, withTrustLines bool) (*Account, uint32, error) {
 	var (
 		record     history.AccountEntry
 		data       []history.Data
 		signers    []history.AccountSigner
 		trustlines []history.TrustLine
-		resouce    protocol.Account
+		resouce    Account
 	)
 
 	record, err := hq.GetAccountByID(ctx, addr)
 	if err != nil {
-		return nil, errors.Wrap(err, "getting history account record")
+		return nil, 0, errors.Wrap(err, "getting history account record")
 	}
 
 	data, err = hq.GetAccountDataByAccountID(ctx, addr)
 	if err != nil {
-		return nil, errors.Wrap(err, "getting history account data")
+		return nil, 0, errors.Wrap(err, "getting history account data")
 	}
 
 	signers, err = hq.GetAccountSignersByAccountID(ctx, addr)
 	if err != nil {
-		return nil, errors.Wrap(err, "getting history signers")
+		return nil, 0, errors.Wrap(err, "getting history signers")
 	}
 
-	trustlines, err = hq.GetSortedTrustLinesByAccountID(ctx, addr)
-	if err != nil {
-		return nil, errors.Wrap(err, "getting history trustlines")
+	if withTrustLines {
+		trustlines, err = hq.GetSortedTrustLinesByAccountID(ctx, addr)
+		if err != nil {
+			return nil, 0, errors.Wrap(err, "getting history trustlines")
+		}
 	}
 
-	ledger, err := getLedgerBySequence(ctx, hq, int32(record.LastModifiedLedger))
-	if err != nil {
-		return nil, err
+	ledgerCache := history.LedgerCache{}
+	ledgerCache.Queue(int32(record.LastModifiedLedger))
+	if err = ledgerCache.Load(ctx, hq); err != nil {
+		return nil, 0, errors.Wrap(err, "failed to load ledger batch")
 	}
 
+	ledger := lastModifiedLedger(&ledgerCache, record)
 	err = resourceadapter.PopulateAccountEntry(
 		ctx,
-		&resouce,
+		&resouce.Account,
 		record,
 		data,
 		signers,
@@ -61,10 +85,94 @@ func AccountInfo(ctx context.Context, hq *history.Q, addr string) (*protocol.Acc
 		ledger,
 	)
 	if err != nil {
-		return nil, errors.Wrap(err, "populating account entry")
+		return nil, 0, errors.Wrap(err, "populating account entry")
+	}
+	resouce.flagsPredateClawback = resourceadapter.AccountFlagsPredateClawback(ledger)
+
+	// the signers are part of the account entry, so only the data entries
+	// and the trust lines can change after it
+	lastActivityLedger := record.LastModifiedLedger
+	for _, entry := range data {
+		if entry.LastModifiedLedger > lastActivityLedger {
+			lastActivityLedger = entry.LastModifiedLedger
+		}
+	}
+	for _, trustline := range trustlines {
+		if trustline.LastModifiedLedger > lastActivityLedger {
+			lastActivityLedger = trustline.LastModifiedLedger
+		}
+	}
+
+	return &resouce, lastActivityLedger, nil
+}
+
+// AccountsInfo returns the information about the accounts identified by addrs,
+// keyed by address. Unlike calling AccountInfo for each address, every kind of
+// record is loaded for all the accounts in a single query. Addresses of
+// accounts which don't exist are absent from the result. When some addresses
+// aren't valid account IDs, a single bad request problem listing all of them
+// is returned.
+func AccountsInfo(ctx context.Context, hq *history.Q, addrs []string) (map[string]*protocol.Account, error) {
+	accounts := make(map[string]*protocol.Account, len(addrs))
+	if len(addrs) == 0 {
+		return accounts, nil
+	}
+	if fieldErrors := validateAccountIDs(addrs); len(fieldErrors) > 0 {
+		return nil, invalidAccountIDsProblem("accounts", fieldErrors)
+	}
+
+	records, err := hq.GetAccountsByIDs(ctx, addrs)
+	if err != nil {
+		return nil, errors.Wrap(err, "getting history account records")
+	}
+	if len(records) == 0 {
+		return accounts, nil
+	}
+
+	ids := make([]string, 0, len(records))
+	ledgerCache := history.LedgerCache{}
+	for _, record := range records {
+		ids = append(ids, record.AccountID)
+		ledgerCache.Queue(int32(record.LastModifiedLedger))
+	}
+	if err = ledgerCache.Load(ctx, hq); err != nil {
+		return nil, errors.Wrap(err, "failed to load ledger batch")
+	}
+
+	// the sub-entries are grouped by account like the accounts page does
+	var loader GetAccountsHandler
+	data, err := loader.loadData(ctx, hq, ids)
+	if err != nil {
+		return nil, err
+	}
+	signers, err := loader.loadSigners(ctx, hq, ids)
+	if err != nil {
+		return nil, err
+	}
+	trustlines, err := loader.loadTrustlines(ctx, hq, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, record := range records {
+		var resource protocol.Account
+		err = resourceadapter.PopulateAccountEntry(
+			ctx,
+			&resource,
+			record,
+			data[record.AccountID],
+			signers[record.AccountID],
+			trustlines[record.AccountID],
+			lastModifiedLedger(&ledgerCache, record),
+		)
+		if err != nil {
+			return nil, errors.Wrap(err, "populating account entry")
+		}
+
+		accounts[record.AccountID] = &resource
 	}
 
-	return &resouce, nil
+	return accounts, nil
 }
 
 // AccountsQuery query struct for accounts end-point
@@ -72,8 +180,69 @@ type AccountsQuery struct {
 	Signer      string `schema:"signer" valid:"accountID,optional"`
 	Sponsor     string `schema:"sponsor" valid:"accountID,optional"`
 	AssetFilter string `schema:"asset" valid:"asset,optional"`
+	// AssetsFilter matches the accounts holding any or all (depending on
+	// Match) of the assets.
+	AssetsFilter []string `schema:"assets" valid:"-"`
+	Match        string   `schema:"match" valid:"in(any|all)~Accepted values: any or all,optional"`
+	// WeakThresholds matches the accounts whose medium or high threshold is
+	// lower than the weight of the master key.
+	WeakThresholds bool `schema:"weak_thresholds" valid:"-"`
+	// HasLiabilities matches the accounts with nonzero buying or selling
+	// liabilities, i.e. with open offers.
+	HasLiabilities bool `schema:"has_liabilities" valid:"-"`
+	// NoHomeDomain matches the accounts whose home domain is unset, like
+	// issuers not claiming a domain.
+	NoHomeDomain bool `schema:"no_home_domain" valid:"-"`
+	// HomeDomain matches the accounts whose home domain is exactly the
+	// given one.
+	HomeDomain string `schema:"home_domain" valid:"-"`
+	// SignerType matches the accounts with at least one signer of the type,
+	// like preauth_tx.
+	SignerType string `schema:"signer_type" valid:"-"`
+	// Threshold matches the accounts whose low, med or high threshold
+	// compares to ThresholdValue with ThresholdOp, gte or lte.
+	Threshold      string `schema:"threshold" valid:"in(low|med|high)~Accepted values: low; med or high,optional"`
+	ThresholdOp    string `schema:"threshold_op" valid:"in(gte|lte)~Accepted values: gte or lte,optional"`
+	ThresholdValue uint8  `schema:"threshold_value" valid:"-"`
+	// DataName and DataValue match the accounts holding a data entry with
+	// the name and the value, encoded in base64.
+	DataName  string `schema:"data_name" valid:"-"`
+	DataValue string `schema:"data_value" valid:"-"`
+	// ModifiedFrom and ModifiedTo match the accounts last modified in a
+	// ledger between them, both included. Either bound can be left out.
+	ModifiedFrom uint32 `schema:"modified_from" valid:"-"`
+	ModifiedTo   uint32 `schema:"modified_to" valid:"-"`
+	// OnlyMatchingAsset restricts the balances included in every account to
+	// the native balance and the balance of the asset in the filter.
+	OnlyMatchingAsset bool `schema:"only_matching_asset" valid:"-"`
+	// ZeroBalance restricts the asset filter to the accounts holding none of
+	// the asset, the ones which opted in but weren't funded yet.
+	ZeroBalance bool `schema:"zero_balance" valid:"-"`
+	// Top lists the given number of accounts holding the largest balances
+	// of the asset in the filter, largest first, instead of paging them.
+	Top uint64 `schema:"top" valid:"-"`
+	// AllowPartial returns the accounts with empty sub-resources instead of
+	// failing the request when signers, trustlines or data can't be loaded.
+	AllowPartial bool `schema:"allow_partial" valid:"-"`
+	// OmitEmpty leaves the empty sub-resources out of every account.
+	OmitEmpty bool `schema:"omit_empty" valid:"-"`
+	// Sort lists all the accounts sorted by the given field instead of
+	// filtering them.
+	Sort string `schema:"sort" valid:"in(native_balance)~Accepted values: native_balance,optional"`
+	// Summary counts the signers, trust lines and data entries of every
+	// account instead of loading them.
+	Summary bool `schema:"summary" valid:"-"`
+	// StrictParams rejects the requests with unrecognized query parameters,
+	// like typos of the filters, instead of ignoring them.
+	StrictParams bool `schema:"strict_params" valid:"-"`
 }
 
+// accountsSortNativeBalance sorts the accounts by native balance.
+const accountsSortNativeBalance = "native_balance"
+
+// maxTopHolders caps the number of accounts listed by the top parameter.
+const maxTopHolders = 200
+
 // URITemplate returns a rfc6570 URI template the query struct
 func (q AccountsQuery) URITemplate() string {
 	return "/accounts{?" + strings.Join(getURIParams(&q, true), ",") + "}"
@@ -95,14 +264,162 @@ func (q AccountsQuery) Validate() error {
 		)
 	}
 
+	for _, asset := range q.AssetsFilter {
+		if !isAsset(asset) {
+			return problem.MakeInvalidFieldProblem(
+				"assets",
+				errors.New(customTagsErrorMessages["asset"]),
+			)
+		}
+		if strings.ToLower(asset) == "native" {
+			return problem.MakeInvalidFieldProblem(
+				"assets",
+				errors.New("you can't filter by asset: native"),
+			)
+		}
+	}
+
 	numParams, err := countNonEmpty(q.Sponsor, q.Signer, q.Asset())
 	if err != nil {
 		return errors.Wrap(err, "Could not count request params")
 	}
-	if numParams != 1 {
+	if len(q.AssetsFilter) > 0 {
+		numParams++
+	}
+	if q.WeakThresholds {
+		numParams++
+	}
+	if q.HasLiabilities {
+		numParams++
+	}
+	if q.NoHomeDomain {
+		numParams++
+	}
+	if len(q.HomeDomain) > 0 {
+		numParams++
+	}
+	if len(q.SignerType) > 0 {
+		numParams++
+	}
+	if len(q.Threshold) > 0 {
+		numParams++
+	}
+	if len(q.DataName) > 0 {
+		numParams++
+	}
+	if q.ModifiedFrom > 0 || q.ModifiedTo > 0 {
+		numParams++
+	}
+	if q.Sort == accountsSortNativeBalance {
+		if numParams != 0 {
+			return problem.MakeInvalidFieldProblem(
+				"sort",
+				errors.New("sort can't be combined with a filter"),
+			)
+		}
+	} else if numParams != 1 {
 		return invalidAccountsParams
 	}
 
+	if q.OnlyMatchingAsset && len(q.AssetFilter) == 0 {
+		return problem.MakeInvalidFieldProblem(
+			"only_matching_asset",
+			errors.New("only_matching_asset can only be used with the asset filter"),
+		)
+	}
+
+	if q.ZeroBalance && len(q.AssetFilter) == 0 {
+		return problem.MakeInvalidFieldProblem(
+			"zero_balance",
+			errors.New("zero_balance can only be used with the asset filter"),
+		)
+	}
+
+	if q.Top > 0 {
+		if len(q.AssetFilter) == 0 {
+			return problem.MakeInvalidFieldProblem(
+				"top",
+				errors.New("top can only be used with the asset filter"),
+			)
+		}
+		if q.Top > maxTopHolders {
+			return problem.MakeInvalidFieldProblem(
+				"top",
+				errors.Errorf("top must not be greater than %d", maxTopHolders),
+			)
+		}
+		if q.ZeroBalance {
+			return problem.MakeInvalidFieldProblem(
+				"top",
+				errors.New("top can't be used with zero_balance"),
+			)
+		}
+	}
+
+	if len(q.Threshold) > 0 && len(q.ThresholdOp) == 0 {
+		return problem.MakeInvalidFieldProblem(
+			"threshold_op",
+			errors.New("threshold_op is required by the threshold filter"),
+		)
+	}
+
+	if len(q.ThresholdOp) > 0 && len(q.Threshold) == 0 {
+		return problem.MakeInvalidFieldProblem(
+			"threshold_op",
+			errors.New("threshold_op can only be used with the threshold filter"),
+		)
+	}
+
+	if len(q.DataName) > 0 && len(q.DataValue) == 0 {
+		return problem.MakeInvalidFieldProblem(
+			"data_value",
+			errors.New("data_value is required by the data_name filter"),
+		)
+	}
+
+	if len(q.DataValue) > 0 {
+		if len(q.DataName) == 0 {
+			return problem.MakeInvalidFieldProblem(
+				"data_value",
+				errors.New("data_value can only be used with the data_name filter"),
+			)
+		}
+		if _, err = base64.StdEncoding.DecodeString(q.DataValue); err != nil {
+			return problem.MakeInvalidFieldProblem(
+				"data_value",
+				errors.New("data_value must be base64 encoded"),
+			)
+		}
+	}
+
+	if len(q.SignerType) > 0 && !isValidSignerType(q.SignerType) {
+		return problem.MakeInvalidFieldProblem(
+			"signer_type",
+			errors.Errorf("unknown signer type: %s", q.SignerType),
+		)
+	}
+
+	if len(q.Match) > 0 && len(q.AssetsFilter) == 0 {
+		return problem.MakeInvalidFieldProblem(
+			"match",
+			errors.New("match can only be used with the assets filter"),
+		)
+	}
+
+	if q.ModifiedTo > 0 && q.ModifiedFrom > q.ModifiedTo {
+		return problem.MakeInvalidFieldProblem(
+			"modified_from",
+			errors.New("modified_from must not be greater than modified_to"),
+		)
+	}
+
+	if q.Summary && q.OnlyMatchingAsset {
+		return problem.MakeInvalidFieldProblem(
+			"only_matching_asset",
+			errors.New("only_matching_asset can't be used with summary"),
+		)
+	}
+
 	return nil
 }
 
@@ -118,9 +435,219 @@ func (q AccountsQuery) Asset() *xdr.Asset {
 	return &asset
 }
 
+// Assets returns the assets of the assets filter.
+func (q AccountsQuery) Assets() []xdr.Asset {
+	assets := make([]xdr.Asset, 0, len(q.AssetsFilter))
+	for _, filter := range q.AssetsFilter {
+		parts := strings.Split(filter, ":")
+		assets = append(assets, xdr.MustNewCreditAsset(parts[0], parts[1]))
+	}
+
+	return assets
+}
+
+// ModifiedToOrLatest returns the upper bound of the modified ledger range,
+// which is unbounded when modified_to is left out.
+func (q AccountsQuery) ModifiedToOrLatest() uint32 {
+	if q.ModifiedTo == 0 {
+		return math.MaxInt32
+	}
+	return q.ModifiedTo
+}
+
+// DataValueBytes returns the decoded value of the data_value filter.
+func (q AccountsQuery) DataValueBytes() []byte {
+	value, err := base64.StdEncoding.DecodeString(q.DataValue)
+	if err != nil {
+		panic(err)
+	}
+	return value
+}
+
+// ThresholdOperator returns the comparison operator of the threshold
+// filter.
+func (q AccountsQuery) ThresholdOperator() string {
+	if q.ThresholdOp == "lte" {
+		return "<="
+	}
+	return ">="
+}
+
+// AssetsMatchMode returns how the accounts are matched against the assets
+// filter, any by default.
+func (q AccountsQuery) AssetsMatchMode() history.AssetsMatchMode {
+	if q.Match == string(history.MatchAllAssets) {
+		return history.MatchAllAssets
+	}
+	return history.MatchAnyAsset
+}
+
+// FilterType returns the type of filter used in the query.
+func (q AccountsQuery) FilterType() AccountsFilterType {
+	switch {
+	case len(q.Sponsor) > 0:
+		return AccountsSponsorFilter
+	case len(q.Signer) > 0:
+		return AccountsSignerFilter
+	case q.WeakThresholds:
+		return AccountsWeakThresholdsFilter
+	case q.HasLiabilities:
+		return AccountsLiabilitiesFilter
+	case q.NoHomeDomain:
+		return AccountsNoHomeDomainFilter
+	case len(q.HomeDomain) > 0:
+		return AccountsHomeDomainFilter
+	case len(q.SignerType) > 0:
+		return AccountsSignerTypeFilter
+	case len(q.Threshold) > 0:
+		return AccountsThresholdFilter
+	case len(q.DataName) > 0:
+		return AccountsDataValueFilter
+	case q.ModifiedFrom > 0 || q.ModifiedTo > 0:
+		return AccountsModifiedFilter
+	case q.Sort == accountsSortNativeBalance:
+		return AccountsNativeBalanceSort
+	default:
+		return AccountsAssetFilter
+	}
+}
+
+// AccountsParams contains the validated filters and paging parameters of a
+// request to the /accounts endpoint.
+type AccountsParams struct {
+	AccountsQuery
+	PageQuery db2.PageQuery
+	// CursorNow is set when the request uses cursor=now, which for accounts
+	// means starting after the latest account id instead of a ledger.
+	CursorNow bool
+	// RowIDCursor is set when the request uses a numeric cursor, paging the
+	// accounts by their internal row id instead of their account id.
+	RowIDCursor bool
+	// Signers are the distinct values of the signer filter, which can be
+	// repeated to match the accounts having all of them.
+	Signers []string
+}
+
+// ParseAccountsParams reads and validates the filters and the paging
+// parameters of a request to the /accounts endpoint.
+func ParseAccountsParams(ledgerState *ledger.State, r *http.Request) (AccountsParams, error) {
+	pq, err := GetPageQuery(ledgerState, r, DisableCursorValidation)
+	if err != nil {
+		return AccountsParams{}, err
+	}
+
+	// an empty home_domain would be taken for a missing filter, accounts
+	// without a home domain are matched by no_home_domain instead
+	if values, ok := r.URL.Query()["home_domain"]; ok && strings.Join(values, "") == "" {
+		return AccountsParams{}, problem.MakeInvalidFieldProblem(
+			"home_domain",
+			errors.New("home_domain can't be empty, use no_home_domain=true to match accounts without a home domain"),
+		)
+	}
+
+	qp := AccountsQuery{}
+	err = getParams(&qp, r)
+	if err != nil {
+		return AccountsParams{}, err
+	}
+
+	cursor, err := getString(r, ParamCursor)
+	if err != nil {
+		return AccountsParams{}, err
+	}
+
+	values, err := getStringSlice(r, "signer")
+	if err != nil {
+		return AccountsParams{}, err
+	}
+	if fieldErrors := validateAccountIDs(values); len(fieldErrors) > 0 {
+		return AccountsParams{}, invalidAccountIDsProblem("signer", fieldErrors)
+	}
+	var signers []string
+	seen := map[string]bool{}
+	for _, signer := range values {
+		if !seen[signer] {
+			seen[signer] = true
+			signers = append(signers, signer)
+		}
+	}
+
+	if qp.Sort == accountsSortNativeBalance && cursor != "" {
+		if cursor == "now" {
+			return AccountsParams{}, problem.MakeInvalidFieldProblem(
+				ParamCursor,
+				errors.New("cursor=now can't be used with sort"),
+			)
+		}
+		if _, _, err = history.ParseNativeBalanceCursor(pq.Cursor); err != nil {
+			return AccountsParams{}, problem.MakeInvalidFieldProblem(ParamCursor, err)
+		}
+	}
+
+	// the signer and asset filters can also page by row id
+	_, rowIDCursor := history.ParseAccountRowIDCursor(cursor)
+	if rowIDCursor && len(qp.Signer) == 0 && len(qp.AssetFilter) == 0 {
+		return AccountsParams{}, problem.MakeInvalidFieldProblem(
+			ParamCursor,
+			errors.New("numeric cursors can only be used with the signer and asset filters"),
+		)
+	}
+	if rowIDCursor && len(signers) > 1 {
+		return AccountsParams{}, problem.MakeInvalidFieldProblem(
+			ParamCursor,
+			errors.New("numeric cursors can't be used with multiple signers"),
+		)
+	}
+	if rowIDCursor && qp.Top > 0 {
+		return AccountsParams{}, problem.MakeInvalidFieldProblem(
+			ParamCursor,
+			errors.New("numeric cursors can't be used with top"),
+		)
+	}
+
+	// the signer filter pages by account id, a cursor which isn't one would
+	// silently return an empty or arbitrary page
+	if len(qp.Signer) > 0 && cursor != "" && cursor != "now" && !rowIDCursor {
+		if err = validateAccountStrkey(cursor); err != nil {
+			return AccountsParams{}, problem.MakeInvalidFieldProblem(ParamCursor, err)
+		}
+	}
+
+	return AccountsParams{
+		AccountsQuery: qp,
+		PageQuery:     pq,
+		CursorNow:     cursor == "now" && r.Header.Get("Last-Event-ID") == "",
+		RowIDCursor:   rowIDCursor,
+		Signers:       signers,
+	}, nil
+}
+
 // GetAccountsHandler is the action handler for the /accounts endpoint
 type GetAccountsHandler struct {
 	LedgerState *ledger.State
+	// FilterRateLimiter is optional, when set requests are throttled
+	// depending on the filter type.
+	FilterRateLimiter AccountsFilterRateLimiter
+	// StaleThreshold is the number of ledgers the history database may lag
+	// behind stellar-core before the results are flagged with the
+	// X-History-Stale header. They are never flagged when it is 0.
+	StaleThreshold uint
+	// StrictParams rejects the requests with unrecognized query parameters
+	// even when they don't set strict_params.
+	StrictParams bool
+}
+
+// setHistoryStaleHeader flags the response as stale when ingestion into the
+// history database is more than staleThreshold ledgers behind stellar-core.
+// Unlike the history middleware, the results are still served.
+func setHistoryStaleHeader(w HeaderWriter, ledgerState *ledger.State, staleThreshold uint) {
+	if ledgerState == nil || staleThreshold == 0 {
+		return
+	}
+	status := ledgerState.CurrentStatus()
+	if status.CoreLatest-status.HistoryLatest > int32(staleThreshold) {
+		w.Header().Set(HistoryStaleHeaderName, "true")
+	}
 }
 
 // GetResourcePage returns a page containing the account records that have
@@ -130,66 +657,148 @@ func (handler GetAccountsHandler) GetResourcePage(
 	r *http.Request,
 ) ([]hal.Pageable, error) {
 	ctx := r.Context()
-	pq, err := GetPageQuery(handler.LedgerState, r, DisableCursorValidation)
+	strict, err := getBool(r, "strict_params")
 	if err != nil {
 		return nil, err
 	}
+	if strict || handler.StrictParams {
+		if err = checkUnknownParams(r, &AccountsQuery{}, true); err != nil {
+			return nil, err
+		}
+	}
 
-	historyQ, err := horizonContext.HistoryQFromRequest(r)
+	params, err := ParseAccountsParams(handler.LedgerState, r)
+	if err != nil {
+		return nil, localizeProblem(r, err)
+	}
+	qp, pq := params.AccountsQuery, params.PageQuery
+
+	setHistoryStaleHeader(w, handler.LedgerState, handler.StaleThreshold)
+
+	err = checkAccountsFilterRateLimit(handler.FilterRateLimiter, w, r, qp.FilterType())
 	if err != nil {
 		return nil, err
 	}
 
-	qp := AccountsQuery{}
-	err = getParams(&qp, r)
+	historyQ, err := horizonContext.HistoryQFromRequest(r)
 	if err != nil {
 		return nil, err
 	}
 
+	if params.CursorNow {
+		// GetPageQuery translates "now" into a ledger based cursor which
+		// doesn't apply to account ids.
+		pq.Cursor, err = historyQ.LatestAccountID(ctx)
+		if err != nil {
+			return nil, historyUnavailableProblem(errors.Wrap(err, "loading latest account id"))
+		}
+		// the links of the page are built from the cursor of the request,
+		// replace "now" so they continue from the latest account id
+		query := r.URL.Query()
+		query.Set(ParamCursor, pq.Cursor)
+		r.URL.RawQuery = query.Encode()
+	}
+
 	var records []history.AccountEntry
 
-	if len(qp.Sponsor) > 0 {
+	if qp.Sort == accountsSortNativeBalance {
+		records, err = historyQ.AccountsByNativeBalance(ctx, pq)
+	} else if len(qp.Sponsor) > 0 {
 		records, err = historyQ.AccountsForSponsor(ctx, qp.Sponsor, pq)
-		if err != nil {
-			return nil, errors.Wrap(err, "loading account records")
-		}
+	} else if len(params.Signers) > 1 {
+		records, err = historyQ.AccountsForAllSigners(ctx, params.Signers, pq)
 	} else if len(qp.Signer) > 0 {
 		records, err = historyQ.AccountEntriesForSigner(ctx, qp.Signer, pq)
-		if err != nil {
-			return nil, errors.Wrap(err, "loading account records")
-		}
+	} else if len(qp.AssetsFilter) > 0 {
+		records, err = historyQ.AccountsForAssets(ctx, qp.Assets(), qp.AssetsMatchMode(), pq)
+	} else if qp.WeakThresholds {
+		records, err = historyQ.AccountsWithWeakThresholds(ctx, pq)
+	} else if qp.HasLiabilities {
+		records, err = historyQ.AccountsWithLiabilities(ctx, pq)
+	} else if qp.NoHomeDomain {
+		records, err = historyQ.AccountsWithoutHomeDomain(ctx, pq)
+	} else if len(qp.HomeDomain) > 0 {
+		records, err = historyQ.AccountsForHomeDomain(ctx, qp.HomeDomain, pq)
+	} else if len(qp.SignerType) > 0 {
+		records, err = historyQ.AccountsWithSignerType(ctx, qp.SignerType, pq)
+	} else if len(qp.Threshold) > 0 {
+		records, err = historyQ.AccountsWithThreshold(ctx, qp.Threshold, qp.ThresholdOperator(), int(qp.ThresholdValue), pq)
+	} else if len(qp.DataName) > 0 {
+		records, err = historyQ.AccountsWithDataValue(ctx, qp.DataName, qp.DataValueBytes(), pq)
+	} else if qp.ModifiedFrom > 0 || qp.ModifiedTo > 0 {
+		records, err = historyQ.AccountsModifiedBetween(ctx, qp.ModifiedFrom, qp.ModifiedToOrLatest(), pq)
+	} else if qp.Top > 0 {
+		records, err = historyQ.TopHoldersForAsset(ctx, *qp.Asset(), qp.Top)
+	} else if qp.ZeroBalance {
+		records, err = historyQ.AccountsForAssetWithBalance(ctx, *qp.Asset(), 0, pq)
 	} else {
 		records, err = historyQ.AccountsForAsset(ctx, *qp.Asset(), pq)
-		if err != nil {
-			return nil, errors.Wrap(err, "loading account records")
-		}
+	}
+	if err != nil {
+		return nil, historyUnavailableProblem(errors.Wrap(err, "loading account records"))
 	}
 
+	records = uniqueAccountEntries(records)
 	accounts := make([]hal.Pageable, 0, len(records))
 
 	if len(records) == 0 {
 		// early return
 		return accounts, nil
 	}
+	if r.Method == http.MethodHead {
+		// the body of HEAD responses is discarded, the accounts aren't
+		// populated once the page query succeeded
+		return accounts, nil
+	}
 
 	accountIDs := make([]string, 0, len(records))
 	for _, record := range records {
 		accountIDs = append(accountIDs, record.AccountID)
 	}
 
-	signers, err := handler.loadSigners(ctx, historyQ, accountIDs)
-	if err != nil {
-		return nil, err
-	}
+	partial := partialResponse{allowed: qp.AllowPartial}
 
-	trustlines, err := handler.loadTrustlines(ctx, historyQ, accountIDs)
-	if err != nil {
-		return nil, err
-	}
+	var (
+		signers         map[string][]history.AccountSigner
+		trustlines      map[string][]history.TrustLine
+		data            map[string][]history.Data
+		signerCounts    map[string]history.AccountSignersCount
+		trustlineCounts map[string]int
+		dataCounts      map[string]int
+	)
+	if qp.Summary {
+		signerCounts, err = historyQ.CountSignersForAccounts(ctx, accountIDs)
+		if err = partial.handle(ctx, err, "could not count signers"); err != nil {
+			return nil, err
+		}
 
-	data, err := handler.loadData(ctx, historyQ, accountIDs)
-	if err != nil {
-		return nil, err
+		trustlineCounts, err = historyQ.CountTrustLinesForAccounts(ctx, accountIDs)
+		if err = partial.handle(ctx, err, "could not count trustlines"); err != nil {
+			return nil, err
+		}
+
+		dataCounts, err = historyQ.CountDataForAccounts(ctx, accountIDs)
+		if err = partial.handle(ctx, err, "could not count data"); err != nil {
+			return nil, err
+		}
+	} else {
+		signers, err = handler.loadSigners(ctx, historyQ, accountIDs)
+		if err = partial.handle(ctx, err, "could not load signers"); err != nil {
+			return nil, err
+		}
+
+		trustlines, err = handler.loadTrustlines(ctx, historyQ, accountIDs)
+		if err = partial.handle(ctx, err, "could not load trustlines"); err != nil {
+			return nil, err
+		}
+		if qp.OnlyMatchingAsset {
+			trustlines = filterTrustlinesByAsset(trustlines, *qp.Asset())
+		}
+
+		data, err = handler.loadData(ctx, historyQ, accountIDs)
+		if err = partial.handle(ctx, err, "could not load data"); err != nil {
+			return nil, err
+		}
 	}
 
 	ledgerCache := history.LedgerCache{}
@@ -197,27 +806,87 @@ func (handler GetAccountsHandler) GetResourcePage(
 		ledgerCache.Queue(int32(record.LastModifiedLedger))
 	}
 
-	if err := ledgerCache.Load(ctx, historyQ); err != nil {
+	err = ledgerCache.Load(ctx, historyQ)
+	if err = partial.handle(ctx, err, "could not load ledgers"); err != nil {
 		return nil, errors.Wrap(err, "failed to load ledger batch")
 	}
 
 	for _, record := range records {
 		var res protocol.Account
-		s := signers[record.AccountID]
-		t := trustlines[record.AccountID]
-		d := data[record.AccountID]
-		var ledger *history.Ledger
-		if l, ok := ledgerCache.Records[int32(record.LastModifiedLedger)]; ok {
-			ledger = &l
+		ledger := lastModifiedLedger(&ledgerCache, record)
+		if qp.Summary {
+			resourceadapter.PopulateAccountSummary(
+				ctx,
+				&res,
+				record,
+				signerCounts[record.AccountID],
+				trustlineCounts[record.AccountID],
+				dataCounts[record.AccountID],
+				ledger,
+			)
+		} else {
+			s := signers[record.AccountID]
+			t := trustlines[record.AccountID]
+			d := data[record.AccountID]
+			resourceadapter.PopulateAccountEntry(ctx, &res, record, d, s, t, ledger)
+		}
+		if qp.OmitEmpty {
+			resourceadapter.OmitEmptyAccountSubresources(&res)
+		}
+		if qp.Sort == accountsSortNativeBalance {
+			res.PT = history.NativeBalanceCursor(record)
+		} else if params.RowIDCursor {
+			res.PT = history.AccountRowIDCursor(record)
+		}
+		if len(partial.warnings) > 0 {
+			res.Partial = true
+			res.Warnings = partial.warnings
 		}
-		resourceadapter.PopulateAccountEntry(ctx, &res, record, d, s, t, ledger)
 
-		accounts = append(accounts, res)
+		accounts = append(accounts, Account{
+			Account:              res,
+			flagsPredateClawback: resourceadapter.AccountFlagsPredateClawback(ledger),
+		})
 	}
 
 	return accounts, nil
 }
 
+// uniqueAccountEntries removes the records of accounts already present
+// earlier in records (e.g. duplicate rows from a join), preserving the order,
+// so every account is loaded and populated once.
+func uniqueAccountEntries(records []history.AccountEntry) []history.AccountEntry {
+	seen := make(map[string]bool, len(records))
+	unique := make([]history.AccountEntry, 0, len(records))
+	for _, record := range records {
+		if seen[record.AccountID] {
+			continue
+		}
+		seen[record.AccountID] = true
+		unique = append(unique, record)
+	}
+	return unique
+}
+
+// partialResponse keeps track of the sub-loads which failed while building
+// a page of accounts when partial responses are allowed.
+type partialResponse struct {
+	allowed  bool
+	warnings []string
+}
+
+// handle returns err unless partial responses are allowed, in which case the
+// error is logged and the warning is recorded.
+func (p *partialResponse) handle(ctx context.Context, err error, warning string) error {
+	if err == nil || !p.allowed {
+		return err
+	}
+
+	log.Ctx(ctx).WithStack(err).WithField("err", err.Error()).Warn(warning)
+	p.warnings = append(p.warnings, warning)
+	return nil
+}
+
 func (handler GetAccountsHandler) loadData(ctx context.Context, historyQ *history.Q, accounts []string) (map[string][]history.Data, error) {
 	data := make(map[string][]history.Data)
 
@@ -248,6 +917,23 @@ func (handler GetAccountsHandler) loadTrustlines(ctx context.Context, historyQ *
 	return trustLines, nil
 }
 
+// filterTrustlinesByAsset removes all the trust lines which don't match asset.
+func filterTrustlinesByAsset(trustLines map[string][]history.TrustLine, asset xdr.Asset) map[string][]history.TrustLine {
+	var assetType, code, issuer string
+	asset.MustExtract(&assetType, &code, &issuer)
+
+	filtered := make(map[string][]history.TrustLine, len(trustLines))
+	for accountID, records := range trustLines {
+		for _, record := range records {
+			if record.AssetType == asset.Type && record.AssetCode == code && record.AssetIssuer == issuer {
+				filtered[accountID] = append(filtered[accountID], record)
+			}
+		}
+	}
+
+	return filtered
+}
+
 func (handler GetAccountsHandler) loadSigners(ctx context.Context, historyQ *history.Q, accounts []string) (map[string][]history.AccountSigner, error) {
 	signers := make(map[string][]history.AccountSigner)
 
@@ -263,28 +949,183 @@ func (handler GetAccountsHandler) loadSigners(ctx context.Context, historyQ *his
 	return signers, nil
 }
 
-func getLedgerBySequence(ctx context.Context, hq *history.Q, sequence int32) (*history.Ledger, error) {
-	ledger := &history.Ledger{}
-	err := hq.LedgerBySequence(ctx, ledger, sequence)
-	switch {
-	case hq.NoRows(err):
-		return nil, nil
-	case err != nil:
+// lastModifiedLedger returns the ledger in which the account was last
+// modified or nil if the ledger was not loaded in the cache.
+func lastModifiedLedger(ledgerCache *history.LedgerCache, record history.AccountEntry) *history.Ledger {
+	if l, ok := ledgerCache.Records[int32(record.LastModifiedLedger)]; ok {
+		return &l
+	}
+	return nil
+}
+
+// lastActivityTime returns the close time of the last ledger in which the
+// account, one of its trust lines or one of its data entries changed, the
+// same ledger as the last activity of the account summary. It is nil when
+// that ledger isn't ingested.
+func lastActivityTime(ctx context.Context, hq *history.Q, record history.AccountEntry) (*time.Time, error) {
+	subentries, err := hq.SummarizeAccountSubentries(ctx, record.AccountID)
+	if err != nil {
 		return nil, err
-	default:
-		return ledger, nil
 	}
+	var summary protocol.AccountActivitySummary
+	resourceadapter.PopulateAccountActivitySummary(&summary, record, subentries)
+	return ledgerCloseTime(ctx, hq, summary.LastActivityLedger)
+}
+
+// ledgerCloseTime returns the close time of the given ledger or nil when it
+// isn't ingested.
+func ledgerCloseTime(ctx context.Context, hq *history.Q, sequence uint32) (*time.Time, error) {
+	ledgerCache := history.LedgerCache{}
+	ledgerCache.Queue(int32(sequence))
+	if err := ledgerCache.Load(ctx, hq); err != nil {
+		return nil, errors.Wrap(err, "failed to load ledger batch")
+	}
+	if l, ok := ledgerCache.Records[int32(sequence)]; ok {
+		return &l.ClosedAt, nil
+	}
+	return nil, nil
 }
 
 // AccountByIDQuery query struct for accounts/{account_id} end-point
 type AccountByIDQuery struct {
-	AccountID string `schema:"account_id" valid:"accountID,optional"`
+	AccountID  string `schema:"account_id" valid:"accountID,optional"`
+	SignerType string `schema:"signer_type" valid:"-"`
+	// EmbedInflationDest embeds the inflation destination account in the
+	// response.
+	EmbedInflationDest bool `schema:"embed_inflation_dest" valid:"-"`
+	// IncludeIssuedAssets includes the assets issued by the account in the
+	// response.
+	IncludeIssuedAssets bool `schema:"include_issued_assets" valid:"-"`
+	// IncludeCreatedAt includes the operation and the ledger which created
+	// the account in the response.
+	IncludeCreatedAt bool `schema:"include_created_at" valid:"-"`
+	// OmitEmpty leaves the empty sub-resources out of the response.
+	OmitEmpty bool `schema:"omit_empty" valid:"-"`
+	// ExcludeDisabled leaves the disabled trust lines out of the balances.
+	ExcludeDisabled bool `schema:"exclude_disabled" valid:"-"`
+	// KeyCase is the case of the keys of the response, snake_case by
+	// default.
+	KeyCase string `schema:"key_case" valid:"in(snake|camel)~Accepted values: snake or camel,optional"`
+	// MinWeight keeps only the signers with at least the given weight. The
+	// master key is always kept.
+	MinWeight uint8 `schema:"min_weight" valid:"-"`
+	// IncludeIssuerFlags includes the flags of the issuer in every trust line
+	// balance.
+	IncludeIssuerFlags bool `schema:"include_issuer_flags" valid:"-"`
+	// IncludeMinBalance includes the minimum balance of the account, given
+	// the base reserve of the latest ledger.
+	IncludeMinBalance bool `schema:"include_min_balance" valid:"-"`
+	// IncludeReserveCost includes the base reserve paid by the sponsor of
+	// every sponsored signer and trust line, given the base reserve of the
+	// latest ledger.
+	IncludeReserveCost bool `schema:"include_reserve_cost" valid:"-"`
+	// KeyFormat is the format of the account id and of the signer keys,
+	// strkey by default or the hex encoding of their raw bytes.
+	KeyFormat string `schema:"key_format" valid:"in(strkey|hex)~Accepted values: strkey or hex,optional"`
+	// NativeOnly skips loading the trust lines of the account, so the native
+	// balance is its only balance.
+	NativeOnly bool `schema:"native_only" valid:"-"`
+	// IncludeRecentSignerChanges embeds the most recent signer changes of
+	// the account.
+	IncludeRecentSignerChanges bool `schema:"include_recent_signer_changes" valid:"-"`
+	// IncludeOffersCount includes the number of open offers of the account.
+	IncludeOffersCount bool `schema:"include_offers_count" valid:"-"`
+	// IncludeAuthorizedLedger includes the last ledger in which each trust
+	// line was authorized in its balance.
+	IncludeAuthorizedLedger bool `schema:"include_authorized_ledger" valid:"-"`
+	// IncludeSponsoring includes the signers of other accounts whose reserve
+	// is paid by the account. There is no bound on their number.
+	IncludeSponsoring bool `schema:"include_sponsoring" valid:"-"`
+}
+
+// Validate runs custom validations.
+func (q AccountByIDQuery) Validate() error {
+	for _, signerType := range q.SignerTypes() {
+		if !isValidSignerType(signerType) {
+			return problem.MakeInvalidFieldProblem(
+				"signer_type",
+				errors.Errorf("unknown signer type: %s", signerType),
+			)
+		}
+	}
+	return nil
+}
+
+// SignerTypes returns the list of signer types requested in the comma
+// separated signer_type parameter.
+func (q AccountByIDQuery) SignerTypes() []string {
+	if len(q.SignerType) == 0 {
+		return nil
+	}
+	return strings.Split(q.SignerType, ",")
+}
+
+func isValidSignerType(signerType string) bool {
+	for _, name := range protocol.KeyTypeNames {
+		if name == signerType {
+			return true
+		}
+	}
+	return false
+}
+
+// filterSignersByType keeps only the signers with one of the given types.
+func filterSignersByType(signers []protocol.Signer, types []string) []protocol.Signer {
+	filtered := make([]protocol.Signer, 0, len(signers))
+	for _, signer := range signers {
+		for _, signerType := range types {
+			if signer.Type == signerType {
+				filtered = append(filtered, signer)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+// filterSignersByWeight keeps only the master key of the account and the
+// signers with a weight of at least minWeight.
+func filterSignersByWeight(accountID string, signers []protocol.Signer, minWeight int32) []protocol.Signer {
+	filtered := make([]protocol.Signer, 0, len(signers))
+	for _, signer := range signers {
+		if signer.Key == accountID || signer.Weight >= minWeight {
+			filtered = append(filtered, signer)
+		}
+	}
+	return filtered
 }
 
 // GetAccountByIDHandler is the action handler for the /accounts/{account_id} endpoint
 type GetAccountByIDHandler struct{}
 
-type Account protocol.Account
+// Account is an account resource as rendered by Horizon.
+type Account struct {
+	protocol.Account
+	// flagsPredateClawback is set when the account was last modified before
+	// protocol 17, its flags are then rendered without auth_clawback_enabled,
+	// which didn't exist yet.
+	flagsPredateClawback bool
+}
+
+// MarshalJSON renders the account like protocol.Account does, except the
+// flags of the accounts predating clawback are rendered without
+// auth_clawback_enabled.
+func (a Account) MarshalJSON() ([]byte, error) {
+	encoded, err := json.Marshal(a.Account)
+	if err != nil || !a.flagsPredateClawback {
+		return encoded, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err = json.Unmarshal(encoded, &fields); err != nil {
+		return nil, err
+	}
+	fields["flags"], err = json.Marshal(resourceadapter.NewPreClawbackAccountFlags(a.Flags))
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(fields)
+}
 
 func (a Account) Equals(other StreamableObjectResponse) bool {
 	otherAccount, ok := other.(Account)
@@ -294,6 +1135,24 @@ func (a Account) Equals(other StreamableObjectResponse) bool {
 	return a.ID == otherAccount.ID
 }
 
+// camelCaseAccount is an account rendered with camelCase keys.
+type camelCaseAccount struct {
+	Account
+}
+
+// MarshalJSON renders the account with camelCase keys.
+func (a camelCaseAccount) MarshalJSON() ([]byte, error) {
+	return httpjson.MarshalCamelCase(a.Account)
+}
+
+func (a camelCaseAccount) Equals(other StreamableObjectResponse) bool {
+	otherAccount, ok := other.(camelCaseAccount)
+	if !ok {
+		return false
+	}
+	return a.Account.Equals(otherAccount.Account)
+}
+
 func (handler GetAccountByIDHandler) GetResource(
 	w HeaderWriter,
 	r *http.Request,
@@ -307,11 +1166,277 @@ func (handler GetAccountByIDHandler) GetResource(
 	qp := AccountByIDQuery{}
 	err = getParams(&qp, r)
 	if err != nil {
-		return nil, err
+		return nil, localizeProblem(r, err)
 	}
-	account, err := AccountInfo(r.Context(), historyQ, qp.AccountID)
+	resource, lastActivityLedger, err := accountInfo(r.Context(), historyQ, qp.AccountID, !qp.NativeOnly)
 	if err != nil {
-		return Account{}, err
+		return Account{}, historyUnavailableProblem(err)
 	}
-	return Account(*account), nil
+	account := &resource.Account
+	// the last modification is only looked up for conditional requests. The
+	// embedded inflation destinations, the issued assets, the flags of the
+	// issuers and the base reserve can change independently of the account,
+	// so those responses are never conditional
+	if isConditionalRequest(r) && !qp.EmbedInflationDest && !qp.IncludeIssuedAssets && !qp.IncludeIssuerFlags && !qp.IncludeMinBalance && !qp.IncludeReserveCost {
+		var lastModified *time.Time
+		if qp.NativeOnly {
+			// the trust lines weren't loaded
+			lastModified, err = lastActivityTime(r.Context(), historyQ, history.AccountEntry{
+				AccountID:          account.AccountID,
+				LastModifiedLedger: account.LastModifiedLedger,
+			})
+		} else {
+			lastModified, err = ledgerCloseTime(r.Context(), historyQ, lastActivityLedger)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err = checkNotModified(w, r, lastModified); err != nil {
+			return nil, err
+		}
+	}
+	if signerTypes := qp.SignerTypes(); len(signerTypes) > 0 {
+		account.Signers = filterSignersByType(account.Signers, signerTypes)
+	}
+	if qp.MinWeight > 0 {
+		account.Signers = filterSignersByWeight(account.AccountID, account.Signers, int32(qp.MinWeight))
+	}
+	if qp.EmbedInflationDest {
+		err = embedInflationDestination(r.Context(), historyQ, account, map[string]bool{})
+		if err != nil {
+			return Account{}, err
+		}
+	}
+	if qp.IncludeIssuedAssets {
+		account.IssuedAssets, err = issuedAssets(r.Context(), historyQ, account.AccountID)
+		if err != nil {
+			return Account{}, err
+		}
+	}
+	if qp.IncludeCreatedAt {
+		if err = includeCreationInfo(r.Context(), historyQ, account); err != nil {
+			return Account{}, err
+		}
+	}
+	if qp.IncludeRecentSignerChanges {
+		if err = embedRecentSignerChanges(r.Context(), historyQ, account); err != nil {
+			return Account{}, err
+		}
+	}
+	if qp.IncludeAuthorizedLedger {
+		if err = includeAuthorizedLedgers(r.Context(), historyQ, account.AccountID, account.Balances); err != nil {
+			return Account{}, err
+		}
+	}
+	if qp.IncludeSponsoring {
+		if err = includeSponsoring(r.Context(), historyQ, account); err != nil {
+			return Account{}, err
+		}
+	}
+	if qp.IncludeOffersCount {
+		var count int
+		count, err = historyQ.CountOffersForAccount(r.Context(), account.AccountID)
+		if err != nil {
+			return Account{}, errors.Wrap(err, "counting offers")
+		}
+		offersCount := int32(count)
+		account.OffersCount = &offersCount
+	}
+	if qp.ExcludeDisabled {
+		account.Balances = resourceadapter.ExcludeDisabledBalances(account.Balances)
+	}
+	if qp.IncludeIssuerFlags {
+		var issuers map[string]history.AccountEntry
+		if issuers, err = loadIssuers(r.Context(), historyQ, account.Balances); err != nil {
+			return Account{}, err
+		}
+		includeIssuerFlags(account.Balances, issuers)
+	}
+	if qp.IncludeMinBalance || qp.IncludeReserveCost {
+		var baseReserve int32
+		baseReserve, err = historyQ.LatestLedgerBaseReserve(r.Context())
+		if err != nil {
+			return Account{}, errors.Wrap(err, "loading base reserve")
+		}
+		if qp.IncludeMinBalance {
+			resourceadapter.PopulateMinBalance(account, baseReserve)
+		}
+		if qp.IncludeReserveCost {
+			resourceadapter.PopulateSponsorshipReserves(account, baseReserve)
+		}
+	}
+	if qp.OmitEmpty {
+		resourceadapter.OmitEmptyAccountSubresources(account)
+	}
+	if qp.KeyFormat == "hex" {
+		if err = resourceadapter.HexEncodeAccountKeys(account); err != nil {
+			return Account{}, err
+		}
+	}
+	if qp.KeyCase == "camel" {
+		return camelCaseAccount{*resource}, nil
+	}
+	return *resource, nil
+}
+
+// Head checks the existence of the account for HEAD requests. Only the
+// account record is loaded, and the summary of its sub-entries for
+// conditional requests, to set the same Last-Modified header as GetResource.
+func (handler GetAccountByIDHandler) Head(w HeaderWriter, r *http.Request) error {
+	historyQ, err := horizonContext.HistoryQFromRequest(r)
+	if err != nil {
+		return err
+	}
+
+	qp := AccountByIDQuery{}
+	if err = getParams(&qp, r); err != nil {
+		return localizeProblem(r, err)
+	}
+	record, err := historyQ.GetAccountByID(r.Context(), qp.AccountID)
+	if err != nil {
+		return historyUnavailableProblem(errors.Wrap(err, "getting history account record"))
+	}
+
+	if !isConditionalRequest(r) {
+		return nil
+	}
+	lastModified, err := lastActivityTime(r.Context(), historyQ, record)
+	if err != nil {
+		return err
+	}
+	return checkNotModified(w, r, lastModified)
+}
+
+// includeSponsoring fills the signers sponsored by the account. They are only
+// looked up for accounts which sponsor some entries.
+func includeSponsoring(ctx context.Context, hq *history.Q, account *protocol.Account) error {
+	if account.NumSponsoring == 0 {
+		return nil
+	}
+	sponsoredSigners, err := hq.SignersSponsoredBy(ctx, account.AccountID)
+	if err != nil {
+		return errors.Wrap(err, "getting sponsored signers")
+	}
+	for _, sponsoredSigner := range sponsoredSigners {
+		account.Sponsoring = append(account.Sponsoring, protocol.SponsoredSigner{
+			AccountID: sponsoredSigner.Account,
+			Key:       sponsoredSigner.Signer,
+		})
+	}
+	return nil
+}
+
+// includeCreationInfo fills the operation and the ledger which created the
+// account. They are left empty if the creation of the account predates the
+// ingested history.
+func includeCreationInfo(ctx context.Context, hq *history.Q, account *protocol.Account) error {
+	creation, err := hq.AccountCreationInfo(ctx, account.AccountID)
+	if hq.NoRows(err) {
+		return nil
+	} else if err != nil {
+		return errors.Wrap(err, "loading account creation")
+	}
+
+	account.CreatedAtOperationID = strconv.FormatInt(creation.OperationID, 10)
+	account.CreatedLedger = creation.LedgerSequence()
+	return nil
+}
+
+// issuedAssets returns the assets issued by the account, or nil if it
+// doesn't issue any.
+func issuedAssets(ctx context.Context, hq *history.Q, accountID string) ([]protocol.Asset, error) {
+	assets, err := hq.AssetsIssuedBy(ctx, accountID)
+	if err != nil {
+		return nil, errors.Wrap(err, "loading issued assets")
+	}
+
+	var result []protocol.Asset
+	for _, asset := range assets {
+		var assetType, code, issuer string
+		asset.MustExtract(&assetType, &code, &issuer)
+		result = append(result, protocol.Asset{
+			Type:   assetType,
+			Code:   code,
+			Issuer: issuer,
+		})
+	}
+	return result, nil
+}
+
+// maxEmbeddedInflationDestinations limits the length of the chain of
+// inflation destinations embedded in an account.
+const maxEmbeddedInflationDestinations = 5
+
+// embedInflationDestination embeds the inflation destination of account,
+// and recursively the inflation destination of the embedded account, until
+// an account without inflation destination, an account already in the chain
+// or maxEmbeddedInflationDestinations is reached. Accounts pointing back into
+// the chain, including to themselves, are flagged with cycle_detected instead.
+// Inflation destinations which don't exist (e.g. merged accounts) are not
+// embedded.
+func embedInflationDestination(
+	ctx context.Context,
+	hq *history.Q,
+	account *protocol.Account,
+	visited map[string]bool,
+) error {
+	visited[account.AccountID] = true
+	dest := account.InflationDestination
+	if visited[dest] {
+		if account.Embedded == nil {
+			account.Embedded = &protocol.AccountEmbedded{}
+		}
+		account.Embedded.CycleDetected = true
+		return nil
+	}
+	if dest == "" || len(visited) > maxEmbeddedInflationDestinations {
+		return nil
+	}
+
+	embedded, err := AccountInfo(ctx, hq, dest)
+	if hq.NoRows(errors.Cause(err)) {
+		return nil
+	} else if err != nil {
+		return errors.Wrap(err, "loading inflation destination")
+	}
+
+	if err := embedInflationDestination(ctx, hq, embedded, visited); err != nil {
+		return err
+	}
+	if account.Embedded == nil {
+		account.Embedded = &protocol.AccountEmbedded{}
+	}
+	account.Embedded.InflationDestination = embedded
+	return nil
+}
+
+// maxRecentSignerChanges is the number of signer changes embedded in an
+// account.
+const maxRecentSignerChanges = 5
+
+// embedRecentSignerChanges embeds the most recent signer changes of the
+// account, latest first. Nothing is embedded if the history of the account
+// has no signer change.
+func embedRecentSignerChanges(ctx context.Context, hq *history.Q, account *protocol.Account) error {
+	records, err := hq.RecentSignerChanges(ctx, account.AccountID, maxRecentSignerChanges)
+	if err != nil {
+		return errors.Wrap(err, "loading recent signer changes")
+	}
+	if len(records) == 0 {
+		return nil
+	}
+
+	changes := make([]protocol.SignerChange, 0, len(records))
+	for _, record := range records {
+		var change protocol.SignerChange
+		if err = resourceadapter.PopulateSignerChange(&change, record); err != nil {
+			return err
+		}
+		changes = append(changes, change)
+	}
+	if account.Embedded == nil {
+		account.Embedded = &protocol.AccountEmbedded{}
+	}
+	account.Embedded.RecentSignerChanges = changes
+	return nil
 }
@@ -0,0 +1,168 @@
+package actions
+
+import (
+	"context"
+	"net/http"
+	"sort"
+
+	protocol "github.com/stellar/go/protocols/horizon"
+	horizonContext "github.com/stellar/go/services/horizon/internal/context"
+	"github.com/stellar/go/services/horizon/internal/db2"
+	"github.com/stellar/go/services/horizon/internal/db2/history"
+	"github.com/stellar/go/services/horizon/internal/ledger"
+	"github.com/stellar/go/services/horizon/internal/resourceadapter"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/support/render/hal"
+)
+
+// AccountBalancesQuery query struct for the /accounts/{account_id}/balances
+// end-point
+type AccountBalancesQuery struct {
+	AccountID string `schema:"account_id" valid:"accountID,required"`
+	// ExcludeDisabled leaves the disabled trust lines out of the balances.
+	ExcludeDisabled bool `schema:"exclude_disabled" valid:"-"`
+	// IncludeIssuerFlags includes the flags of the issuer in every trust line
+	// balance.
+	IncludeIssuerFlags bool `schema:"include_issuer_flags" valid:"-"`
+}
+
+// GetAccountBalancesHandler is the action handler for the
+// /accounts/{account_id}/balances endpoint. It returns the balances of an
+// account without the rest of the account resource.
+type GetAccountBalancesHandler struct {
+	LedgerState *ledger.State
+}
+
+// GetResourcePage returns a page of balances of an account, paged by asset
+// ("native" or "code:issuer").
+func (handler GetAccountBalancesHandler) GetResourcePage(
+	w HeaderWriter,
+	r *http.Request,
+) ([]hal.Pageable, error) {
+	ctx := r.Context()
+	pq, err := GetPageQuery(handler.LedgerState, r, DisableCursorValidation)
+	if err != nil {
+		return nil, err
+	}
+
+	qp := AccountBalancesQuery{}
+	if err = getParams(&qp, r); err != nil {
+		return nil, err
+	}
+
+	historyQ, err := horizonContext.HistoryQFromRequest(r)
+	if err != nil {
+		return nil, err
+	}
+
+	record, err := historyQ.GetAccountByID(ctx, qp.AccountID)
+	if err != nil {
+		return nil, errors.Wrap(err, "getting history account record")
+	}
+
+	trustlines, err := historyQ.GetSortedTrustLinesByAccountID(ctx, qp.AccountID)
+	if err != nil {
+		return nil, errors.Wrap(err, "getting history trustlines")
+	}
+
+	balances, err := resourceadapter.PopulateAccountBalances(record, trustlines)
+	if err != nil {
+		return nil, errors.Wrap(err, "populating balances")
+	}
+	if err = includeAuthorizedLedgers(ctx, historyQ, qp.AccountID, balances); err != nil {
+		return nil, err
+	}
+	if qp.ExcludeDisabled {
+		balances = resourceadapter.ExcludeDisabledBalances(balances)
+	}
+	if qp.IncludeIssuerFlags {
+		var issuers map[string]history.AccountEntry
+		if issuers, err = loadIssuers(ctx, historyQ, balances); err != nil {
+			return nil, err
+		}
+		includeIssuerFlags(balances, issuers)
+	}
+
+	return pageAccountBalances(balances, pq), nil
+}
+
+// loadIssuers prefetches the accounts issuing the assets of the trust line
+// balances, the distinct issuers being loaded with a single query, so the
+// enrichments of the balances needing the issuers share them. Issuers which
+// don't exist anymore are absent from the map.
+func loadIssuers(ctx context.Context, hq *history.Q, balances []protocol.Balance) (map[string]history.AccountEntry, error) {
+	var ids []string
+	seen := map[string]bool{}
+	for _, balance := range balances {
+		if balance.Type == "native" || seen[balance.Issuer] {
+			continue
+		}
+		seen[balance.Issuer] = true
+		ids = append(ids, balance.Issuer)
+	}
+	issuers := map[string]history.AccountEntry{}
+	if len(ids) == 0 {
+		return issuers, nil
+	}
+
+	records, err := hq.GetAccountsByIDs(ctx, ids)
+	if err != nil {
+		return nil, errors.Wrap(err, "loading issuers")
+	}
+	for _, record := range records {
+		issuers[record.AccountID] = record
+	}
+	return issuers, nil
+}
+
+// includeIssuerFlags sets the flags of the issuer on every trust line
+// balance. Balances of issuers missing from issuers are left without flags.
+func includeIssuerFlags(balances []protocol.Balance, issuers map[string]history.AccountEntry) {
+	for i := range balances {
+		if balances[i].Type == "native" {
+			continue
+		}
+		if issuer, ok := issuers[balances[i].Issuer]; ok {
+			var issuerFlags protocol.AccountFlags
+			resourceadapter.PopulateAccountFlags(&issuerFlags, issuer)
+			balances[i].IssuerFlags = &issuerFlags
+		}
+	}
+}
+
+// pageAccountBalances orders the balances by paging token and returns the
+// ones in the page described by pq. Balances are paged in memory as accounts
+// hold a bounded number of trust lines.
+func pageAccountBalances(balances []protocol.Balance, pq db2.PageQuery) []hal.Pageable {
+	sorted := make([]protocol.AccountBalance, 0, len(balances))
+	for _, balance := range balances {
+		token := balance.Type
+		if balance.Type != "native" {
+			token = balance.Code + ":" + balance.Issuer
+		}
+		sorted = append(sorted, protocol.AccountBalance{Balance: balance, PT: token})
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if pq.Order == db2.OrderDescending {
+			return sorted[i].PT > sorted[j].PT
+		}
+		return sorted[i].PT < sorted[j].PT
+	})
+
+	page := []hal.Pageable{}
+	for _, balance := range sorted {
+		if uint64(len(page)) == pq.Limit {
+			break
+		}
+		if pq.Cursor != "" {
+			if pq.Order == db2.OrderDescending && balance.PT >= pq.Cursor {
+				continue
+			}
+			if pq.Order != db2.OrderDescending && balance.PT <= pq.Cursor {
+				continue
+			}
+		}
+		page = append(page, balance)
+	}
+	return page
+}
@@ -0,0 +1,246 @@
+package actions
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	protocol "github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/protocols/horizon/base"
+	"github.com/stellar/go/services/horizon/internal/db2/history"
+	"github.com/stellar/go/services/horizon/internal/test"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/xdr"
+)
+
+func TestGetAccountBalancesHandler(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+
+	q := &history.Q{tt.HorizonSession()}
+	handler := GetAccountBalancesHandler{}
+
+	batch := q.NewAccountsBatchInsertBuilder(0)
+	tt.Assert.NoError(batch.Add(tt.Ctx, account1))
+	tt.Assert.NoError(batch.Add(tt.Ctx, account2))
+	tt.Assert.NoError(batch.Exec(tt.Ctx))
+
+	accountTwoEurTrustLine := eurTrustLine
+	trustLine := *eurTrustLine.Data.TrustLine
+	trustLine.AccountId = xdr.MustAddress(accountTwo)
+	accountTwoEurTrustLine.Data.TrustLine = &trustLine
+	for _, entry := range []xdr.LedgerEntry{eurTrustLine, usdTrustLine, accountTwoEurTrustLine} {
+		_, err := q.InsertTrustLine(tt.Ctx, entry)
+		tt.Assert.NoError(err)
+	}
+
+	routeParams := map[string]string{"account_id": accountTwo}
+	records, err := handler.GetResourcePage(
+		httptest.NewRecorder(),
+		makeRequest(t, map[string]string{}, routeParams, q),
+	)
+	tt.Assert.NoError(err)
+	tokens := []string{}
+	for _, record := range records {
+		tokens = append(tokens, record.PagingToken())
+	}
+	tt.Assert.Equal([]string{"EUR:" + trustLineIssuer, "USD:" + trustLineIssuer, "native"}, tokens)
+
+	usdBalance := records[1].(protocol.AccountBalance)
+	tt.Assert.Equal("credit_alphanum4", usdBalance.Type)
+	tt.Assert.Equal("0.0010000", usdBalance.Balance.Balance)
+	tt.Assert.Equal("0.0000001", usdBalance.BuyingLiabilities)
+	tt.Assert.Equal("0.0000002", usdBalance.SellingLiabilities)
+	nativeBalance := records[2].(protocol.AccountBalance)
+	tt.Assert.Equal("0.0050000", nativeBalance.Balance.Balance)
+
+	records, err = handler.GetResourcePage(
+		httptest.NewRecorder(),
+		makeRequest(
+			t,
+			map[string]string{"cursor": "EUR:" + trustLineIssuer, "limit": "1"},
+			routeParams,
+			q,
+		),
+	)
+	tt.Assert.NoError(err)
+	if tt.Assert.Len(records, 1) {
+		tt.Assert.Equal("USD:"+trustLineIssuer, records[0].PagingToken())
+	}
+
+	records, err = handler.GetResourcePage(
+		httptest.NewRecorder(),
+		makeRequest(t, map[string]string{"order": "desc", "limit": "2"}, routeParams, q),
+	)
+	tt.Assert.NoError(err)
+	if tt.Assert.Len(records, 2) {
+		tt.Assert.Equal("native", records[0].PagingToken())
+		tt.Assert.Equal("USD:"+trustLineIssuer, records[1].PagingToken())
+	}
+
+	_, err = handler.GetResourcePage(
+		httptest.NewRecorder(),
+		makeRequest(t, map[string]string{}, map[string]string{"account_id": signer}, q),
+	)
+	tt.Assert.True(q.NoRows(errors.Cause(err)))
+}
+
+func TestGetAccountBalancesHandlerExcludeDisabled(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+
+	q := &history.Q{tt.HorizonSession()}
+	handler := GetAccountBalancesHandler{}
+
+	batch := q.NewAccountsBatchInsertBuilder(0)
+	tt.Assert.NoError(batch.Add(tt.Ctx, account1))
+	tt.Assert.NoError(batch.Exec(tt.Ctx))
+
+	// a trust line with a zero limit and a zero balance is disabled
+	disabledTrustLine := usdTrustLine
+	trustLine := *usdTrustLine.Data.TrustLine
+	trustLine.AccountId = xdr.MustAddress(accountOne)
+	trustLine.Limit = 0
+	trustLine.Balance = 0
+	trustLine.Ext = xdr.TrustLineEntryExt{}
+	disabledTrustLine.Data.TrustLine = &trustLine
+	for _, entry := range []xdr.LedgerEntry{eurTrustLine, disabledTrustLine} {
+		_, err := q.InsertTrustLine(tt.Ctx, entry)
+		tt.Assert.NoError(err)
+	}
+
+	routeParams := map[string]string{"account_id": accountOne}
+	records, err := handler.GetResourcePage(
+		httptest.NewRecorder(),
+		makeRequest(t, map[string]string{}, routeParams, q),
+	)
+	tt.Assert.NoError(err)
+	if tt.Assert.Len(records, 3) {
+		eurBalance := records[0].(protocol.AccountBalance)
+		tt.Assert.False(*eurBalance.IsDisabled)
+		usdBalance := records[1].(protocol.AccountBalance)
+		tt.Assert.Equal("USD:"+trustLineIssuer, usdBalance.PT)
+		tt.Assert.True(*usdBalance.IsDisabled)
+		nativeBalance := records[2].(protocol.AccountBalance)
+		tt.Assert.Nil(nativeBalance.IsDisabled)
+	}
+
+	records, err = handler.GetResourcePage(
+		httptest.NewRecorder(),
+		makeRequest(t, map[string]string{"exclude_disabled": "true"}, routeParams, q),
+	)
+	tt.Assert.NoError(err)
+	tokens := []string{}
+	for _, record := range records {
+		tokens = append(tokens, record.PagingToken())
+	}
+	tt.Assert.Equal([]string{"EUR:" + trustLineIssuer, "native"}, tokens)
+}
+
+func TestGetAccountBalancesHandlerAuthorizedLedger(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+
+	q := &history.Q{tt.HorizonSession()}
+	handler := GetAccountBalancesHandler{}
+
+	batch := q.NewAccountsBatchInsertBuilder(0)
+	tt.Assert.NoError(batch.Add(tt.Ctx, account2))
+	tt.Assert.NoError(batch.Exec(tt.Ctx))
+	_, err := q.InsertTrustLine(tt.Ctx, usdTrustLine)
+	tt.Assert.NoError(err)
+
+	routeParams := map[string]string{"account_id": accountTwo}
+	getUSDBalance := func() protocol.AccountBalance {
+		records, err := handler.GetResourcePage(
+			httptest.NewRecorder(),
+			makeRequest(t, map[string]string{}, routeParams, q),
+		)
+		tt.Assert.NoError(err)
+		tt.Assert.Len(records, 2)
+		tt.Assert.Zero(records[1].(protocol.AccountBalance).AuthorizedLedger)
+		return records[0].(protocol.AccountBalance)
+	}
+
+	// the authorization of usdTrustLine was never recorded
+	tt.Assert.Zero(getUSDBalance().AuthorizedLedger)
+
+	// the issuer authorizes the trust line, revokes the authorization and
+	// authorizes it again
+	usdTrustLineWithFlags := func(flags xdr.TrustLineFlags) xdr.LedgerEntry {
+		entry := usdTrustLine
+		trustLine := *usdTrustLine.Data.TrustLine
+		trustLine.Flags = xdr.Uint32(flags)
+		entry.Data.TrustLine = &trustLine
+		return entry
+	}
+	for ledger, flags := range map[uint32]xdr.TrustLineFlags{
+		100: xdr.TrustLineFlagsAuthorizedFlag,
+		101: 0,
+		102: xdr.TrustLineFlagsAuthorizedFlag,
+		103: xdr.TrustLineFlagsAuthorizedToMaintainLiabilitiesFlag,
+	} {
+		tt.Assert.NoError(q.InsertTrustLineAuthorizations(
+			tt.Ctx,
+			ledger,
+			[]xdr.LedgerEntry{usdTrustLineWithFlags(flags)},
+		))
+	}
+
+	tt.Assert.Equal(uint32(102), getUSDBalance().AuthorizedLedger)
+}
+
+func TestLoadIssuers(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+
+	q := &history.Q{tt.HorizonSession()}
+	batch := q.NewAccountsBatchInsertBuilder(0)
+	tt.Assert.NoError(batch.Add(tt.Ctx, account1))
+	tt.Assert.NoError(batch.Add(tt.Ctx, account2))
+	tt.Assert.NoError(batch.Exec(tt.Ctx))
+
+	// many trust lines share the issuers accountOne, accountTwo and signer,
+	// which doesn't exist
+	var balances []protocol.Balance
+	for i := 0; i < 10; i++ {
+		for _, issuer := range []string{accountOne, accountTwo, signer} {
+			balances = append(balances, protocol.Balance{
+				Asset: base.Asset{
+					Type:   "credit_alphanum4",
+					Code:   fmt.Sprintf("A%d", i),
+					Issuer: issuer,
+				},
+			})
+		}
+	}
+	balances = append(balances, protocol.Balance{Asset: base.Asset{Type: "native"}})
+
+	session := &queryRecordingSession{SessionInterface: q}
+	issuers, err := loadIssuers(tt.Ctx, &history.Q{session}, balances)
+	tt.Assert.NoError(err)
+	tt.Assert.Len(session.queries, 1)
+	tt.Assert.Len(issuers, 2)
+	tt.Assert.Equal(accountOne, issuers[accountOne].AccountID)
+	tt.Assert.Equal(accountTwo, issuers[accountTwo].AccountID)
+
+	includeIssuerFlags(balances, issuers)
+	for _, balance := range balances {
+		if balance.Type == "native" || balance.Issuer == signer {
+			tt.Assert.Nil(balance.IssuerFlags)
+		} else {
+			tt.Assert.NotNil(balance.IssuerFlags)
+		}
+	}
+
+	// balances without trust lines don't run any query
+	session = &queryRecordingSession{SessionInterface: q}
+	issuers, err = loadIssuers(tt.Ctx, &history.Q{session}, balances[len(balances)-1:])
+	tt.Assert.NoError(err)
+	tt.Assert.Empty(issuers)
+	tt.Assert.Len(session.queries, 0)
+}
@@ -1,17 +1,27 @@
 package actions
 
 import (
+	"encoding/hex"
 	"io"
 	"net/http"
+	"strconv"
+	"unicode/utf8"
 
+	protocol "github.com/stellar/go/protocols/horizon"
 	"github.com/stellar/go/services/horizon/internal/context"
 	"github.com/stellar/go/services/horizon/internal/db2/history"
+	"github.com/stellar/go/services/horizon/internal/ledger"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/support/render/hal"
+	"github.com/stellar/go/support/render/problem"
 )
 
 // AccountDataQuery query struct for account data end-point
 type AccountDataQuery struct {
 	AccountID string `schema:"account_id" valid:"accountID"`
 	Key       string `schema:"key" valid:"length(1|64)"`
+	// Decode is the codec the value is rendered with, base64 by default.
+	Decode string `schema:"decode" valid:"in(utf8|hex|base64)~Accepted values: utf8; hex or base64,optional"`
 }
 
 type accountDataResponse struct {
@@ -30,11 +40,15 @@ func (adr accountDataResponse) Equals(other StreamableObjectResponse) bool {
 type GetAccountDataHandler struct{}
 
 func (handler GetAccountDataHandler) GetResource(w HeaderWriter, r *http.Request) (StreamableObjectResponse, error) {
-	data, err := loadAccountData(r)
+	qp, data, err := loadAccountData(r)
 	if err != nil {
 		return nil, err
 	}
-	response := accountDataResponse{Value: data.Value.Base64()}
+	value, err := encodeDataValue(data.Value, qp.Decode)
+	if err != nil {
+		return nil, err
+	}
+	response := accountDataResponse{Value: value}
 	if data.Sponsor.Valid {
 		response.Sponsor = data.Sponsor.String
 	}
@@ -42,7 +56,7 @@ func (handler GetAccountDataHandler) GetResource(w HeaderWriter, r *http.Request
 }
 
 func (handler GetAccountDataHandler) WriteRawResponse(w io.Writer, r *http.Request) error {
-	data, err := loadAccountData(r)
+	_, data, err := loadAccountData(r)
 	if err != nil {
 		return err
 	}
@@ -50,19 +64,120 @@ func (handler GetAccountDataHandler) WriteRawResponse(w io.Writer, r *http.Reque
 	return err
 }
 
-func loadAccountData(r *http.Request) (history.Data, error) {
+func loadAccountData(r *http.Request) (AccountDataQuery, history.Data, error) {
 	qp := AccountDataQuery{}
 	err := getParams(&qp, r)
 	if err != nil {
-		return history.Data{}, err
+		return qp, history.Data{}, err
 	}
 	historyQ, err := context.HistoryQFromRequest(r)
 	if err != nil {
-		return history.Data{}, err
+		return qp, history.Data{}, err
 	}
 	data, err := historyQ.GetAccountDataByName(r.Context(), qp.AccountID, qp.Key)
 	if err != nil {
-		return history.Data{}, err
+		return qp, history.Data{}, err
 	}
-	return data, nil
+	return qp, data, nil
+}
+
+// encodeDataValue renders the value of a data entry with the given codec,
+// base64 by default.
+func encodeDataValue(value history.AccountDataValue, codec string) (string, error) {
+	switch codec {
+	case "hex":
+		return hex.EncodeToString(value), nil
+	case "utf8":
+		if !utf8.Valid(value) {
+			return "", problem.MakeInvalidFieldProblem(
+				"decode",
+				errors.New("the value is not valid UTF-8"),
+			)
+		}
+		return string(value), nil
+	default:
+		return value.Base64(), nil
+	}
+}
+
+// AccountDataEntriesQuery query struct for the /accounts/{account_id}/data
+// end-point
+type AccountDataEntriesQuery struct {
+	AccountID string `schema:"account_id" valid:"accountID,required"`
+	// Sort is the order of the data entries, currently they can only be
+	// sorted by the length in bytes of their values.
+	Sort string `schema:"sort" valid:"in(size)~Accepted values: size,required"`
+	// IncludeReserveCost includes the base reserve paid by the sponsor of
+	// every sponsored data entry, given the base reserve of the latest
+	// ledger.
+	IncludeReserveCost bool `schema:"include_reserve_cost" valid:"-"`
+}
+
+// GetAccountDataEntriesHandler is the action handler for the
+// /accounts/{account_id}/data endpoint. It lists the data entries of an
+// account.
+type GetAccountDataEntriesHandler struct {
+	LedgerState *ledger.State
+}
+
+// GetResourcePage returns a page of data entries of an account, paged by
+// value size and name.
+func (handler GetAccountDataEntriesHandler) GetResourcePage(
+	w HeaderWriter,
+	r *http.Request,
+) ([]hal.Pageable, error) {
+	ctx := r.Context()
+	pq, err := GetPageQuery(handler.LedgerState, r, DisableCursorValidation)
+	if err != nil {
+		return nil, err
+	}
+
+	qp := AccountDataEntriesQuery{}
+	if err = getParams(&qp, r); err != nil {
+		return nil, err
+	}
+
+	if pq.Cursor != "" {
+		if _, _, err = history.ParseDataSizeCursor(pq.Cursor); err != nil {
+			return nil, problem.MakeInvalidFieldProblem(ParamCursor, err)
+		}
+	}
+
+	historyQ, err := context.HistoryQFromRequest(r)
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := historyQ.DataByAddressOrderedBySize(ctx, qp.AccountID, pq)
+	if err != nil {
+		return nil, errors.Wrap(err, "loading account data")
+	}
+
+	var reserve string
+	if qp.IncludeReserveCost {
+		var baseReserve int32
+		baseReserve, err = historyQ.LatestLedgerBaseReserve(ctx)
+		if err != nil {
+			return nil, errors.Wrap(err, "loading base reserve")
+		}
+		reserve = strconv.FormatInt(int64(baseReserve), 10)
+	}
+
+	entries := []hal.Pageable{}
+	for _, record := range records {
+		entry := protocol.AccountDataEntry{
+			Name:               record.Name,
+			Value:              record.Value.Base64(),
+			Size:               len(record.Value),
+			LastModifiedLedger: record.LastModifiedLedger,
+			PT:                 history.DataSizeCursor(record),
+		}
+		if record.Sponsor.Valid {
+			entry.Sponsor = record.Sponsor.String
+			entry.ReserveStroops = reserve
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
 }
@@ -0,0 +1,165 @@
+package actions
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	protocol "github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/services/horizon/internal/db2/history"
+	"github.com/stellar/go/services/horizon/internal/test"
+	"github.com/stellar/go/support/render/problem"
+	"github.com/stellar/go/xdr"
+)
+
+func TestEncodeDataValue(t *testing.T) {
+	value := history.AccountDataValue("value")
+	for codec, expected := range map[string]string{
+		"":       "dmFsdWU=",
+		"base64": "dmFsdWU=",
+		"hex":    "76616c7565",
+		"utf8":   "value",
+	} {
+		encoded, err := encodeDataValue(value, codec)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, encoded)
+	}
+
+	_, err := encodeDataValue(history.AccountDataValue{0xff, 0xfe}, "utf8")
+	if assert.IsType(t, &problem.P{}, err) {
+		assert.Equal(t, "decode", err.(*problem.P).Extras["invalid_field"])
+	}
+}
+
+func TestGetAccountDataEntriesHandler(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+
+	q := &history.Q{tt.HorizonSession()}
+	handler := GetAccountDataEntriesHandler{}
+
+	// data1 holds 10 bytes, largeData 64 bytes, both belong to accountOne
+	largeData := data1
+	entry := *data1.Data.Data
+	entry.DataName = "large data"
+	entry.DataValue = make([]byte, 64)
+	largeData.Data.Data = &entry
+	for _, data := range []xdr.LedgerEntry{data1, data2, largeData} {
+		_, err := q.InsertAccountData(tt.Ctx, data)
+		tt.Assert.NoError(err)
+	}
+
+	routeParams := map[string]string{"account_id": accountOne}
+	records, err := handler.GetResourcePage(
+		httptest.NewRecorder(),
+		makeRequest(t, map[string]string{"sort": "size", "order": "desc"}, routeParams, q),
+	)
+	tt.Assert.NoError(err)
+	if tt.Assert.Len(records, 2) {
+		large := records[0].(protocol.AccountDataEntry)
+		tt.Assert.Equal("large data", large.Name)
+		tt.Assert.Equal(64, large.Size)
+		tt.Assert.Equal("64-large data", large.PT)
+
+		small := records[1].(protocol.AccountDataEntry)
+		tt.Assert.Equal("test data", small.Name)
+		tt.Assert.Equal(10, small.Size)
+		tt.Assert.Equal("AAECAwQFBgcICQ==", small.Value)
+	}
+
+	records, err = handler.GetResourcePage(
+		httptest.NewRecorder(),
+		makeRequest(
+			t,
+			map[string]string{"sort": "size", "order": "desc", "cursor": "64-large data"},
+			routeParams,
+			q,
+		),
+	)
+	tt.Assert.NoError(err)
+	if tt.Assert.Len(records, 1) {
+		tt.Assert.Equal("10-test data", records[0].PagingToken())
+	}
+
+	for _, params := range []map[string]string{
+		{},
+		{"sort": "name"},
+	} {
+		_, err = handler.GetResourcePage(
+			httptest.NewRecorder(),
+			makeRequest(t, params, routeParams, q),
+		)
+		if tt.Assert.IsType(&problem.P{}, err) {
+			tt.Assert.Equal("sort", err.(*problem.P).Extras["invalid_field"])
+		}
+	}
+
+	_, err = handler.GetResourcePage(
+		httptest.NewRecorder(),
+		makeRequest(t, map[string]string{"sort": "size", "cursor": "large data"}, routeParams, q),
+	)
+	if tt.Assert.IsType(&problem.P{}, err) {
+		tt.Assert.Equal("cursor", err.(*problem.P).Extras["invalid_field"])
+	}
+}
+
+func TestGetAccountDataEntriesHandlerIncludeReserveCost(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+
+	q := &history.Q{tt.HorizonSession()}
+	handler := GetAccountDataEntriesHandler{}
+
+	_, err := q.InsertLedger(tt.Ctx, xdr.LedgerHeaderHistoryEntry{
+		Header: xdr.LedgerHeader{
+			LedgerSeq:   100,
+			BaseReserve: 5000000,
+		},
+	}, 0, 0, 0, 0, 0)
+	tt.Assert.NoError(err)
+
+	// the sponsored data entry holds 64 bytes, data1 10 bytes
+	sponsoredData := data1
+	entry := *data1.Data.Data
+	entry.DataName = "sponsored data"
+	entry.DataValue = make([]byte, 64)
+	sponsoredData.Data.Data = &entry
+	sponsoredData.Ext = xdr.LedgerEntryExt{
+		V:  1,
+		V1: &xdr.LedgerEntryExtensionV1{SponsoringId: &sponsor},
+	}
+	for _, data := range []xdr.LedgerEntry{data1, sponsoredData} {
+		_, err = q.InsertAccountData(tt.Ctx, data)
+		tt.Assert.NoError(err)
+	}
+
+	routeParams := map[string]string{"account_id": accountOne}
+	records, err := handler.GetResourcePage(
+		httptest.NewRecorder(),
+		makeRequest(t, map[string]string{"sort": "size", "order": "desc"}, routeParams, q),
+	)
+	tt.Assert.NoError(err)
+	for _, record := range records {
+		tt.Assert.Empty(record.(protocol.AccountDataEntry).ReserveStroops)
+	}
+
+	records, err = handler.GetResourcePage(
+		httptest.NewRecorder(),
+		makeRequest(
+			t,
+			map[string]string{"sort": "size", "order": "desc", "include_reserve_cost": "true"},
+			routeParams,
+			q,
+		),
+	)
+	tt.Assert.NoError(err)
+	if tt.Assert.Len(records, 2) {
+		sponsored := records[0].(protocol.AccountDataEntry)
+		tt.Assert.Equal(sponsor.Address(), sponsored.Sponsor)
+		tt.Assert.Equal("5000000", sponsored.ReserveStroops)
+		tt.Assert.Empty(records[1].(protocol.AccountDataEntry).ReserveStroops)
+	}
+}
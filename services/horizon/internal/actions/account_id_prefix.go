@@ -0,0 +1,84 @@
+package actions
+
+import (
+	"net/http"
+	"strings"
+
+	protocol "github.com/stellar/go/protocols/horizon"
+	horizonContext "github.com/stellar/go/services/horizon/internal/context"
+	"github.com/stellar/go/services/horizon/internal/db2"
+	"github.com/stellar/go/services/horizon/internal/db2/history"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/support/render/problem"
+)
+
+// base32Alphabet holds the characters of strkeys.
+const base32Alphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZ234567"
+
+// AccountIDPrefixQuery query struct for the `/accounts/id_prefix` end-point
+type AccountIDPrefixQuery struct {
+	Prefix string `schema:"prefix" valid:"required"`
+	Limit  uint64 `schema:"limit" valid:"-"`
+}
+
+// Validate runs custom validations.
+func (q AccountIDPrefixQuery) Validate() error {
+	if len(q.Prefix) < history.MinAccountIDPrefixLength || len(q.Prefix) > accountStrkeyLength {
+		return problem.MakeInvalidFieldProblem(
+			"prefix",
+			errors.Errorf(
+				"prefix must be between %d and %d characters long",
+				history.MinAccountIDPrefixLength,
+				accountStrkeyLength,
+			),
+		)
+	}
+	if q.Prefix[0] != 'G' || strings.Trim(q.Prefix, base32Alphabet) != "" {
+		return problem.MakeInvalidFieldProblem(
+			"prefix",
+			errors.New("prefix must be the beginning of an account ID: G followed by base32 characters"),
+		)
+	}
+	if q.Limit > db2.MaxPageSize {
+		return problem.MakeInvalidFieldProblem(
+			"limit",
+			errors.Errorf("limit must not be greater than %d", db2.MaxPageSize),
+		)
+	}
+	return nil
+}
+
+// GetAccountIDPrefixHandler is the action handler for the
+// `/accounts/id_prefix` endpoint. It lets explorers autocomplete account ids
+// without loading the accounts.
+type GetAccountIDPrefixHandler struct{}
+
+// GetResource returns the ids of the accounts starting with the prefix, in
+// ascending order.
+func (handler GetAccountIDPrefixHandler) GetResource(w HeaderWriter, r *http.Request) (interface{}, error) {
+	qp := AccountIDPrefixQuery{}
+	if err := getParams(&qp, r); err != nil {
+		return nil, err
+	}
+	limit := qp.Limit
+	if limit == 0 {
+		limit = db2.DefaultPageSize
+	}
+
+	historyQ, err := horizonContext.HistoryQFromRequest(r)
+	if err != nil {
+		return nil, err
+	}
+
+	accountIDs, err := historyQ.AccountsWithIDPrefix(r.Context(), qp.Prefix, int(limit))
+	if err != nil {
+		return nil, errors.Wrap(err, "loading account ids")
+	}
+	if accountIDs == nil {
+		accountIDs = []string{}
+	}
+	return protocol.AccountIDPrefixMatches{
+		Prefix:     qp.Prefix,
+		AccountIDs: accountIDs,
+	}, nil
+}
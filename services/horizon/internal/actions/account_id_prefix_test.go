@@ -0,0 +1,83 @@
+package actions
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	protocol "github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/services/horizon/internal/db2/history"
+	"github.com/stellar/go/services/horizon/internal/test"
+	"github.com/stellar/go/support/render/problem"
+)
+
+func TestGetAccountIDPrefixHandler(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+
+	q := &history.Q{tt.HorizonSession()}
+	handler := GetAccountIDPrefixHandler{}
+
+	// accountOne and accountTwo only share "GA"
+	batch := q.NewAccountsBatchInsertBuilder(0)
+	tt.Assert.NoError(batch.Add(tt.Ctx, account1))
+	tt.Assert.NoError(batch.Add(tt.Ctx, account2))
+	tt.Assert.NoError(batch.Exec(tt.Ctx))
+
+	getMatches := func(params map[string]string) (interface{}, error) {
+		return handler.GetResource(
+			httptest.NewRecorder(),
+			makeRequest(t, params, map[string]string{}, q),
+		)
+	}
+
+	for _, testCase := range []struct {
+		prefix   string
+		expected []string
+	}{
+		{"GABGM", []string{accountOne}},
+		{"GADTX", []string{accountTwo}},
+		{accountTwo, []string{accountTwo}},
+		{"GAAAA", []string{}},
+	} {
+		response, err := getMatches(map[string]string{"prefix": testCase.prefix})
+		tt.Assert.NoError(err)
+		tt.Assert.Equal(protocol.AccountIDPrefixMatches{
+			Prefix:     testCase.prefix,
+			AccountIDs: testCase.expected,
+		}, response)
+	}
+
+	for _, testCase := range []struct {
+		params map[string]string
+		field  string
+		reason string
+	}{
+		{
+			map[string]string{"prefix": "GA"},
+			"prefix",
+			"prefix must be between 5 and 56 characters long",
+		},
+		{
+			map[string]string{"prefix": "gabgm"},
+			"prefix",
+			"prefix must be the beginning of an account ID: G followed by base32 characters",
+		},
+		{
+			map[string]string{"prefix": "MABGM"},
+			"prefix",
+			"prefix must be the beginning of an account ID: G followed by base32 characters",
+		},
+		{
+			map[string]string{"prefix": "GABGM", "limit": "201"},
+			"limit",
+			"limit must not be greater than 200",
+		},
+	} {
+		_, err := getMatches(testCase.params)
+		if tt.Assert.IsType(&problem.P{}, err) {
+			tt.Assert.Equal(testCase.field, err.(*problem.P).Extras["invalid_field"])
+			tt.Assert.Equal(testCase.reason, err.(*problem.P).Extras["reason"])
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package actions
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/stellar/go/services/horizon/internal/context"
+)
+
+// AccountSequenceQuery query struct for account sequence end-point
+type AccountSequenceQuery struct {
+	AccountID string `schema:"account_id" valid:"accountID"`
+}
+
+type accountSequenceResponse struct {
+	Sequence string `json:"sequence"`
+}
+
+func (asr accountSequenceResponse) Equals(other StreamableObjectResponse) bool {
+	other, ok := other.(accountSequenceResponse)
+	if !ok {
+		return false
+	}
+	return asr == other
+}
+
+// GetAccountSequenceHandler is the action handler for the
+// /accounts/{account_id}/sequence endpoint. It only loads the sequence number
+// of the account, which is all clients building transactions need.
+type GetAccountSequenceHandler struct{}
+
+func (handler GetAccountSequenceHandler) GetResource(w HeaderWriter, r *http.Request) (StreamableObjectResponse, error) {
+	qp := AccountSequenceQuery{}
+	if err := getParams(&qp, r); err != nil {
+		return nil, err
+	}
+	historyQ, err := context.HistoryQFromRequest(r)
+	if err != nil {
+		return nil, err
+	}
+	sequence, err := historyQ.SequenceByAddress(r.Context(), qp.AccountID)
+	if err != nil {
+		return nil, err
+	}
+	return accountSequenceResponse{Sequence: strconv.FormatInt(sequence, 10)}, nil
+}
@@ -0,0 +1,35 @@
+package actions
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stellar/go/services/horizon/internal/db2/history"
+	"github.com/stellar/go/services/horizon/internal/test"
+)
+
+func TestGetAccountSequenceHandler(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+
+	q := &history.Q{tt.HorizonSession()}
+	handler := GetAccountSequenceHandler{}
+
+	batch := q.NewAccountsBatchInsertBuilder(0)
+	tt.Assert.NoError(batch.Add(tt.Ctx, account1))
+	tt.Assert.NoError(batch.Exec(tt.Ctx))
+
+	response, err := handler.GetResource(
+		httptest.NewRecorder(),
+		makeRequest(t, map[string]string{}, map[string]string{"account_id": accountOne}, q),
+	)
+	tt.Assert.NoError(err)
+	tt.Assert.Equal(accountSequenceResponse{Sequence: "223456789"}, response)
+
+	_, err = handler.GetResource(
+		httptest.NewRecorder(),
+		makeRequest(t, map[string]string{}, map[string]string{"account_id": accountTwo}, q),
+	)
+	tt.Assert.True(q.NoRows(err))
+}
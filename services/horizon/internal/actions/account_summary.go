@@ -0,0 +1,47 @@
+package actions
+
+import (
+	"net/http"
+
+	protocol "github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/services/horizon/internal/context"
+	"github.com/stellar/go/services/horizon/internal/resourceadapter"
+	"github.com/stellar/go/support/errors"
+)
+
+// AccountSummaryQuery query struct for the /accounts/{account_id}/summary
+// end-point
+type AccountSummaryQuery struct {
+	AccountID string `schema:"account_id" valid:"accountID"`
+}
+
+// GetAccountSummaryHandler is the action handler for the
+// /accounts/{account_id}/summary endpoint. It counts the sub-entries of the
+// account instead of loading them, which is all wallet home screens need.
+type GetAccountSummaryHandler struct{}
+
+// GetResource returns the compact summary of an account.
+func (handler GetAccountSummaryHandler) GetResource(w HeaderWriter, r *http.Request) (interface{}, error) {
+	ctx := r.Context()
+	qp := AccountSummaryQuery{}
+	if err := getParams(&qp, r); err != nil {
+		return nil, err
+	}
+	historyQ, err := context.HistoryQFromRequest(r)
+	if err != nil {
+		return nil, err
+	}
+
+	record, err := historyQ.GetAccountByID(ctx, qp.AccountID)
+	if err != nil {
+		return nil, errors.Wrap(err, "getting history account record")
+	}
+	subentries, err := historyQ.SummarizeAccountSubentries(ctx, qp.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	var summary protocol.AccountActivitySummary
+	resourceadapter.PopulateAccountActivitySummary(&summary, record, subentries)
+	return summary, nil
+}
@@ -0,0 +1,59 @@
+package actions
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	protocol "github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/services/horizon/internal/db2/history"
+	"github.com/stellar/go/services/horizon/internal/test"
+	"github.com/stellar/go/support/errors"
+)
+
+func TestGetAccountSummaryHandler(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+
+	q := &history.Q{tt.HorizonSession()}
+	handler := GetAccountSummaryHandler{}
+
+	batch := q.NewAccountsBatchInsertBuilder(0)
+	tt.Assert.NoError(batch.Add(tt.Ctx, account1))
+	tt.Assert.NoError(batch.Add(tt.Ctx, account2))
+	tt.Assert.NoError(batch.Exec(tt.Ctx))
+	for _, row := range accountSigners {
+		_, err := q.CreateAccountSigner(tt.Ctx, row.Account, row.Signer, row.Weight, nil)
+		tt.Assert.NoError(err)
+	}
+	// the USD trust line of account2 changed after the account itself
+	recentUsdTrustLine := usdTrustLine
+	recentUsdTrustLine.LastModifiedLedgerSeq = 1240
+	_, err := q.InsertTrustLine(tt.Ctx, recentUsdTrustLine)
+	tt.Assert.NoError(err)
+	_, err = q.InsertTrustLine(tt.Ctx, eurTrustLine)
+	tt.Assert.NoError(err)
+	_, err = q.InsertAccountData(tt.Ctx, data2)
+	tt.Assert.NoError(err)
+
+	response, err := handler.GetResource(
+		httptest.NewRecorder(),
+		makeRequest(t, map[string]string{}, map[string]string{"account_id": accountTwo}, q),
+	)
+	tt.Assert.NoError(err)
+	tt.Assert.Equal(protocol.AccountActivitySummary{
+		AccountID:          accountTwo,
+		NumBalances:        2,
+		NativeBalance:      "0.0050000",
+		NumSigners:         2,
+		NumData:            1,
+		Flags:              protocol.AccountFlags{AuthRevocable: true},
+		LastActivityLedger: 1240,
+	}, response)
+
+	_, err = handler.GetResource(
+		httptest.NewRecorder(),
+		makeRequest(t, map[string]string{}, map[string]string{"account_id": signer}, q),
+	)
+	tt.Assert.True(q.NoRows(errors.Cause(err)))
+}
@@ -1,16 +1,34 @@
 package actions
 
 import (
+	"context"
+	"encoding/json"
+	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
+	sq "github.com/Masterminds/squirrel"
+	"github.com/guregu/null"
+	"github.com/jmoiron/sqlx"
+	"github.com/stellar/throttled"
 	"github.com/stretchr/testify/assert"
 
 	protocol "github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/services/horizon/internal/db2"
 	"github.com/stellar/go/services/horizon/internal/db2/history"
+	"github.com/stellar/go/services/horizon/internal/ledger"
+	hProblem "github.com/stellar/go/services/horizon/internal/render/problem"
+	"github.com/stellar/go/services/horizon/internal/resourceadapter"
 	"github.com/stellar/go/services/horizon/internal/test"
+	testAccounts "github.com/stellar/go/services/horizon/internal/test/accounts"
+	"github.com/stellar/go/services/horizon/internal/toid"
+	"github.com/stellar/go/support/db"
 	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/support/render/hal"
 	"github.com/stellar/go/support/render/problem"
 	"github.com/stellar/go/xdr"
 )
@@ -324,6 +342,96 @@ func TestAccountInfo(t *testing.T) {
 	tt.Assert.True(q.NoRows(errors.Cause(err)))
 }
 
+func TestAccountInfoCancelledContext(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+	q := &history.Q{tt.HorizonSession()}
+
+	batch := q.NewAccountsBatchInsertBuilder(0)
+	tt.Assert.NoError(batch.Add(tt.Ctx, account1))
+	tt.Assert.NoError(batch.Exec(tt.Ctx))
+
+	// the request context is passed to the queries, cancelling the request
+	// aborts them
+	ctx, cancel := context.WithCancel(tt.Ctx)
+	cancel()
+	_, err := AccountInfo(ctx, q, accountOne)
+	tt.Assert.Equal(context.Canceled, errors.Cause(err))
+}
+
+// sponsoringEntries returns a copy of the account entry which sponsors
+// numSponsoring entries.
+func sponsoringEntries(entry xdr.LedgerEntry, numSponsoring uint32) xdr.LedgerEntry {
+	account := *entry.Data.Account
+	v1 := *account.Ext.V1
+	v1.Ext = xdr.AccountEntryExtensionV1Ext{
+		V:  2,
+		V2: &xdr.AccountEntryExtensionV2{NumSponsoring: xdr.Uint32(numSponsoring)},
+	}
+	account.Ext.V1 = &v1
+	entry.Data.Account = &account
+	return entry
+}
+
+func TestAccountsInfo(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+	q := &history.Q{tt.HorizonSession()}
+
+	batch := q.NewAccountsBatchInsertBuilder(0)
+	tt.Assert.NoError(batch.Add(tt.Ctx, sponsoringEntries(account1, 1)))
+	tt.Assert.NoError(batch.Add(tt.Ctx, account2))
+	tt.Assert.NoError(batch.Exec(tt.Ctx))
+
+	for _, entry := range []xdr.LedgerEntry{eurTrustLine, usdTrustLine} {
+		_, err := q.InsertTrustLine(tt.Ctx, entry)
+		tt.Assert.NoError(err)
+	}
+	tt.Assert.NoError(q.InsertTrustLineAuthorizations(tt.Ctx, 10, []xdr.LedgerEntry{eurTrustLine, usdTrustLine}))
+	_, err := q.InsertAccountData(tt.Ctx, data1)
+	tt.Assert.NoError(err)
+
+	// accountOne sponsors a signer of accountTwo
+	sponsorID := accountOne
+	for _, account := range []string{accountOne, accountTwo} {
+		_, err = q.CreateAccountSigner(tt.Ctx, account, account, 1, nil)
+		tt.Assert.NoError(err)
+	}
+	_, err = q.CreateAccountSigner(tt.Ctx, accountTwo, signer, 1, &sponsorID)
+	tt.Assert.NoError(err)
+
+	// a missing account is absent from the result
+	missing := "GDBAPLDCAEJV6LSEDFEAUDAVFYSNFRUYZ4X75YYJJMMX5KFVUOHX46SQ"
+	accounts, err := AccountsInfo(tt.Ctx, q, []string{accountOne, accountTwo, missing})
+	tt.Assert.NoError(err)
+	tt.Assert.Len(accounts, 2)
+	tt.Assert.NotContains(accounts, missing)
+
+	// the batched resources match the ones loaded one by one
+	for _, accountID := range []string{accountOne, accountTwo} {
+		expected, err := AccountInfo(tt.Ctx, q, accountID)
+		tt.Assert.NoError(err)
+		tt.Assert.Equal(expected, accounts[accountID])
+	}
+	// the sponsored signers are only included on request
+	tt.Assert.Nil(accounts[accountOne].Sponsoring)
+
+	// all the invalid addresses are reported at once
+	_, err = AccountsInfo(tt.Ctx, q, []string{"GINVALID", accountOne, "GALSOINVALID"})
+	if tt.Assert.IsType(&problem.P{}, err) {
+		fieldErrors := err.(*problem.P).Extras["invalid_values"].([]FieldError)
+		tt.Assert.Len(fieldErrors, 2)
+		tt.Assert.Equal("GINVALID", fieldErrors[0].Value)
+		tt.Assert.Equal("GALSOINVALID", fieldErrors[1].Value)
+	}
+
+	accounts, err = AccountsInfo(tt.Ctx, q, nil)
+	tt.Assert.NoError(err)
+	tt.Assert.Len(accounts, 0)
+}
+
 func TestGetAccountsHandlerPageNoResults(t *testing.T) {
 	tt := test.Start(t)
 	defer tt.Finish()
@@ -389,7 +497,7 @@ func TestGetAccountsHandlerPageResultsBySigner(t *testing.T) {
 	}
 
 	for _, row := range records {
-		result := row.(protocol.Account)
+		result := row.(Account)
 		tt.Assert.True(want[result.AccountID])
 		delete(want, result.AccountID)
 	}
@@ -418,7 +526,7 @@ func TestGetAccountsHandlerPageResultsBySigner(t *testing.T) {
 	}
 
 	for _, row := range records {
-		result := row.(protocol.Account)
+		result := row.(Account)
 		tt.Assert.True(want[result.AccountID])
 		delete(want, result.AccountID)
 	}
@@ -426,6 +534,63 @@ func TestGetAccountsHandlerPageResultsBySigner(t *testing.T) {
 	tt.Assert.Empty(want)
 }
 
+func TestGetAccountsHandlerPageResultsByAllSigners(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+
+	q := &history.Q{tt.HorizonSession()}
+	handler := &GetAccountsHandler{}
+
+	batch := q.NewAccountsBatchInsertBuilder(0)
+	tt.Assert.NoError(batch.Add(tt.Ctx, account1))
+	tt.Assert.NoError(batch.Add(tt.Ctx, account2))
+	tt.Assert.NoError(batch.Add(tt.Ctx, account3))
+	tt.Assert.NoError(batch.Exec(tt.Ctx))
+
+	for _, row := range accountSigners {
+		_, err := q.CreateAccountSigner(tt.Ctx, row.Account, row.Signer, row.Weight, nil)
+		tt.Assert.NoError(err)
+	}
+
+	request := func(signers ...string) *http.Request {
+		r := makeRequest(t, map[string]string{}, map[string]string{}, q)
+		query := url.Values{}
+		for _, s := range signers {
+			query.Add("signer", s)
+		}
+		r.URL.RawQuery = query.Encode()
+		return r
+	}
+	accountIDs := func(records []hal.Pageable) []string {
+		ids := []string{}
+		for _, record := range records {
+			ids = append(ids, record.(Account).AccountID)
+		}
+		return ids
+	}
+
+	// signer signs for every account but only account one is also signed by
+	// accountOne
+	records, err := handler.GetResourcePage(httptest.NewRecorder(), request(signer, accountOne))
+	tt.Assert.NoError(err)
+	tt.Assert.Equal([]string{accountOne}, accountIDs(records))
+
+	records, err = handler.GetResourcePage(httptest.NewRecorder(), request(accountOne, accountTwo))
+	tt.Assert.NoError(err)
+	tt.Assert.Empty(records)
+
+	// a repeated signer behaves like a single one
+	records, err = handler.GetResourcePage(httptest.NewRecorder(), request(signer, signer))
+	tt.Assert.NoError(err)
+	tt.Assert.ElementsMatch([]string{accountOne, accountTwo, signer}, accountIDs(records))
+
+	_, err = handler.GetResourcePage(httptest.NewRecorder(), request(signer, "GNOTANACCOUNT"))
+	if tt.Assert.IsType(&problem.P{}, err) {
+		tt.Assert.Equal("signer", err.(*problem.P).Extras["invalid_field"])
+	}
+}
+
 func TestGetAccountsHandlerPageResultsBySponsor(t *testing.T) {
 	tt := test.Start(t)
 	defer tt.Finish()
@@ -461,7 +626,7 @@ func TestGetAccountsHandlerPageResultsBySponsor(t *testing.T) {
 
 	tt.Assert.NoError(err)
 	tt.Assert.Equal(1, len(records))
-	tt.Assert.Equal(signer, records[0].(protocol.Account).ID)
+	tt.Assert.Equal(signer, records[0].(Account).ID)
 }
 
 func TestGetAccountsHandlerPageResultsByAsset(t *testing.T) {
@@ -535,7 +700,7 @@ func TestGetAccountsHandlerPageResultsByAsset(t *testing.T) {
 
 	tt.Assert.NoError(err)
 	tt.Assert.Equal(1, len(records))
-	result := records[0].(protocol.Account)
+	result := records[0].(Account)
 	tt.Assert.Equal(accountTwo, result.AccountID)
 	tt.Assert.NotNil(result.LastModifiedTime)
 	tt.Assert.Equal(ledgerCloseTime, result.LastModifiedTime.Unix())
@@ -546,97 +711,2639 @@ func TestGetAccountsHandlerPageResultsByAsset(t *testing.T) {
 	tt.Assert.True(ok)
 }
 
-func TestGetAccountsHandlerInvalidParams(t *testing.T) {
-	testCases := []struct {
-		desc                    string
-		params                  map[string]string
-		expectedInvalidField    string
-		expectedErr             string
-		isInvalidAccountsParams bool
-	}{
-		{
-			desc:                    "empty filters",
-			isInvalidAccountsParams: true,
-		},
-		{
-			desc: "signer and seller",
-			params: map[string]string{
-				"signer": accountOne,
-				"asset":  "USD" + ":" + accountOne,
-			},
-			isInvalidAccountsParams: true,
-		},
-		{
-			desc: "signer and sponsor",
-			params: map[string]string{
-				"signer":  accountOne,
-				"sponsor": accountTwo,
-			},
-			isInvalidAccountsParams: true,
-		},
-		{
-			desc: "asset and sponsor",
-			params: map[string]string{
-				"asset":   "USD" + ":" + accountOne,
-				"sponsor": accountTwo,
-			},
-			isInvalidAccountsParams: true,
-		},
-		{
-			desc: "filtering by native asset",
-			params: map[string]string{
-				"asset": "native",
-			},
-			expectedInvalidField: "asset",
-			expectedErr:          "you can't filter by asset: native",
-		},
-		{
-			desc: "invalid asset",
-			params: map[string]string{
-				"asset_issuer": accountOne,
-				"asset":        "USDCOP:someissuer",
-			},
-			expectedInvalidField: "asset",
-			expectedErr:          customTagsErrorMessages["asset"],
-		},
+func TestGetAccountsHandlerPageResultsByAssetOnlyMatchingAsset(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+
+	q := &history.Q{tt.HorizonSession()}
+	handler := &GetAccountsHandler{}
+
+	batch := q.NewAccountsBatchInsertBuilder(0)
+	err := batch.Add(tt.Ctx, account1)
+	assert.NoError(t, err)
+	err = batch.Add(tt.Ctx, account2)
+	assert.NoError(t, err)
+	assert.NoError(t, batch.Exec(tt.Ctx))
+
+	eurTrustLineAccountTwo := eurTrustLine
+	eurTrustLineEntry := *eurTrustLine.Data.TrustLine
+	eurTrustLineEntry.AccountId = xdr.MustAddress(accountTwo)
+	eurTrustLineAccountTwo.Data.TrustLine = &eurTrustLineEntry
+
+	for _, entry := range []xdr.LedgerEntry{eurTrustLine, usdTrustLine, eurTrustLineAccountTwo} {
+		_, err = q.InsertTrustLine(tt.Ctx, entry)
+		assert.NoError(t, err)
 	}
-	for _, tc := range testCases {
-		t.Run(tc.desc, func(t *testing.T) {
-			tt := test.Start(t)
-			defer tt.Finish()
-			q := &history.Q{tt.HorizonSession()}
-			handler := &GetAccountsHandler{}
 
-			_, err := handler.GetResourcePage(
-				httptest.NewRecorder(),
-				makeRequest(
-					t,
-					tc.params,
-					map[string]string{},
-					q,
-				),
-			)
-			tt.Assert.Error(err)
-			if tc.isInvalidAccountsParams {
-				tt.Assert.Equal(invalidAccountsParams, err)
-			} else {
-				if tt.Assert.IsType(&problem.P{}, err) {
-					p := err.(*problem.P)
-					tt.Assert.Equal("bad_request", p.Type)
-					tt.Assert.Equal(tc.expectedInvalidField, p.Extras["invalid_field"])
-					tt.Assert.Equal(
-						tc.expectedErr,
-						p.Extras["reason"],
-					)
-				}
-			}
-		})
+	params := map[string]string{
+		"asset": "USD:" + trustLineIssuer,
 	}
+	records, err := handler.GetResourcePage(
+		httptest.NewRecorder(),
+		makeRequest(t, params, map[string]string{}, q),
+	)
+	tt.Assert.NoError(err)
+	tt.Assert.Len(records, 1)
+	tt.Assert.Len(records[0].(Account).Balances, 3)
+
+	params["only_matching_asset"] = "true"
+	records, err = handler.GetResourcePage(
+		httptest.NewRecorder(),
+		makeRequest(t, params, map[string]string{}, q),
+	)
+	tt.Assert.NoError(err)
+	tt.Assert.Len(records, 1)
+	result := records[0].(Account)
+	tt.Assert.Equal(accountTwo, result.AccountID)
+	tt.Assert.Len(result.Balances, 2)
+	tt.Assert.Equal("USD", result.Balances[0].Code)
+	tt.Assert.Equal(trustLineIssuer, result.Balances[0].Issuer)
+	tt.Assert.Equal("native", result.Balances[1].Type)
 }
 
-func TestAccountQueryURLTemplate(t *testing.T) {
-	tt := assert.New(t)
-	expected := "/accounts{?signer,sponsor,asset,cursor,limit,order}"
-	accountsQuery := AccountsQuery{}
-	tt.Equal(expected, accountsQuery.URITemplate())
+func TestGetAccountsHandlerZeroBalance(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+
+	q := &history.Q{tt.HorizonSession()}
+	handler := &GetAccountsHandler{}
+
+	batch := q.NewAccountsBatchInsertBuilder(0)
+	tt.Assert.NoError(batch.Add(tt.Ctx, account1))
+	tt.Assert.NoError(batch.Add(tt.Ctx, account2))
+	tt.Assert.NoError(batch.Exec(tt.Ctx))
+
+	// account1 opted in to USD without being funded, account2 holds 10000
+	unfundedUsdTrustLine := usdTrustLine
+	trustLine := *usdTrustLine.Data.TrustLine
+	trustLine.AccountId = xdr.MustAddress(accountOne)
+	trustLine.Balance = 0
+	unfundedUsdTrustLine.Data.TrustLine = &trustLine
+	for _, entry := range []xdr.LedgerEntry{unfundedUsdTrustLine, usdTrustLine} {
+		_, err := q.InsertTrustLine(tt.Ctx, entry)
+		tt.Assert.NoError(err)
+	}
+
+	accountIDs := func(params map[string]string) []string {
+		records, err := handler.GetResourcePage(
+			httptest.NewRecorder(),
+			makeRequest(t, params, map[string]string{}, q),
+		)
+		tt.Assert.NoError(err)
+		var ids []string
+		for _, record := range records {
+			ids = append(ids, record.(Account).AccountID)
+		}
+		return ids
+	}
+
+	usd := "USD:" + trustLineIssuer
+	tt.Assert.Equal([]string{accountOne, accountTwo}, accountIDs(map[string]string{"asset": usd}))
+	tt.Assert.Equal(
+		[]string{accountOne},
+		accountIDs(map[string]string{"asset": usd, "zero_balance": "true"}),
+	)
+}
+
+func TestGetAccountsHandlerPageResultsByAssets(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+
+	q := &history.Q{tt.HorizonSession()}
+	handler := &GetAccountsHandler{}
+
+	batch := q.NewAccountsBatchInsertBuilder(0)
+	err := batch.Add(tt.Ctx, account1)
+	assert.NoError(t, err)
+	err = batch.Add(tt.Ctx, account2)
+	assert.NoError(t, err)
+	assert.NoError(t, batch.Exec(tt.Ctx))
+
+	// account one holds EUR, account two holds USD and EUR
+	eurTrustLineAccountTwo := eurTrustLine
+	eurTrustLineEntry := *eurTrustLine.Data.TrustLine
+	eurTrustLineEntry.AccountId = xdr.MustAddress(accountTwo)
+	eurTrustLineAccountTwo.Data.TrustLine = &eurTrustLineEntry
+
+	for _, entry := range []xdr.LedgerEntry{eurTrustLine, usdTrustLine, eurTrustLineAccountTwo} {
+		_, err = q.InsertTrustLine(tt.Ctx, entry)
+		assert.NoError(t, err)
+	}
+
+	request := func(match string) *http.Request {
+		r := makeRequest(t, map[string]string{}, map[string]string{}, q)
+		query := url.Values{}
+		query.Add("assets", "USD:"+trustLineIssuer)
+		query.Add("assets", "EUR:"+trustLineIssuer)
+		if len(match) > 0 {
+			query.Set("match", match)
+		}
+		r.URL.RawQuery = query.Encode()
+		return r
+	}
+	accountIDs := func(records []hal.Pageable) []string {
+		ids := []string{}
+		for _, record := range records {
+			ids = append(ids, record.(Account).AccountID)
+		}
+		return ids
+	}
+
+	for _, match := range []string{"", "any"} {
+		records, err := handler.GetResourcePage(httptest.NewRecorder(), request(match))
+		tt.Assert.NoError(err)
+		tt.Assert.ElementsMatch([]string{accountOne, accountTwo}, accountIDs(records))
+	}
+
+	records, err := handler.GetResourcePage(httptest.NewRecorder(), request("all"))
+	tt.Assert.NoError(err)
+	tt.Assert.Equal([]string{accountTwo}, accountIDs(records))
+	tt.Assert.Len(records[0].(Account).Balances, 3)
+}
+
+func TestGetAccountsHandlerPageResultsByWeakThresholds(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+
+	q := &history.Q{tt.HorizonSession()}
+	handler := &GetAccountsHandler{}
+
+	weakAccount := account2
+	weakAccountEntry := *account2.Data.Account
+	weakAccountEntry.Thresholds = xdr.Thresholds{9, 6, 7, 8}
+	weakAccount.Data.Account = &weakAccountEntry
+
+	batch := q.NewAccountsBatchInsertBuilder(0)
+	// account1's thresholds are {1, 2, 3, 4}
+	tt.Assert.NoError(batch.Add(tt.Ctx, account1))
+	tt.Assert.NoError(batch.Add(tt.Ctx, weakAccount))
+	tt.Assert.NoError(batch.Exec(tt.Ctx))
+
+	records, err := handler.GetResourcePage(
+		httptest.NewRecorder(),
+		makeRequest(t, map[string]string{"weak_thresholds": "true"}, map[string]string{}, q),
+	)
+	tt.Assert.NoError(err)
+	tt.Assert.Len(records, 1)
+	result := records[0].(Account)
+	tt.Assert.Equal(accountTwo, result.AccountID)
+	tt.Assert.True(result.IsThresholdWeak())
+
+	response, err := GetAccountByIDHandler{}.GetResource(
+		httptest.NewRecorder(),
+		makeRequest(t, map[string]string{}, map[string]string{"account_id": accountOne}, q),
+	)
+	tt.Assert.NoError(err)
+	tt.Assert.False(response.(Account).Account.IsThresholdWeak())
+}
+
+func TestGetAccountsHandlerPageResultsByLiabilities(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+
+	q := &history.Q{tt.HorizonSession()}
+	handler := &GetAccountsHandler{}
+
+	noLiabilitiesAccount := account2
+	noLiabilitiesAccountEntry := *account2.Data.Account
+	noLiabilitiesAccountEntry.Ext = xdr.AccountEntryExt{}
+	noLiabilitiesAccount.Data.Account = &noLiabilitiesAccountEntry
+
+	batch := q.NewAccountsBatchInsertBuilder(0)
+	// account1's liabilities are 3/4
+	tt.Assert.NoError(batch.Add(tt.Ctx, account1))
+	tt.Assert.NoError(batch.Add(tt.Ctx, noLiabilitiesAccount))
+	tt.Assert.NoError(batch.Exec(tt.Ctx))
+
+	records, err := handler.GetResourcePage(
+		httptest.NewRecorder(),
+		makeRequest(t, map[string]string{"has_liabilities": "true"}, map[string]string{}, q),
+	)
+	tt.Assert.NoError(err)
+	tt.Assert.Len(records, 1)
+	tt.Assert.Equal(accountOne, records[0].(Account).AccountID)
+}
+
+func TestGetAccountsHandlerPageResultsByNoHomeDomain(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+
+	q := &history.Q{tt.HorizonSession()}
+	handler := &GetAccountsHandler{}
+
+	domainlessAccount := account2
+	domainlessAccountEntry := *account2.Data.Account
+	domainlessAccountEntry.HomeDomain = ""
+	domainlessAccount.Data.Account = &domainlessAccountEntry
+
+	batch := q.NewAccountsBatchInsertBuilder(0)
+	// account1's home domain is stellar.org
+	tt.Assert.NoError(batch.Add(tt.Ctx, account1))
+	tt.Assert.NoError(batch.Add(tt.Ctx, domainlessAccount))
+	tt.Assert.NoError(batch.Exec(tt.Ctx))
+
+	records, err := handler.GetResourcePage(
+		httptest.NewRecorder(),
+		makeRequest(t, map[string]string{"no_home_domain": "true"}, map[string]string{}, q),
+	)
+	tt.Assert.NoError(err)
+	if tt.Assert.Len(records, 1) {
+		account := records[0].(Account)
+		tt.Assert.Equal(accountTwo, account.AccountID)
+		tt.Assert.Empty(account.HomeDomain)
+	}
+}
+
+func TestGetAccountsHandlerPageResultsByHomeDomain(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+
+	q := &history.Q{tt.HorizonSession()}
+	handler := &GetAccountsHandler{}
+
+	// account1's home domain is stellar.org, account2's is
+	// meridian.stellar.org
+	batch := q.NewAccountsBatchInsertBuilder(0)
+	tt.Assert.NoError(batch.Add(tt.Ctx, account1))
+	tt.Assert.NoError(batch.Add(tt.Ctx, account2))
+	tt.Assert.NoError(batch.Exec(tt.Ctx))
+
+	records, err := handler.GetResourcePage(
+		httptest.NewRecorder(),
+		makeRequest(t, map[string]string{"home_domain": "stellar.org"}, map[string]string{}, q),
+	)
+	tt.Assert.NoError(err)
+	if tt.Assert.Len(records, 1) {
+		account := records[0].(Account)
+		tt.Assert.Equal(accountOne, account.AccountID)
+		tt.Assert.Equal("stellar.org", account.HomeDomain)
+	}
+
+	// the filter can't be combined with another one
+	_, err = handler.GetResourcePage(
+		httptest.NewRecorder(),
+		makeRequest(
+			t,
+			map[string]string{"home_domain": "stellar.org", "signer": accountOne},
+			map[string]string{},
+			q,
+		),
+	)
+	tt.Assert.Equal(invalidAccountsParams, err)
+}
+
+func TestGetAccountsHandlerHead(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+
+	q := &history.Q{tt.HorizonSession()}
+	handler := &GetAccountsHandler{}
+
+	batch := q.NewAccountsBatchInsertBuilder(0)
+	tt.Assert.NoError(batch.Add(tt.Ctx, account1))
+	tt.Assert.NoError(batch.Exec(tt.Ctx))
+	_, err := q.CreateAccountSigner(tt.Ctx, accountOne, signer, 1, nil)
+	tt.Assert.NoError(err)
+
+	// the accounts of HEAD requests aren't populated
+	r := makeRequest(t, map[string]string{"signer": signer}, map[string]string{}, q)
+	r.Method = http.MethodHead
+	records, err := handler.GetResourcePage(httptest.NewRecorder(), r)
+	tt.Assert.NoError(err)
+	tt.Assert.Empty(records)
+
+	// the parameters are still validated
+	r = makeRequest(t, map[string]string{"signer": "GNOTANACCOUNT"}, map[string]string{}, q)
+	r.Method = http.MethodHead
+	_, err = handler.GetResourcePage(httptest.NewRecorder(), r)
+	if tt.Assert.IsType(&problem.P{}, err) {
+		tt.Assert.Equal("signer", err.(*problem.P).Extras["invalid_field"])
+	}
+}
+
+func TestGetAccountsHandlerPageResultsBySignerType(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+
+	q := &history.Q{tt.HorizonSession()}
+	handler := &GetAccountsHandler{}
+
+	batch := q.NewAccountsBatchInsertBuilder(0)
+	tt.Assert.NoError(batch.Add(tt.Ctx, account1))
+	tt.Assert.NoError(batch.Add(tt.Ctx, account2))
+	tt.Assert.NoError(batch.Exec(tt.Ctx))
+
+	// both accounts have their master key, only accountTwo has a preauth_tx
+	// signer
+	preAuthSigner := "TBEZPYEOQNR2QDXVA2N7UVR7FDN2OFS4HSPTEGZAJLIV77NWK4VQP27G"
+	for _, row := range []history.AccountSigner{
+		{Account: accountOne, Signer: accountOne, Weight: 1},
+		{Account: accountTwo, Signer: accountTwo, Weight: 1},
+		{Account: accountTwo, Signer: preAuthSigner, Weight: 1},
+	} {
+		_, err := q.CreateAccountSigner(tt.Ctx, row.Account, row.Signer, row.Weight, nil)
+		tt.Assert.NoError(err)
+	}
+
+	records, err := handler.GetResourcePage(
+		httptest.NewRecorder(),
+		makeRequest(t, map[string]string{"signer_type": "preauth_tx"}, map[string]string{}, q),
+	)
+	tt.Assert.NoError(err)
+	if tt.Assert.Len(records, 1) {
+		account := records[0].(Account)
+		tt.Assert.Equal(accountTwo, account.AccountID)
+	}
+
+	records, err = handler.GetResourcePage(
+		httptest.NewRecorder(),
+		makeRequest(t, map[string]string{"signer_type": "sha256_hash"}, map[string]string{}, q),
+	)
+	tt.Assert.NoError(err)
+	tt.Assert.Len(records, 0)
+}
+
+func TestGetAccountsHandlerPageResultsByRowIDCursor(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+
+	q := &history.Q{tt.HorizonSession()}
+	handler := &GetAccountsHandler{}
+
+	// accountTwo is inserted first, so it has the lowest row id
+	for _, entry := range []xdr.LedgerEntry{account2, account1} {
+		batch := q.NewAccountsBatchInsertBuilder(0)
+		tt.Assert.NoError(batch.Add(tt.Ctx, entry))
+		tt.Assert.NoError(batch.Exec(tt.Ctx))
+	}
+	for _, account := range []string{accountOne, accountTwo} {
+		_, err := q.CreateAccountSigner(tt.Ctx, account, signer, 1, nil)
+		tt.Assert.NoError(err)
+	}
+
+	getPage := func(cursor string) []hal.Pageable {
+		records, err := handler.GetResourcePage(
+			httptest.NewRecorder(),
+			makeRequest(
+				t,
+				map[string]string{"signer": signer, "cursor": cursor, "limit": "1"},
+				map[string]string{},
+				q,
+			),
+		)
+		tt.Assert.NoError(err)
+		return records
+	}
+
+	records := getPage("0")
+	if tt.Assert.Len(records, 1) {
+		account := records[0].(Account)
+		tt.Assert.Equal(accountTwo, account.AccountID)
+		_, err := strconv.ParseInt(account.PT, 10, 64)
+		tt.Assert.NoError(err)
+
+		records = getPage(account.PT)
+		if tt.Assert.Len(records, 1) {
+			tt.Assert.Equal(accountOne, records[0].(Account).AccountID)
+			tt.Assert.Len(getPage(records[0].PagingToken()), 0)
+		}
+	}
+
+	// the account id cursor is still supported
+	records = getPage(accountOne)
+	if tt.Assert.Len(records, 1) {
+		account := records[0].(Account)
+		tt.Assert.Equal(accountTwo, account.AccountID)
+		tt.Assert.Equal(accountTwo, account.PT)
+	}
+
+	_, err := handler.GetResourcePage(
+		httptest.NewRecorder(),
+		makeRequest(t, map[string]string{"sponsor": sponsor.Address(), "cursor": "1"}, map[string]string{}, q),
+	)
+	if tt.Assert.IsType(&problem.P{}, err) {
+		p := err.(*problem.P)
+		tt.Assert.Equal("cursor", p.Extras["invalid_field"])
+		tt.Assert.Equal("numeric cursors can only be used with the signer and asset filters", p.Extras["reason"])
+	}
+}
+
+func TestGetAccountsHandlerTopHolders(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+
+	q := &history.Q{tt.HorizonSession()}
+	handler := &GetAccountsHandler{}
+
+	batch := q.NewAccountsBatchInsertBuilder(0)
+	tt.Assert.NoError(batch.Add(tt.Ctx, account1))
+	tt.Assert.NoError(batch.Add(tt.Ctx, account2))
+	tt.Assert.NoError(batch.Exec(tt.Ctx))
+
+	// accountTwo holds 10000 USD, accountOne holds more and sorts first
+	largerUsdTrustLine := usdTrustLine
+	trustLine := *usdTrustLine.Data.TrustLine
+	trustLine.AccountId = xdr.MustAddress(accountOne)
+	trustLine.Balance = 30000
+	largerUsdTrustLine.Data.TrustLine = &trustLine
+	for _, entry := range []xdr.LedgerEntry{usdTrustLine, largerUsdTrustLine} {
+		_, err := q.InsertTrustLine(tt.Ctx, entry)
+		tt.Assert.NoError(err)
+	}
+
+	for _, testCase := range []struct {
+		top      string
+		expected []string
+	}{
+		{"2", []string{accountOne, accountTwo}},
+		{"1", []string{accountOne}},
+	} {
+		records, err := handler.GetResourcePage(
+			httptest.NewRecorder(),
+			makeRequest(
+				t,
+				map[string]string{"asset": "USD:" + trustLineIssuer, "top": testCase.top},
+				map[string]string{},
+				q,
+			),
+		)
+		tt.Assert.NoError(err)
+		if tt.Assert.Len(records, len(testCase.expected)) {
+			for i, record := range records {
+				account := record.(Account)
+				tt.Assert.Equal(testCase.expected[i], account.AccountID)
+				tt.Assert.Equal("USD", account.Balances[0].Code)
+			}
+		}
+	}
+
+	// holders with the same balance are ordered by account id
+	trustLine.Balance = 10000
+	_, err := q.UpdateTrustLine(tt.Ctx, largerUsdTrustLine)
+	tt.Assert.NoError(err)
+	records, err := q.TopHoldersForAsset(tt.Ctx, usd, 2)
+	tt.Assert.NoError(err)
+	if tt.Assert.Len(records, 2) {
+		tt.Assert.Equal(accountOne, records[0].AccountID)
+		tt.Assert.Equal(accountTwo, records[1].AccountID)
+	}
+}
+
+func TestGetAccountsHandlerStrictParams(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+
+	q := &history.Q{tt.HorizonSession()}
+	params := map[string]string{"signerr": accountOne}
+
+	// lenient by default, the typo'd filter is ignored
+	_, err := (&GetAccountsHandler{}).GetResourcePage(
+		httptest.NewRecorder(),
+		makeRequest(t, params, map[string]string{}, q),
+	)
+	tt.Assert.Equal(invalidAccountsParams, err)
+
+	params["strict_params"] = "true"
+	_, err = (&GetAccountsHandler{}).GetResourcePage(
+		httptest.NewRecorder(),
+		makeRequest(t, params, map[string]string{}, q),
+	)
+	if tt.Assert.IsType(&problem.P{}, err) {
+		p := err.(*problem.P)
+		tt.Assert.Equal("bad_request", p.Type)
+		tt.Assert.Equal("The request has unrecognized query parameters: signerr", p.Detail)
+		tt.Assert.Equal([]string{"signerr"}, p.Extras["unknown_params"])
+	}
+
+	// strict mode enabled by the operator
+	delete(params, "strict_params")
+	params["limitt"] = "1"
+	_, err = (&GetAccountsHandler{StrictParams: true}).GetResourcePage(
+		httptest.NewRecorder(),
+		makeRequest(t, params, map[string]string{}, q),
+	)
+	if tt.Assert.IsType(&problem.P{}, err) {
+		tt.Assert.Equal([]string{"limitt", "signerr"}, err.(*problem.P).Extras["unknown_params"])
+	}
+
+	// known parameters are accepted in strict mode
+	_, err = (&GetAccountsHandler{StrictParams: true}).GetResourcePage(
+		httptest.NewRecorder(),
+		makeRequest(
+			t,
+			map[string]string{"signer": accountOne, "limit": "1", "strict_params": "true"},
+			map[string]string{},
+			q,
+		),
+	)
+	tt.Assert.NoError(err)
+
+	_, err = (&GetAccountsHandler{}).GetResourcePage(
+		httptest.NewRecorder(),
+		makeRequest(t, map[string]string{"strict_params": "yes"}, map[string]string{}, q),
+	)
+	tt.Assert.Equal(problem.MakeInvalidFieldProblem("strict_params", errors.New("unparseable value")), err)
+}
+
+func TestGetAccountsHandlerPageResultsByThreshold(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+
+	q := &history.Q{tt.HorizonSession()}
+	handler := &GetAccountsHandler{}
+
+	// the medium threshold of account1 is 3, the one of account2 is 7
+	batch := q.NewAccountsBatchInsertBuilder(0)
+	tt.Assert.NoError(batch.Add(tt.Ctx, account1))
+	tt.Assert.NoError(batch.Add(tt.Ctx, account2))
+	tt.Assert.NoError(batch.Exec(tt.Ctx))
+
+	for _, testCase := range []struct {
+		op       string
+		value    string
+		expected []string
+	}{
+		{"gte", "2", []string{accountOne, accountTwo}},
+		{"gte", "4", []string{accountTwo}},
+		{"lte", "3", []string{accountOne}},
+		{"lte", "2", []string{}},
+	} {
+		records, err := handler.GetResourcePage(
+			httptest.NewRecorder(),
+			makeRequest(
+				t,
+				map[string]string{
+					"threshold":       "med",
+					"threshold_op":    testCase.op,
+					"threshold_value": testCase.value,
+				},
+				map[string]string{},
+				q,
+			),
+		)
+		tt.Assert.NoError(err)
+		accountIDs := []string{}
+		for _, record := range records {
+			accountIDs = append(accountIDs, record.(Account).AccountID)
+		}
+		tt.Assert.Equal(testCase.expected, accountIDs)
+	}
+}
+
+func TestGetAccountsHandlerPageResultsByDataValue(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+
+	q := &history.Q{tt.HorizonSession()}
+	handler := &GetAccountsHandler{}
+
+	batch := q.NewAccountsBatchInsertBuilder(0)
+	tt.Assert.NoError(batch.Add(tt.Ctx, account1))
+	tt.Assert.NoError(batch.Add(tt.Ctx, account2))
+	tt.Assert.NoError(batch.Add(tt.Ctx, account3))
+	tt.Assert.NoError(batch.Exec(tt.Ctx))
+
+	// accountTwo holds the same "test data" value as accountOne, the signer
+	// account a different one
+	sharedData := data1
+	sharedDataEntry := *data1.Data.Data
+	sharedDataEntry.AccountId = xdr.MustAddress(accountTwo)
+	sharedData.Data.Data = &sharedDataEntry
+
+	otherData := data1
+	otherDataEntry := *data1.Data.Data
+	otherDataEntry.AccountId = xdr.MustAddress(signer)
+	otherDataEntry.DataValue = []byte{9, 8, 7}
+	otherData.Data.Data = &otherDataEntry
+
+	for _, data := range []xdr.LedgerEntry{data1, sharedData, otherData} {
+		_, err := q.InsertAccountData(tt.Ctx, data)
+		tt.Assert.NoError(err)
+	}
+
+	records, err := handler.GetResourcePage(
+		httptest.NewRecorder(),
+		makeRequest(
+			t,
+			map[string]string{"data_name": "test data", "data_value": "AAECAwQFBgcICQ=="},
+			map[string]string{},
+			q,
+		),
+	)
+	tt.Assert.NoError(err)
+	if tt.Assert.Len(records, 2) {
+		tt.Assert.Equal(accountOne, records[0].(Account).AccountID)
+		tt.Assert.Equal(accountTwo, records[1].(Account).AccountID)
+	}
+}
+
+func TestGetAccountsHandlerSortedByNativeBalance(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+
+	q := &history.Q{tt.HorizonSession()}
+	handler := &GetAccountsHandler{}
+
+	// account1 has 20000 stroops and account2 50000
+	batch := q.NewAccountsBatchInsertBuilder(0)
+	tt.Assert.NoError(batch.Add(tt.Ctx, account1))
+	tt.Assert.NoError(batch.Add(tt.Ctx, account2))
+	tt.Assert.NoError(batch.Exec(tt.Ctx))
+
+	records, err := handler.GetResourcePage(
+		httptest.NewRecorder(),
+		makeRequest(t, map[string]string{"sort": "native_balance", "order": "desc"}, map[string]string{}, q),
+	)
+	tt.Assert.NoError(err)
+	tt.Assert.Len(records, 2)
+	richest := records[0].(Account)
+	tt.Assert.Equal(accountTwo, richest.AccountID)
+	tt.Assert.Equal("50000-"+accountTwo, richest.PagingToken())
+	tt.Assert.NotEmpty(richest.Signers)
+	tt.Assert.Equal(accountOne, records[1].(Account).AccountID)
+
+	records, err = handler.GetResourcePage(
+		httptest.NewRecorder(),
+		makeRequest(t, map[string]string{
+			"sort":   "native_balance",
+			"order":  "desc",
+			"cursor": richest.PagingToken(),
+		}, map[string]string{}, q),
+	)
+	tt.Assert.NoError(err)
+	tt.Assert.Len(records, 1)
+	tt.Assert.Equal(accountOne, records[0].(Account).AccountID)
+
+	for _, params := range []map[string]string{
+		{"sort": "native_balance", "cursor": accountOne},
+		{"sort": "native_balance", "signer": accountOne},
+		{"sort": "trustlines"},
+	} {
+		_, err = handler.GetResourcePage(
+			httptest.NewRecorder(),
+			makeRequest(t, params, map[string]string{}, q),
+		)
+		if tt.Assert.IsType(&problem.P{}, err) {
+			tt.Assert.Equal(http.StatusBadRequest, err.(*problem.P).Status)
+		}
+	}
+}
+
+func TestGetAccountsHandlerModified(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+
+	q := &history.Q{tt.HorizonSession()}
+	handler := &GetAccountsHandler{}
+
+	// account1 is modified at 1234 and account2 at 1235
+	modifiedAccount2 := account2
+	modifiedAccount2.LastModifiedLedgerSeq = 1235
+	batch := q.NewAccountsBatchInsertBuilder(0)
+	tt.Assert.NoError(batch.Add(tt.Ctx, account1))
+	tt.Assert.NoError(batch.Add(tt.Ctx, modifiedAccount2))
+	tt.Assert.NoError(batch.Exec(tt.Ctx))
+
+	for _, testCase := range []struct {
+		params   map[string]string
+		expected []string
+	}{
+		{map[string]string{"modified_from": "1234", "modified_to": "1234"}, []string{accountOne}},
+		{map[string]string{"modified_from": "1235", "modified_to": "1235"}, []string{accountTwo}},
+		{map[string]string{"modified_from": "1234", "modified_to": "1235"}, []string{accountOne, accountTwo}},
+		{map[string]string{"modified_from": "1235"}, []string{accountTwo}},
+		{map[string]string{"modified_to": "1234"}, []string{accountOne}},
+		{map[string]string{"modified_from": "1236"}, []string{}},
+	} {
+		records, err := handler.GetResourcePage(
+			httptest.NewRecorder(),
+			makeRequest(t, testCase.params, map[string]string{}, q),
+		)
+		tt.Assert.NoError(err)
+		ids := []string{}
+		for _, record := range records {
+			account := record.(Account)
+			tt.Assert.NotEmpty(account.Signers)
+			ids = append(ids, account.AccountID)
+		}
+		tt.Assert.Equal(testCase.expected, ids)
+	}
+
+	_, err := handler.GetResourcePage(
+		httptest.NewRecorder(),
+		makeRequest(t, map[string]string{"modified_from": "1235", "modified_to": "1234"}, map[string]string{}, q),
+	)
+	if tt.Assert.IsType(&problem.P{}, err) {
+		tt.Assert.Equal("modified_from", err.(*problem.P).Extras["invalid_field"])
+	}
+
+	_, err = handler.GetResourcePage(
+		httptest.NewRecorder(),
+		makeRequest(t, map[string]string{"modified_from": "1234", "signer": accountOne}, map[string]string{}, q),
+	)
+	tt.Assert.Equal(invalidAccountsParams, err)
+}
+
+func TestGetAccountsHandlerSummary(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+
+	q := &history.Q{tt.HorizonSession()}
+	handler := &GetAccountsHandler{}
+
+	batch := q.NewAccountsBatchInsertBuilder(0)
+	tt.Assert.NoError(batch.Add(tt.Ctx, account2))
+	tt.Assert.NoError(batch.Exec(tt.Ctx))
+	for _, row := range accountSigners {
+		if row.Account == accountTwo {
+			_, err := q.CreateAccountSigner(tt.Ctx, row.Account, row.Signer, row.Weight, nil)
+			tt.Assert.NoError(err)
+		}
+	}
+	accountTwoEurTrustLine := eurTrustLine
+	trustLine := *eurTrustLine.Data.TrustLine
+	trustLine.AccountId = xdr.MustAddress(accountTwo)
+	accountTwoEurTrustLine.Data.TrustLine = &trustLine
+	for _, entry := range []xdr.LedgerEntry{usdTrustLine, accountTwoEurTrustLine} {
+		_, err := q.InsertTrustLine(tt.Ctx, entry)
+		tt.Assert.NoError(err)
+	}
+	_, err := q.InsertAccountData(tt.Ctx, data2)
+	tt.Assert.NoError(err)
+
+	records, err := handler.GetResourcePage(
+		httptest.NewRecorder(),
+		makeRequest(t, map[string]string{
+			"asset":   "USD:" + trustLineIssuer,
+			"summary": "true",
+		}, map[string]string{}, q),
+	)
+	tt.Assert.NoError(err)
+	tt.Assert.Len(records, 1)
+
+	account := records[0].(Account)
+	tt.Assert.Equal(accountTwo, account.AccountID)
+	tt.Assert.Equal(
+		&protocol.AccountSummary{NumSigners: 2, NumTrustLines: 2, NumData: 1},
+		account.Summary,
+	)
+	tt.Assert.False(account.IsImmutable)
+	tt.Assert.Nil(account.Signers)
+	tt.Assert.Nil(account.Data)
+	if tt.Assert.Len(account.Balances, 1) {
+		tt.Assert.Equal("native", account.Balances[0].Type)
+	}
+	marshaled, err := json.Marshal(account)
+	tt.Assert.NoError(err)
+	// _links holds a data link, only the top-level data key must be omitted
+	var fields map[string]json.RawMessage
+	tt.Assert.NoError(json.Unmarshal(marshaled, &fields))
+	tt.Assert.NotContains(fields, "data")
+
+	// without summary the sub-entries are loaded
+	records, err = handler.GetResourcePage(
+		httptest.NewRecorder(),
+		makeRequest(t, map[string]string{"asset": "USD:" + trustLineIssuer}, map[string]string{}, q),
+	)
+	tt.Assert.NoError(err)
+	account = records[0].(Account)
+	tt.Assert.Nil(account.Summary)
+	tt.Assert.Len(account.Signers, 2)
+	tt.Assert.Len(account.Balances, 3)
+}
+
+func TestGetAccountsHandlerHistoryStale(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+
+	q := &history.Q{tt.HorizonSession()}
+	ledgerState := &ledger.State{}
+	handler := &GetAccountsHandler{LedgerState: ledgerState, StaleThreshold: 10}
+
+	batch := q.NewAccountsBatchInsertBuilder(0)
+	tt.Assert.NoError(batch.Add(tt.Ctx, account1))
+	tt.Assert.NoError(batch.Exec(tt.Ctx))
+	_, err := q.InsertTrustLine(tt.Ctx, eurTrustLine)
+	tt.Assert.NoError(err)
+
+	getAccounts := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		records, err := handler.GetResourcePage(
+			w,
+			makeRequest(t, map[string]string{"asset": "EUR:" + trustLineIssuer}, map[string]string{}, q),
+		)
+		tt.Assert.NoError(err)
+		tt.Assert.Len(records, 1)
+		return w
+	}
+
+	// history is up to date with core
+	ledgerState.SetStatus(ledger.Status{CoreLatest: 1250, HistoryLatest: 1245})
+	tt.Assert.Empty(getAccounts().Header().Get(HistoryStaleHeaderName))
+
+	// history is 20 ledgers behind core, the results are still served
+	ledgerState.SetStatus(ledger.Status{CoreLatest: 1265, HistoryLatest: 1245})
+	tt.Assert.Equal("true", getAccounts().Header().Get(HistoryStaleHeaderName))
+
+	// the header is never set without a threshold
+	handler.StaleThreshold = 0
+	tt.Assert.Empty(getAccounts().Header().Get(HistoryStaleHeaderName))
+}
+
+func TestGetAccountsHandlerCursorNow(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+
+	q := &history.Q{tt.HorizonSession()}
+	handler := &GetAccountsHandler{LedgerState: &ledger.State{}}
+
+	batch := q.NewAccountsBatchInsertBuilder(0)
+	tt.Assert.NoError(batch.Add(tt.Ctx, account1))
+	tt.Assert.NoError(batch.Add(tt.Ctx, account2))
+	tt.Assert.NoError(batch.Exec(tt.Ctx))
+	for _, row := range accountSigners {
+		_, err := q.CreateAccountSigner(tt.Ctx, row.Account, row.Signer, row.Weight, nil)
+		tt.Assert.NoError(err)
+	}
+
+	params := map[string]string{"signer": signer}
+	records, err := handler.GetResourcePage(
+		httptest.NewRecorder(),
+		makeRequest(t, params, map[string]string{}, q),
+	)
+	tt.Assert.NoError(err)
+	tt.Assert.Len(records, 2)
+
+	// existing accounts are not replayed
+	params["cursor"] = "now"
+	records, err = handler.GetResourcePage(
+		httptest.NewRecorder(),
+		makeRequest(t, params, map[string]string{}, q),
+	)
+	tt.Assert.NoError(err)
+	tt.Assert.Len(records, 0)
+}
+
+func TestGetAccountsHandlerInvalidParams(t *testing.T) {
+	testCases := []struct {
+		desc                    string
+		params                  map[string]string
+		expectedInvalidField    string
+		expectedErr             string
+		isInvalidAccountsParams bool
+	}{
+		{
+			desc:                    "empty filters",
+			isInvalidAccountsParams: true,
+		},
+		{
+			desc: "signer and seller",
+			params: map[string]string{
+				"signer": accountOne,
+				"asset":  "USD" + ":" + accountOne,
+			},
+			isInvalidAccountsParams: true,
+		},
+		{
+			desc: "signer and sponsor",
+			params: map[string]string{
+				"signer":  accountOne,
+				"sponsor": accountTwo,
+			},
+			isInvalidAccountsParams: true,
+		},
+		{
+			desc: "asset and sponsor",
+			params: map[string]string{
+				"asset":   "USD" + ":" + accountOne,
+				"sponsor": accountTwo,
+			},
+			isInvalidAccountsParams: true,
+		},
+		{
+			desc: "signer with a cursor which isn't an account id",
+			params: map[string]string{
+				"signer": accountOne,
+				"cursor": "12345",
+			},
+			expectedInvalidField: "cursor",
+			expectedErr:          "account ID must be 56 characters long, got 5",
+		},
+		{
+			desc: "signer with a cursor which isn't a valid strkey",
+			params: map[string]string{
+				"signer": accountOne,
+				"cursor": "GAOQJGUAB7NI7K7I62ORBXMN3J4SSWQUQ7FOEPSDJ322W2HMCNWPHXF0",
+			},
+			expectedInvalidField: "cursor",
+			expectedErr:          "account ID must be base32 encoded",
+		},
+		{
+			desc: "only_matching_asset with summary",
+			params: map[string]string{
+				"asset":               "USD" + ":" + accountOne,
+				"only_matching_asset": "true",
+				"summary":             "true",
+			},
+			expectedInvalidField: "only_matching_asset",
+			expectedErr:          "only_matching_asset can't be used with summary",
+		},
+		{
+			desc: "filtering by native asset",
+			params: map[string]string{
+				"asset": "native",
+			},
+			expectedInvalidField: "asset",
+			expectedErr:          "you can't filter by asset: native",
+		},
+		{
+			desc: "only_matching_asset without asset",
+			params: map[string]string{
+				"signer":              accountOne,
+				"only_matching_asset": "true",
+			},
+			expectedInvalidField: "only_matching_asset",
+			expectedErr:          "only_matching_asset can only be used with the asset filter",
+		},
+		{
+			desc: "zero_balance without asset",
+			params: map[string]string{
+				"signer":       accountOne,
+				"zero_balance": "true",
+			},
+			expectedInvalidField: "zero_balance",
+			expectedErr:          "zero_balance can only be used with the asset filter",
+		},
+		{
+			desc: "top without asset",
+			params: map[string]string{
+				"signer": accountOne,
+				"top":    "10",
+			},
+			expectedInvalidField: "top",
+			expectedErr:          "top can only be used with the asset filter",
+		},
+		{
+			desc: "top above the cap",
+			params: map[string]string{
+				"asset": "USD" + ":" + accountOne,
+				"top":   "201",
+			},
+			expectedInvalidField: "top",
+			expectedErr:          "top must not be greater than 200",
+		},
+		{
+			desc: "top with zero_balance",
+			params: map[string]string{
+				"asset":        "USD" + ":" + accountOne,
+				"top":          "10",
+				"zero_balance": "true",
+			},
+			expectedInvalidField: "top",
+			expectedErr:          "top can't be used with zero_balance",
+		},
+		{
+			desc: "asset and assets",
+			params: map[string]string{
+				"asset":  "USD" + ":" + accountOne,
+				"assets": "EUR" + ":" + accountOne,
+			},
+			isInvalidAccountsParams: true,
+		},
+		{
+			desc: "weak_thresholds and signer",
+			params: map[string]string{
+				"weak_thresholds": "true",
+				"signer":          accountOne,
+			},
+			isInvalidAccountsParams: true,
+		},
+		{
+			desc: "has_liabilities and weak_thresholds",
+			params: map[string]string{
+				"has_liabilities": "true",
+				"weak_thresholds": "true",
+			},
+			isInvalidAccountsParams: true,
+		},
+		{
+			desc: "no_home_domain and has_liabilities",
+			params: map[string]string{
+				"no_home_domain":  "true",
+				"has_liabilities": "true",
+			},
+			isInvalidAccountsParams: true,
+		},
+		{
+			desc: "signer_type and signer",
+			params: map[string]string{
+				"signer_type": "preauth_tx",
+				"signer":      accountOne,
+			},
+			isInvalidAccountsParams: true,
+		},
+		{
+			desc: "unknown signer_type",
+			params: map[string]string{
+				"signer_type": "preauth",
+			},
+			expectedInvalidField: "signer_type",
+			expectedErr:          "unknown signer type: preauth",
+		},
+		{
+			desc: "threshold and weak_thresholds",
+			params: map[string]string{
+				"threshold":       "med",
+				"threshold_op":    "gte",
+				"weak_thresholds": "true",
+			},
+			isInvalidAccountsParams: true,
+		},
+		{
+			desc: "invalid threshold",
+			params: map[string]string{
+				"threshold":    "master",
+				"threshold_op": "gte",
+			},
+			expectedInvalidField: "threshold",
+			expectedErr:          "Accepted values: low; med or high",
+		},
+		{
+			desc: "threshold without threshold_op",
+			params: map[string]string{
+				"threshold": "med",
+			},
+			expectedInvalidField: "threshold_op",
+			expectedErr:          "threshold_op is required by the threshold filter",
+		},
+		{
+			desc: "threshold_op without threshold",
+			params: map[string]string{
+				"threshold_op": "gte",
+				"signer":       accountOne,
+			},
+			expectedInvalidField: "threshold_op",
+			expectedErr:          "threshold_op can only be used with the threshold filter",
+		},
+		{
+			desc: "data_name without data_value",
+			params: map[string]string{
+				"data_name": "test data",
+			},
+			expectedInvalidField: "data_value",
+			expectedErr:          "data_value is required by the data_name filter",
+		},
+		{
+			desc: "data_value without data_name",
+			params: map[string]string{
+				"data_value": "AAECAwQFBgcICQ==",
+				"signer":     accountOne,
+			},
+			expectedInvalidField: "data_value",
+			expectedErr:          "data_value can only be used with the data_name filter",
+		},
+		{
+			desc: "data_value not base64 encoded",
+			params: map[string]string{
+				"data_name":  "test data",
+				"data_value": "not base64!",
+			},
+			expectedInvalidField: "data_value",
+			expectedErr:          "data_value must be base64 encoded",
+		},
+		{
+			desc: "filtering assets by native asset",
+			params: map[string]string{
+				"assets": "native",
+			},
+			expectedInvalidField: "assets",
+			expectedErr:          "you can't filter by asset: native",
+		},
+		{
+			desc: "invalid assets",
+			params: map[string]string{
+				"assets": "USDCOP:someissuer",
+			},
+			expectedInvalidField: "assets",
+			expectedErr:          customTagsErrorMessages["asset"],
+		},
+		{
+			desc: "match without assets",
+			params: map[string]string{
+				"asset": "USD" + ":" + accountOne,
+				"match": "all",
+			},
+			expectedInvalidField: "match",
+			expectedErr:          "match can only be used with the assets filter",
+		},
+		{
+			desc: "invalid match",
+			params: map[string]string{
+				"assets": "USD" + ":" + accountOne,
+				"match":  "some",
+			},
+			expectedInvalidField: "match",
+			expectedErr:          "Accepted values: any or all",
+		},
+		{
+			desc: "invalid asset",
+			params: map[string]string{
+				"asset_issuer": accountOne,
+				"asset":        "USDCOP:someissuer",
+			},
+			expectedInvalidField: "asset",
+			expectedErr:          customTagsErrorMessages["asset"],
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			tt := test.Start(t)
+			defer tt.Finish()
+			q := &history.Q{tt.HorizonSession()}
+			handler := &GetAccountsHandler{}
+
+			_, err := handler.GetResourcePage(
+				httptest.NewRecorder(),
+				makeRequest(
+					t,
+					tc.params,
+					map[string]string{},
+					q,
+				),
+			)
+			tt.Assert.Error(err)
+			if tc.isInvalidAccountsParams {
+				tt.Assert.Equal(invalidAccountsParams, err)
+			} else {
+				if tt.Assert.IsType(&problem.P{}, err) {
+					p := err.(*problem.P)
+					tt.Assert.Equal("bad_request", p.Type)
+					tt.Assert.Equal(tc.expectedInvalidField, p.Extras["invalid_field"])
+					tt.Assert.Equal(
+						tc.expectedErr,
+						p.Extras["reason"],
+					)
+				}
+			}
+		})
+	}
+}
+
+func TestAccountQueryURLTemplate(t *testing.T) {
+	tt := assert.New(t)
+	expected := "/accounts{?signer,sponsor,asset,assets,match,weak_thresholds,has_liabilities,no_home_domain,home_domain,signer_type,threshold,threshold_op,threshold_value,data_name,data_value,modified_from,modified_to,only_matching_asset,zero_balance,top,allow_partial,omit_empty,sort,summary,strict_params,cursor,limit,order}"
+	accountsQuery := AccountsQuery{}
+	tt.Equal(expected, accountsQuery.URITemplate())
+}
+
+func TestGetAccountsHandlerFilterRateLimit(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+
+	q := &history.Q{tt.HorizonSession()}
+	limiter, err := NewGCRAAccountsFilterRateLimiter(map[AccountsFilterType]throttled.RateQuota{
+		AccountsAssetFilter: {
+			MaxRate:  throttled.PerHour(1),
+			MaxBurst: 0,
+		},
+	}, remoteAddrKey{})
+	tt.Assert.NoError(err)
+	handler := &GetAccountsHandler{FilterRateLimiter: limiter}
+
+	assetParams := map[string]string{
+		"asset": "USD:" + trustLineIssuer,
+	}
+	_, err = handler.GetResourcePage(
+		httptest.NewRecorder(),
+		makeRequest(t, assetParams, map[string]string{}, q),
+	)
+	tt.Assert.NoError(err)
+
+	w := httptest.NewRecorder()
+	_, err = handler.GetResourcePage(
+		w,
+		makeRequest(t, assetParams, map[string]string{}, q),
+	)
+	tt.Assert.Equal(hProblem.RateLimitExceeded, err)
+	tt.Assert.NotEmpty(w.Header().Get("Retry-After"))
+
+	for i := 0; i < 3; i++ {
+		w = httptest.NewRecorder()
+		_, err = handler.GetResourcePage(
+			w,
+			makeRequest(t, map[string]string{"signer": signer}, map[string]string{}, q),
+		)
+		tt.Assert.NoError(err)
+		tt.Assert.Empty(w.Header().Get("Retry-After"))
+	}
+}
+
+func TestParseAccountsParams(t *testing.T) {
+	tt := assert.New(t)
+
+	params, err := ParseAccountsParams(
+		nil,
+		makeRequest(
+			t,
+			map[string]string{
+				"asset":  "USD:" + trustLineIssuer,
+				"cursor": accountOne,
+				"limit":  "20",
+				"order":  "desc",
+			},
+			map[string]string{},
+			nil,
+		),
+	)
+	tt.NoError(err)
+	tt.Equal("USD:"+trustLineIssuer, params.AssetFilter)
+	tt.Equal(usd, *params.Asset())
+	tt.Empty(params.Signer)
+	tt.Empty(params.Sponsor)
+	tt.Equal(accountOne, params.PageQuery.Cursor)
+	tt.Equal(uint64(20), params.PageQuery.Limit)
+	tt.Equal("desc", params.PageQuery.Order)
+
+	params, err = ParseAccountsParams(
+		nil,
+		makeRequest(t, map[string]string{"signer": signer}, map[string]string{}, nil),
+	)
+	tt.NoError(err)
+	tt.Equal(signer, params.Signer)
+	tt.Equal(AccountsSignerFilter, params.FilterType())
+	tt.Equal("", params.PageQuery.Cursor)
+	tt.Equal(uint64(db2.DefaultPageSize), params.PageQuery.Limit)
+	tt.Equal("asc", params.PageQuery.Order)
+
+	_, err = ParseAccountsParams(
+		nil,
+		makeRequest(t, map[string]string{}, map[string]string{}, nil),
+	)
+	tt.Equal(invalidAccountsParams, err)
+
+	_, err = ParseAccountsParams(
+		nil,
+		makeRequest(t, map[string]string{"signer": signer, "limit": "201"}, map[string]string{}, nil),
+	)
+	if tt.IsType(&problem.P{}, err) {
+		tt.Equal("limit", err.(*problem.P).Extras["invalid_field"])
+	}
+
+	_, err = ParseAccountsParams(
+		nil,
+		makeRequest(t, map[string]string{"signer": "GNOTANACCOUNT"}, map[string]string{}, nil),
+	)
+	if tt.IsType(&problem.P{}, err) {
+		tt.Equal("signer", err.(*problem.P).Extras["invalid_field"])
+	}
+
+	// an empty home_domain isn't ignored
+	for _, params := range []map[string]string{
+		{"home_domain": ""},
+		{"home_domain": "", "signer": signer},
+		{"home_domain": "", "sort": "native_balance"},
+	} {
+		_, err = ParseAccountsParams(
+			nil,
+			makeRequest(t, params, map[string]string{}, nil),
+		)
+		if tt.IsType(&problem.P{}, err) {
+			tt.Equal("home_domain", err.(*problem.P).Extras["invalid_field"])
+		}
+	}
+}
+
+func TestGetAccountByIDHandlerSignerType(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+
+	q := &history.Q{tt.HorizonSession()}
+	handler := GetAccountByIDHandler{}
+
+	batch := q.NewAccountsBatchInsertBuilder(0)
+	tt.Assert.NoError(batch.Add(tt.Ctx, account1))
+	tt.Assert.NoError(batch.Exec(tt.Ctx))
+
+	hashXSigner := "XAS32PGNT5JVEBFMI7QYUA7IGFSBQSNHOL2RWXJI45S7NEJHI2O5U2SV"
+	preAuthSigner := "TBEZPYEOQNR2QDXVA2N7UVR7FDN2OFS4HSPTEGZAJLIV77NWK4VQP27G"
+	for _, key := range []string{accountOne, signer, hashXSigner, preAuthSigner} {
+		_, err := q.CreateAccountSigner(tt.Ctx, accountOne, key, 1, nil)
+		tt.Assert.NoError(err)
+	}
+
+	response, err := handler.GetResource(
+		httptest.NewRecorder(),
+		makeRequest(t, map[string]string{}, map[string]string{"account_id": accountOne}, q),
+	)
+	tt.Assert.NoError(err)
+	tt.Assert.Len(response.(Account).Signers, 4)
+
+	response, err = handler.GetResource(
+		httptest.NewRecorder(),
+		makeRequest(
+			t,
+			map[string]string{"signer_type": "ed25519_public_key"},
+			map[string]string{"account_id": accountOne},
+			q,
+		),
+	)
+	tt.Assert.NoError(err)
+	signers := response.(Account).Signers
+	tt.Assert.Len(signers, 2)
+	for _, s := range signers {
+		tt.Assert.Equal("ed25519_public_key", s.Type)
+	}
+
+	response, err = handler.GetResource(
+		httptest.NewRecorder(),
+		makeRequest(
+			t,
+			map[string]string{"signer_type": "sha256_hash,preauth_tx"},
+			map[string]string{"account_id": accountOne},
+			q,
+		),
+	)
+	tt.Assert.NoError(err)
+	signers = response.(Account).Signers
+	tt.Assert.Len(signers, 2)
+	tt.Assert.Equal(preAuthSigner, signers[0].Key)
+	tt.Assert.Equal(hashXSigner, signers[1].Key)
+
+	_, err = handler.GetResource(
+		httptest.NewRecorder(),
+		makeRequest(
+			t,
+			map[string]string{"signer_type": "ed25519"},
+			map[string]string{"account_id": accountOne},
+			q,
+		),
+	)
+	if tt.Assert.IsType(&problem.P{}, err) {
+		tt.Assert.Equal("signer_type", err.(*problem.P).Extras["invalid_field"])
+	}
+}
+
+func TestGetAccountByIDHandlerKeyFormat(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+
+	q := &history.Q{tt.HorizonSession()}
+	handler := GetAccountByIDHandler{}
+
+	// account3 is the signer account, its only signer is its master key
+	batch := q.NewAccountsBatchInsertBuilder(0)
+	tt.Assert.NoError(batch.Add(tt.Ctx, account3))
+	tt.Assert.NoError(batch.Exec(tt.Ctx))
+	_, err := q.CreateAccountSigner(tt.Ctx, signer, signer, 3, nil)
+	tt.Assert.NoError(err)
+
+	getAccount := func(params map[string]string) Account {
+		response, err := handler.GetResource(
+			httptest.NewRecorder(),
+			makeRequest(t, params, map[string]string{"account_id": signer}, q),
+		)
+		tt.Assert.NoError(err)
+		return response.(Account)
+	}
+
+	for _, params := range []map[string]string{{}, {"key_format": "strkey"}} {
+		account := getAccount(params)
+		tt.Assert.Equal(signer, account.AccountID)
+		if tt.Assert.Len(account.Signers, 1) {
+			tt.Assert.Equal(signer, account.Signers[0].Key)
+		}
+	}
+
+	signerHex := "aea37a2de39a823c4c6943fde81cdfb3d7b4728acd657cc1589905a0aee4be4c"
+	account := getAccount(map[string]string{"key_format": "hex"})
+	tt.Assert.Equal(signerHex, account.AccountID)
+	if tt.Assert.Len(account.Signers, 1) {
+		tt.Assert.Equal(signerHex, account.Signers[0].Key)
+		tt.Assert.Equal("ed25519_public_key", account.Signers[0].Type)
+	}
+
+	_, err = handler.GetResource(
+		httptest.NewRecorder(),
+		makeRequest(t, map[string]string{"key_format": "base64"}, map[string]string{"account_id": signer}, q),
+	)
+	if tt.Assert.IsType(&problem.P{}, err) {
+		tt.Assert.Equal("key_format", err.(*problem.P).Extras["invalid_field"])
+	}
+}
+
+func TestGetAccountByIDHandlerLastModifiedTime(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+
+	q := &history.Q{tt.HorizonSession()}
+	handler := GetAccountByIDHandler{}
+
+	batch := q.NewAccountsBatchInsertBuilder(0)
+	tt.Assert.NoError(batch.Add(tt.Ctx, account1))
+	tt.Assert.NoError(batch.Exec(tt.Ctx))
+
+	// the ledger in which the account was last modified is not ingested yet
+	response, err := handler.GetResource(
+		httptest.NewRecorder(),
+		makeRequest(t, map[string]string{}, map[string]string{"account_id": accountOne}, q),
+	)
+	tt.Assert.NoError(err)
+	tt.Assert.Equal(uint32(1234), response.(Account).LastModifiedLedger)
+	tt.Assert.Nil(response.(Account).LastModifiedTime)
+
+	ledgerCloseTime := time.Now().Unix()
+	_, err = q.InsertLedger(tt.Ctx, xdr.LedgerHeaderHistoryEntry{
+		Header: xdr.LedgerHeader{
+			LedgerSeq: 1234,
+			ScpValue: xdr.StellarValue{
+				CloseTime: xdr.TimePoint(ledgerCloseTime),
+			},
+		},
+	}, 0, 0, 0, 0, 0)
+	tt.Assert.NoError(err)
+
+	response, err = handler.GetResource(
+		httptest.NewRecorder(),
+		makeRequest(t, map[string]string{}, map[string]string{"account_id": accountOne}, q),
+	)
+	tt.Assert.NoError(err)
+	lastModifiedTime := response.(Account).LastModifiedTime
+	if tt.Assert.NotNil(lastModifiedTime) {
+		tt.Assert.Equal(ledgerCloseTime, lastModifiedTime.Unix())
+	}
+}
+
+func TestGetAccountByIDHandlerIncludeMinBalance(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+
+	q := &history.Q{tt.HorizonSession()}
+	handler := GetAccountByIDHandler{}
+
+	batch := q.NewAccountsBatchInsertBuilder(0)
+	tt.Assert.NoError(batch.Add(tt.Ctx, account1))
+	tt.Assert.NoError(batch.Exec(tt.Ctx))
+	for seq, baseReserve := range map[uint32]uint32{1234: 5000000, 1235: 10000000} {
+		_, err := q.InsertLedger(tt.Ctx, xdr.LedgerHeaderHistoryEntry{
+			Header: xdr.LedgerHeader{
+				LedgerSeq:   xdr.Uint32(seq),
+				BaseReserve: xdr.Uint32(baseReserve),
+			},
+		}, 0, 0, 0, 0, 0)
+		tt.Assert.NoError(err)
+	}
+
+	getAccount := func(params map[string]string) Account {
+		response, err := handler.GetResource(
+			httptest.NewRecorder(),
+			makeRequest(t, params, map[string]string{"account_id": accountOne}, q),
+		)
+		tt.Assert.NoError(err)
+		return response.(Account)
+	}
+
+	tt.Assert.Empty(getAccount(map[string]string{}).MinBalanceStroops)
+	// (2 + 10 subentries) * the base reserve of the latest ledger
+	tt.Assert.Equal(
+		"120000000",
+		getAccount(map[string]string{"include_min_balance": "true"}).MinBalanceStroops,
+	)
+}
+
+func TestGetAccountByIDHandlerIncludeReserveCost(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+
+	q := &history.Q{tt.HorizonSession()}
+	handler := GetAccountByIDHandler{}
+
+	batch := q.NewAccountsBatchInsertBuilder(0)
+	tt.Assert.NoError(batch.Add(tt.Ctx, account1))
+	tt.Assert.NoError(batch.Exec(tt.Ctx))
+	_, err := q.InsertLedger(tt.Ctx, xdr.LedgerHeaderHistoryEntry{
+		Header: xdr.LedgerHeader{
+			LedgerSeq:   1234,
+			BaseReserve: 5000000,
+		},
+	}, 0, 0, 0, 0, 0)
+	tt.Assert.NoError(err)
+
+	// the signer of accountOne is sponsored, its master key isn't
+	sponsorAddress := sponsor.Address()
+	_, err = q.CreateAccountSigner(tt.Ctx, accountOne, accountOne, 1, nil)
+	tt.Assert.NoError(err)
+	_, err = q.CreateAccountSigner(tt.Ctx, accountOne, signer, 1, &sponsorAddress)
+	tt.Assert.NoError(err)
+	// so is its USD trust line, not its EUR trust line
+	sponsoredUsdTrustLine := usdTrustLine
+	trustLine := *usdTrustLine.Data.TrustLine
+	trustLine.AccountId = xdr.MustAddress(accountOne)
+	sponsoredUsdTrustLine.Data.TrustLine = &trustLine
+	sponsoredUsdTrustLine.Ext = xdr.LedgerEntryExt{
+		V:  1,
+		V1: &xdr.LedgerEntryExtensionV1{SponsoringId: &sponsor},
+	}
+	for _, entry := range []xdr.LedgerEntry{eurTrustLine, sponsoredUsdTrustLine} {
+		_, err = q.InsertTrustLine(tt.Ctx, entry)
+		tt.Assert.NoError(err)
+	}
+
+	getAccount := func(params map[string]string) Account {
+		response, err := handler.GetResource(
+			httptest.NewRecorder(),
+			makeRequest(t, params, map[string]string{"account_id": accountOne}, q),
+		)
+		tt.Assert.NoError(err)
+		return response.(Account)
+	}
+
+	reserves := func(account Account) map[string]string {
+		result := map[string]string{}
+		for _, s := range account.Signers {
+			result[s.Key] = s.ReserveStroops
+		}
+		for _, b := range account.Balances {
+			result[b.Type+":"+b.Code] = b.ReserveStroops
+		}
+		return result
+	}
+
+	for _, reserve := range reserves(getAccount(map[string]string{})) {
+		tt.Assert.Empty(reserve)
+	}
+	tt.Assert.Equal(map[string]string{
+		accountOne:             "",
+		signer:                 "5000000",
+		"credit_alphanum4:EUR": "",
+		"credit_alphanum4:USD": "5000000",
+		"native:":              "",
+	}, reserves(getAccount(map[string]string{"include_reserve_cost": "true"})))
+}
+
+func TestGetAccountByIDHandlerIfModifiedSince(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+
+	q := &history.Q{tt.HorizonSession()}
+	handler := GetAccountByIDHandler{}
+
+	batch := q.NewAccountsBatchInsertBuilder(0)
+	tt.Assert.NoError(batch.Add(tt.Ctx, account1))
+	tt.Assert.NoError(batch.Exec(tt.Ctx))
+
+	ledgerCloseTime := time.Unix(1600000000, 0).UTC()
+	_, err := q.InsertLedger(tt.Ctx, xdr.LedgerHeaderHistoryEntry{
+		Header: xdr.LedgerHeader{
+			LedgerSeq: 1234,
+			ScpValue: xdr.StellarValue{
+				CloseTime: xdr.TimePoint(ledgerCloseTime.Unix()),
+			},
+		},
+	}, 0, 0, 0, 0, 0)
+	tt.Assert.NoError(err)
+
+	request := func(ifModifiedSince string) *http.Request {
+		r := makeRequest(t, map[string]string{}, map[string]string{"account_id": accountOne}, q)
+		if len(ifModifiedSince) > 0 {
+			r.Header.Set("If-Modified-Since", ifModifiedSince)
+		}
+		return r
+	}
+
+	// the last modification is only looked up for conditional requests
+	w := httptest.NewRecorder()
+	_, err = handler.GetResource(w, request(""))
+	tt.Assert.NoError(err)
+	tt.Assert.Empty(w.Header().Get("Last-Modified"))
+
+	w = httptest.NewRecorder()
+	_, err = handler.GetResource(w, request(ledgerCloseTime.Add(-time.Second).Format(http.TimeFormat)))
+	tt.Assert.NoError(err)
+	tt.Assert.Equal(ledgerCloseTime.Format(http.TimeFormat), w.Header().Get("Last-Modified"))
+
+	for _, since := range []time.Time{ledgerCloseTime, ledgerCloseTime.Add(time.Hour)} {
+		_, err = handler.GetResource(httptest.NewRecorder(), request(since.Format(http.TimeFormat)))
+		tt.Assert.Equal(ErrNotModified, err)
+	}
+
+	response, err := handler.GetResource(
+		httptest.NewRecorder(),
+		request(ledgerCloseTime.Add(-time.Second).Format(http.TimeFormat)),
+	)
+	tt.Assert.NoError(err)
+	tt.Assert.Equal(accountOne, response.(Account).AccountID)
+
+	// invalid dates are ignored
+	_, err = handler.GetResource(httptest.NewRecorder(), request("yesterday"))
+	tt.Assert.NoError(err)
+
+	// a trust line changed after the account moves the last modification
+	laterCloseTime := ledgerCloseTime.Add(time.Minute)
+	_, err = q.InsertLedger(tt.Ctx, xdr.LedgerHeaderHistoryEntry{
+		Header: xdr.LedgerHeader{
+			LedgerSeq: 1240,
+			ScpValue: xdr.StellarValue{
+				CloseTime: xdr.TimePoint(laterCloseTime.Unix()),
+			},
+		},
+	}, 0, 0, 0, 0, 0)
+	tt.Assert.NoError(err)
+	trustLine := eurTrustLine
+	trustLine.LastModifiedLedgerSeq = 1240
+	_, err = q.InsertTrustLine(tt.Ctx, trustLine)
+	tt.Assert.NoError(err)
+
+	w = httptest.NewRecorder()
+	_, err = handler.GetResource(w, request(ledgerCloseTime.Format(http.TimeFormat)))
+	tt.Assert.NoError(err)
+	tt.Assert.Equal(laterCloseTime.Format(http.TimeFormat), w.Header().Get("Last-Modified"))
+
+	// without loading the trust lines
+	r := request(ledgerCloseTime.Format(http.TimeFormat))
+	r.URL.RawQuery = "native_only=true"
+	w = httptest.NewRecorder()
+	_, err = handler.GetResource(w, r)
+	tt.Assert.NoError(err)
+	tt.Assert.Equal(laterCloseTime.Format(http.TimeFormat), w.Header().Get("Last-Modified"))
+
+	tt.Assert.Equal(ErrNotModified, handler.Head(httptest.NewRecorder(), request(laterCloseTime.Format(http.TimeFormat))))
+	tt.Assert.NoError(handler.Head(httptest.NewRecorder(), request(ledgerCloseTime.Format(http.TimeFormat))))
+}
+
+func TestGetAccountByIDHandlerIncludeIssuedAssets(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+
+	q := &history.Q{tt.HorizonSession()}
+	handler := GetAccountByIDHandler{}
+
+	issuerAccount := account1
+	issuerAccountEntry := *account1.Data.Account
+	issuerAccountEntry.AccountId = xdr.MustAddress(trustLineIssuer)
+	issuerAccount.Data.Account = &issuerAccountEntry
+
+	batch := q.NewAccountsBatchInsertBuilder(0)
+	tt.Assert.NoError(batch.Add(tt.Ctx, account1))
+	tt.Assert.NoError(batch.Add(tt.Ctx, issuerAccount))
+	tt.Assert.NoError(batch.Exec(tt.Ctx))
+
+	// trustLineIssuer issues USD and EUR
+	for _, entry := range []xdr.LedgerEntry{eurTrustLine, usdTrustLine} {
+		_, err := q.InsertTrustLine(tt.Ctx, entry)
+		tt.Assert.NoError(err)
+	}
+
+	getAccount := func(accountID string, params map[string]string) Account {
+		response, err := handler.GetResource(
+			httptest.NewRecorder(),
+			makeRequest(t, params, map[string]string{"account_id": accountID}, q),
+		)
+		tt.Assert.NoError(err)
+		return response.(Account)
+	}
+
+	account := getAccount(trustLineIssuer, map[string]string{})
+	tt.Assert.Nil(account.IssuedAssets)
+
+	account = getAccount(trustLineIssuer, map[string]string{"include_issued_assets": "true"})
+	tt.Assert.Equal([]protocol.Asset{
+		{Type: "credit_alphanum4", Code: "EUR", Issuer: trustLineIssuer},
+		{Type: "credit_alphanum4", Code: "USD", Issuer: trustLineIssuer},
+	}, account.IssuedAssets)
+
+	// omitted when the account doesn't issue any asset
+	account = getAccount(accountOne, map[string]string{"include_issued_assets": "true"})
+	tt.Assert.Nil(account.IssuedAssets)
+}
+
+func TestGetAccountByIDHandlerIncludeCreatedAt(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+
+	q := &history.Q{tt.HorizonSession()}
+	handler := GetAccountByIDHandler{}
+
+	batch := q.NewAccountsBatchInsertBuilder(0)
+	tt.Assert.NoError(batch.Add(tt.Ctx, account1))
+	tt.Assert.NoError(batch.Add(tt.Ctx, account2))
+	tt.Assert.NoError(batch.Exec(tt.Ctx))
+
+	// only the creation of accountOne is in the history
+	details, err := json.Marshal(map[string]string{
+		"funder":           trustLineIssuer,
+		"account":          accountOne,
+		"starting_balance": "100.0000000",
+	})
+	tt.Assert.NoError(err)
+	operations := q.NewOperationBatchInsertBuilder(0)
+	tt.Assert.NoError(operations.Add(tt.Ctx,
+		toid.New(1234, 1, 1).ToInt64(),
+		toid.New(1234, 1, 0).ToInt64(),
+		1,
+		xdr.OperationTypeCreateAccount,
+		details,
+		trustLineIssuer,
+		null.String{},
+	))
+	tt.Assert.NoError(operations.Exec(tt.Ctx))
+
+	getAccount := func(accountID string, params map[string]string) Account {
+		response, err := handler.GetResource(
+			httptest.NewRecorder(),
+			makeRequest(t, params, map[string]string{"account_id": accountID}, q),
+		)
+		tt.Assert.NoError(err)
+		return response.(Account)
+	}
+
+	account := getAccount(accountOne, map[string]string{})
+	tt.Assert.Empty(account.CreatedAtOperationID)
+	tt.Assert.Zero(account.CreatedLedger)
+
+	account = getAccount(accountOne, map[string]string{"include_created_at": "true"})
+	tt.Assert.Equal("5299989647361", account.CreatedAtOperationID)
+	tt.Assert.Equal(int32(1234), account.CreatedLedger)
+
+	// omitted when the creation of the account isn't known
+	account = getAccount(accountTwo, map[string]string{"include_created_at": "true"})
+	tt.Assert.Empty(account.CreatedAtOperationID)
+	tt.Assert.Zero(account.CreatedLedger)
+}
+
+func TestGetAccountByIDHandlerOmitEmpty(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+
+	q := &history.Q{tt.HorizonSession()}
+	handler := GetAccountByIDHandler{}
+
+	// account1 doesn't have any data entry
+	batch := q.NewAccountsBatchInsertBuilder(0)
+	tt.Assert.NoError(batch.Add(tt.Ctx, account1))
+	tt.Assert.NoError(batch.Exec(tt.Ctx))
+
+	getAccountJSON := func(params map[string]string) string {
+		response, err := handler.GetResource(
+			httptest.NewRecorder(),
+			makeRequest(t, params, map[string]string{"account_id": accountOne}, q),
+		)
+		tt.Assert.NoError(err)
+		marshaled, err := json.Marshal(response)
+		tt.Assert.NoError(err)
+		return string(marshaled)
+	}
+
+	tt.Assert.Contains(getAccountJSON(map[string]string{}), `"data":{}`)
+
+	marshaled := getAccountJSON(map[string]string{"omit_empty": "true"})
+	// _links holds a data link, only the top-level data key must be omitted
+	var fields map[string]json.RawMessage
+	tt.Assert.NoError(json.Unmarshal([]byte(marshaled), &fields))
+	tt.Assert.NotContains(fields, "data")
+	tt.Assert.Contains(marshaled, `"balances":[{`)
+	tt.Assert.Contains(marshaled, `"signers":[{`)
+}
+
+func TestGetAccountByIDHandlerMinWeight(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+
+	q := &history.Q{tt.HorizonSession()}
+	handler := GetAccountByIDHandler{}
+
+	batch := q.NewAccountsBatchInsertBuilder(0)
+	tt.Assert.NoError(batch.Add(tt.Ctx, account1))
+	tt.Assert.NoError(batch.Add(tt.Ctx, account2))
+	tt.Assert.NoError(batch.Exec(tt.Ctx))
+	for _, row := range accountSigners {
+		_, err := q.CreateAccountSigner(tt.Ctx, row.Account, row.Signer, row.Weight, nil)
+		tt.Assert.NoError(err)
+	}
+
+	signerWeights := func(accountID string, params map[string]string) map[string]int32 {
+		response, err := handler.GetResource(
+			httptest.NewRecorder(),
+			makeRequest(t, params, map[string]string{"account_id": accountID}, q),
+		)
+		tt.Assert.NoError(err)
+		weights := map[string]int32{}
+		for _, s := range response.(Account).Signers {
+			weights[s.Key] = s.Weight
+		}
+		return weights
+	}
+
+	tt.Assert.Equal(
+		map[string]int32{accountTwo: 1, signer: 2},
+		signerWeights(accountTwo, map[string]string{}),
+	)
+	// the master key is kept even if its weight is lower
+	tt.Assert.Equal(
+		map[string]int32{accountTwo: 1, signer: 2},
+		signerWeights(accountTwo, map[string]string{"min_weight": "2"}),
+	)
+	tt.Assert.Equal(
+		map[string]int32{accountOne: 1},
+		signerWeights(accountOne, map[string]string{"min_weight": "2"}),
+	)
+	tt.Assert.Equal(
+		map[string]int32{accountTwo: 1},
+		signerWeights(accountTwo, map[string]string{"min_weight": "3"}),
+	)
+}
+
+func TestGetAccountByIDHandlerMasterKeyWeight(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+
+	q := &history.Q{tt.HorizonSession()}
+	handler := GetAccountByIDHandler{}
+
+	// account2 with its master key disabled
+	disabledMaster := account2
+	entry := *account2.Data.Account
+	entry.Thresholds = xdr.Thresholds{0, 6, 7, 8}
+	disabledMaster.Data.Account = &entry
+
+	batch := q.NewAccountsBatchInsertBuilder(0)
+	tt.Assert.NoError(batch.Add(tt.Ctx, account1))
+	tt.Assert.NoError(batch.Add(tt.Ctx, disabledMaster))
+	tt.Assert.NoError(batch.Exec(tt.Ctx))
+
+	getAccount := func(accountID string) Account {
+		response, err := handler.GetResource(
+			httptest.NewRecorder(),
+			makeRequest(t, map[string]string{}, map[string]string{"account_id": accountID}, q),
+		)
+		tt.Assert.NoError(err)
+		return response.(Account)
+	}
+
+	account := getAccount(accountOne)
+	tt.Assert.Equal(int32(1), account.MasterKeyWeight)
+	tt.Assert.False(account.MasterKeyDisabled)
+	marshaled, err := json.Marshal(account)
+	tt.Assert.NoError(err)
+	tt.Assert.Contains(string(marshaled), `"master_key_weight":1`)
+	tt.Assert.NotContains(string(marshaled), `"master_key_disabled"`)
+
+	account = getAccount(accountTwo)
+	tt.Assert.Equal(int32(0), account.MasterKeyWeight)
+	tt.Assert.True(account.MasterKeyDisabled)
+	marshaled, err = json.Marshal(account)
+	tt.Assert.NoError(err)
+	tt.Assert.Contains(string(marshaled), `"master_key_weight":0`)
+	tt.Assert.Contains(string(marshaled), `"master_key_disabled":true`)
+}
+
+func TestGetAccountByIDHandlerIncludeIssuerFlags(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+
+	q := &history.Q{tt.HorizonSession()}
+	handler := GetAccountByIDHandler{}
+
+	issuer := xdr.LedgerEntry{
+		LastModifiedLedgerSeq: 1234,
+		Data: xdr.LedgerEntryData{
+			Type: xdr.LedgerEntryTypeAccount,
+			Account: &xdr.AccountEntry{
+				AccountId:  xdr.MustAddress(trustLineIssuer),
+				Balance:    10000,
+				SeqNum:     1,
+				Flags:      xdr.Uint32(xdr.AccountFlagsAuthRevocableFlag),
+				Thresholds: xdr.Thresholds{1, 1, 1, 1},
+			},
+		},
+	}
+	batch := q.NewAccountsBatchInsertBuilder(0)
+	tt.Assert.NoError(batch.Add(tt.Ctx, account2))
+	tt.Assert.NoError(batch.Add(tt.Ctx, issuer))
+	tt.Assert.NoError(batch.Exec(tt.Ctx))
+	_, err := q.InsertTrustLine(tt.Ctx, usdTrustLine)
+	tt.Assert.NoError(err)
+
+	getBalances := func(params map[string]string) []protocol.Balance {
+		response, err := handler.GetResource(
+			httptest.NewRecorder(),
+			makeRequest(t, params, map[string]string{"account_id": accountTwo}, q),
+		)
+		tt.Assert.NoError(err)
+		balances := response.(Account).Balances
+		tt.Assert.Len(balances, 2)
+		return balances
+	}
+
+	for _, balance := range getBalances(map[string]string{}) {
+		tt.Assert.Nil(balance.IssuerFlags)
+	}
+
+	balances := getBalances(map[string]string{"include_issuer_flags": "true"})
+	tt.Assert.Equal("USD", balances[0].Code)
+	tt.Assert.Equal(&protocol.AccountFlags{AuthRevocable: true}, balances[0].IssuerFlags)
+	tt.Assert.Equal("native", balances[1].Type)
+	tt.Assert.Nil(balances[1].IssuerFlags)
+}
+
+func TestGetAccountByIDHandlerCamelCase(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+
+	q := &history.Q{tt.HorizonSession()}
+	handler := GetAccountByIDHandler{}
+
+	batch := q.NewAccountsBatchInsertBuilder(0)
+	tt.Assert.NoError(batch.Add(tt.Ctx, account1))
+	tt.Assert.NoError(batch.Exec(tt.Ctx))
+	_, err := q.InsertTrustLine(tt.Ctx, eurTrustLine)
+	tt.Assert.NoError(err)
+
+	getAccountJSON := func(params map[string]string) string {
+		response, err := handler.GetResource(
+			httptest.NewRecorder(),
+			makeRequest(t, params, map[string]string{"account_id": accountOne}, q),
+		)
+		tt.Assert.NoError(err)
+		marshaled, err := json.Marshal(response)
+		tt.Assert.NoError(err)
+		return string(marshaled)
+	}
+
+	marshaled := getAccountJSON(map[string]string{})
+	tt.Assert.Contains(marshaled, `"account_id":"`+accountOne+`"`)
+	tt.Assert.NotContains(marshaled, `"accountId"`)
+
+	marshaled = getAccountJSON(map[string]string{"key_case": "camel"})
+	tt.Assert.Contains(marshaled, `"accountId":"`+accountOne+`"`)
+	tt.Assert.Contains(marshaled, `"pagingToken":`)
+	tt.Assert.Contains(marshaled, `"_links":{`)
+	tt.Assert.Contains(marshaled, `"lowThreshold":2`)
+	tt.Assert.Contains(marshaled, `"assetCode":"EUR"`)
+	tt.Assert.Contains(marshaled, `"buyingLiabilities":"0.0000003"`)
+	tt.Assert.Contains(marshaled, `"isAuthorized":`)
+	tt.Assert.Contains(marshaled, `"signers":[{"key":`)
+	tt.Assert.NotContains(marshaled, `"account_id"`)
+
+	_, err = handler.GetResource(
+		httptest.NewRecorder(),
+		makeRequest(t, map[string]string{"key_case": "kebab"}, map[string]string{"account_id": accountOne}, q),
+	)
+	tt.Assert.Error(err)
+	p := err.(*problem.P)
+	tt.Assert.Equal("bad_request", p.Type)
+	tt.Assert.Equal("key_case", p.Extras["invalid_field"])
+}
+
+func TestAccountPreClawbackFlags(t *testing.T) {
+	account := Account{
+		Account: protocol.Account{
+			AccountID: accountOne,
+			Flags:     protocol.AccountFlags{AuthRequired: true},
+		},
+		flagsPredateClawback: true,
+	}
+	marshaled, err := json.Marshal(account)
+	assert.NoError(t, err)
+	assert.Contains(t, string(marshaled), `"flags":{"auth_required":true,"auth_revocable":false,"auth_immutable":false}`)
+	assert.Contains(t, string(marshaled), `"account_id":"`+accountOne+`"`)
+
+	marshaled, err = json.Marshal(camelCaseAccount{account})
+	assert.NoError(t, err)
+	assert.Contains(t, string(marshaled), `"flags":{"authImmutable":false,"authRequired":true,"authRevocable":false}`)
+	assert.Contains(t, string(marshaled), `"accountId":"`+accountOne+`"`)
+	assert.Equal(t, 1, strings.Count(string(marshaled), `"flags":`))
+
+	account.flagsPredateClawback = false
+	marshaled, err = json.Marshal(account)
+	assert.NoError(t, err)
+	assert.Contains(t, string(marshaled), `"auth_clawback_enabled":false`)
+}
+
+func TestGetAccountByIDHandlerSponsor(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+
+	q := &history.Q{tt.HorizonSession()}
+	handler := GetAccountByIDHandler{}
+
+	// account3 is sponsored by sponsor, account1 pays its own reserve
+	batch := q.NewAccountsBatchInsertBuilder(0)
+	tt.Assert.NoError(batch.Add(tt.Ctx, account1))
+	tt.Assert.NoError(batch.Add(tt.Ctx, account3))
+	tt.Assert.NoError(batch.Exec(tt.Ctx))
+
+	getAccount := func(accountID string) Account {
+		response, err := handler.GetResource(
+			httptest.NewRecorder(),
+			makeRequest(t, map[string]string{}, map[string]string{"account_id": accountID}, q),
+		)
+		tt.Assert.NoError(err)
+		return response.(Account)
+	}
+
+	account := getAccount(signer)
+	tt.Assert.Equal(sponsor.Address(), account.Sponsor)
+
+	account = getAccount(accountOne)
+	tt.Assert.Empty(account.Sponsor)
+	marshaled, err := json.Marshal(account)
+	tt.Assert.NoError(err)
+	tt.Assert.NotContains(string(marshaled), `"sponsor"`)
+	tt.Assert.NotContains(string(marshaled), `"sponsoring"`)
+}
+
+func TestGetAccountByIDHandlerTrustLineLiabilities(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+
+	q := &history.Q{tt.HorizonSession()}
+	handler := GetAccountByIDHandler{}
+
+	batch := q.NewAccountsBatchInsertBuilder(0)
+	tt.Assert.NoError(batch.Add(tt.Ctx, account1))
+	tt.Assert.NoError(batch.Exec(tt.Ctx))
+	_, err := q.InsertTrustLine(tt.Ctx, eurTrustLine)
+	tt.Assert.NoError(err)
+
+	response, err := handler.GetResource(
+		httptest.NewRecorder(),
+		makeRequest(t, map[string]string{}, map[string]string{"account_id": accountOne}, q),
+	)
+	tt.Assert.NoError(err)
+	balances := response.(Account).Balances
+	tt.Assert.Len(balances, 2)
+
+	// the liabilities of the trust line are rendered like the native ones
+	for _, balance := range balances {
+		tt.Assert.Equal("0.0000003", balance.BuyingLiabilities)
+		tt.Assert.Equal("0.0000004", balance.SellingLiabilities)
+		tt.Assert.Equal("3", balance.BuyingLiabilitiesStroops)
+		tt.Assert.Equal("4", balance.SellingLiabilitiesStroops)
+	}
+	tt.Assert.Equal("EUR", balances[0].Code)
+	tt.Assert.Equal("native", balances[1].Type)
+}
+
+func TestAccountEntryFromResourceRoundTrip(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+
+	q := &history.Q{tt.HorizonSession()}
+	handler := GetAccountByIDHandler{}
+
+	batch := q.NewAccountsBatchInsertBuilder(0)
+	tt.Assert.NoError(batch.Add(tt.Ctx, account1))
+	tt.Assert.NoError(batch.Exec(tt.Ctx))
+
+	response, err := handler.GetResource(
+		httptest.NewRecorder(),
+		makeRequest(t, map[string]string{}, map[string]string{"account_id": accountOne}, q),
+	)
+	tt.Assert.NoError(err)
+
+	entry, err := resourceadapter.AccountEntryFromResource(response.(Account).Account)
+	tt.Assert.NoError(err)
+	tt.Assert.Equal(*account1.Data.Account, entry)
+
+	// the balance can't be reconstructed without the native balance
+	resource := response.(Account).Account
+	resource.Balances = nil
+	_, err = resourceadapter.AccountEntryFromResource(resource)
+	tt.Assert.EqualError(err, "native balance is missing")
+}
+
+func TestGetAccountByIDHandlerFullAccount(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+
+	q := &history.Q{tt.HorizonSession()}
+	handler := GetAccountByIDHandler{}
+
+	// account2 with a signer sponsored by sponsor, its USD trust line and
+	// its data entry
+	account := *account2.Data.Account
+	account.Signers = []xdr.Signer{{Key: xdr.MustSigner(signer), Weight: 3}}
+	sponsorID := sponsor
+	extension := *account.Ext.V1
+	extension.Ext = xdr.AccountEntryExtensionV1Ext{
+		V: 2,
+		V2: &xdr.AccountEntryExtensionV2{
+			SignerSponsoringIDs: []xdr.SponsorshipDescriptor{&sponsorID},
+		},
+	}
+	account.Ext.V1 = &extension
+	err := testAccounts.InsertFullAccount(
+		tt.Ctx,
+		q,
+		1234,
+		account,
+		[]xdr.TrustLineEntry{*usdTrustLine.Data.TrustLine},
+		[]xdr.DataEntry{*data2.Data.Data},
+	)
+	tt.Assert.NoError(err)
+
+	response, err := handler.GetResource(
+		httptest.NewRecorder(),
+		makeRequest(t, map[string]string{}, map[string]string{"account_id": accountTwo}, q),
+	)
+	tt.Assert.NoError(err)
+	result := response.(Account)
+	tt.Assert.Equal(accountTwo, result.ID)
+	tt.Assert.Equal("648736", result.Sequence)
+	tt.Assert.Equal(uint32(1234), result.LastModifiedLedger)
+
+	if tt.Assert.Len(result.Balances, 2) {
+		tt.Assert.Equal("USD", result.Balances[0].Code)
+		tt.Assert.Equal("0.0010000", result.Balances[0].Balance)
+		tt.Assert.Equal("native", result.Balances[1].Type)
+		tt.Assert.Equal("0.0050000", result.Balances[1].Balance)
+		tt.Assert.Equal("0.0000030", result.Balances[1].BuyingLiabilities)
+	}
+
+	signers := map[string]protocol.Signer{}
+	for _, s := range result.Signers {
+		signers[s.Key] = s
+	}
+	tt.Assert.Len(signers, 2)
+	tt.Assert.Equal(int32(1), signers[accountTwo].Weight)
+	tt.Assert.Equal(int32(3), signers[signer].Weight)
+	tt.Assert.Equal(sponsor.Address(), signers[signer].Sponsor)
+
+	tt.Assert.Equal(map[string]string{"test data2": "CgsMDQ4PEBESEw=="}, result.Data)
+}
+
+func TestGetAccountByIDHandlerSponsoring(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+
+	q := &history.Q{tt.HorizonSession()}
+	handler := GetAccountByIDHandler{}
+
+	batch := q.NewAccountsBatchInsertBuilder(0)
+	tt.Assert.NoError(batch.Add(tt.Ctx, sponsoringEntries(account1, 1)))
+	tt.Assert.NoError(batch.Add(tt.Ctx, account2))
+	tt.Assert.NoError(batch.Exec(tt.Ctx))
+
+	// account1 sponsors a signer of account2
+	sponsorAddress := accountOne
+	_, err := q.CreateAccountSigner(tt.Ctx, accountTwo, signer, 1, &sponsorAddress)
+	tt.Assert.NoError(err)
+
+	getAccount := func(accountID string, params map[string]string) Account {
+		response, err := handler.GetResource(
+			httptest.NewRecorder(),
+			makeRequest(t, params, map[string]string{"account_id": accountID}, q),
+		)
+		tt.Assert.NoError(err)
+		return response.(Account)
+	}
+
+	// the sponsored signers are only included on request
+	tt.Assert.Nil(getAccount(accountOne, map[string]string{}).Sponsoring)
+
+	includeSponsoring := map[string]string{"include_sponsoring": "true"}
+	tt.Assert.Equal(
+		[]protocol.SponsoredSigner{{AccountID: accountTwo, Key: signer}},
+		getAccount(accountOne, includeSponsoring).Sponsoring,
+	)
+	tt.Assert.Empty(getAccount(accountTwo, includeSponsoring).Sponsoring)
+}
+
+func TestGetAccountByIDHandlerEmbedInflationDest(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+
+	q := &history.Q{tt.HorizonSession()}
+	handler := GetAccountByIDHandler{}
+
+	withInflationDest := func(entry xdr.LedgerEntry, dest string) xdr.LedgerEntry {
+		account := *entry.Data.Account
+		inflationDest := xdr.MustAddress(dest)
+		account.InflationDest = &inflationDest
+		entry.Data.Account = &account
+		return entry
+	}
+
+	// account1 -> account2 -> account1 is a cycle and account3 points at itself
+	batch := q.NewAccountsBatchInsertBuilder(0)
+	tt.Assert.NoError(batch.Add(tt.Ctx, withInflationDest(account1, accountTwo)))
+	tt.Assert.NoError(batch.Add(tt.Ctx, withInflationDest(account2, accountOne)))
+	tt.Assert.NoError(batch.Add(tt.Ctx, withInflationDest(account3, signer)))
+	tt.Assert.NoError(batch.Exec(tt.Ctx))
+
+	response, err := handler.GetResource(
+		httptest.NewRecorder(),
+		makeRequest(t, map[string]string{}, map[string]string{"account_id": accountOne}, q),
+	)
+	tt.Assert.NoError(err)
+	tt.Assert.Nil(response.(Account).Embedded)
+
+	embed := map[string]string{"embed_inflation_dest": "true"}
+	response, err = handler.GetResource(
+		httptest.NewRecorder(),
+		makeRequest(t, embed, map[string]string{"account_id": accountOne}, q),
+	)
+	tt.Assert.NoError(err)
+	account := response.(Account)
+	tt.Assert.Equal(accountTwo, account.InflationDestination)
+	if tt.Assert.NotNil(account.Embedded) && tt.Assert.NotNil(account.Embedded.InflationDestination) {
+		inflationDest := account.Embedded.InflationDestination
+		tt.Assert.Equal(accountTwo, inflationDest.AccountID)
+		tt.Assert.Equal(accountOne, inflationDest.InflationDestination)
+		// account1 is not embedded again
+		if tt.Assert.NotNil(inflationDest.Embedded) {
+			tt.Assert.True(inflationDest.Embedded.CycleDetected)
+			tt.Assert.Nil(inflationDest.Embedded.InflationDestination)
+		}
+		tt.Assert.False(account.Embedded.CycleDetected)
+	}
+
+	response, err = handler.GetResource(
+		httptest.NewRecorder(),
+		makeRequest(t, embed, map[string]string{"account_id": signer}, q),
+	)
+	tt.Assert.NoError(err)
+	account = response.(Account)
+	tt.Assert.Equal(signer, account.InflationDestination)
+	if tt.Assert.NotNil(account.Embedded) {
+		tt.Assert.True(account.Embedded.CycleDetected)
+		tt.Assert.Nil(account.Embedded.InflationDestination)
+	}
+}
+
+func TestUniqueAccountEntries(t *testing.T) {
+	records := []history.AccountEntry{
+		{AccountID: accountOne, Balance: 1},
+		{AccountID: accountTwo, Balance: 2},
+		{AccountID: accountOne, Balance: 3},
+		{AccountID: signer, Balance: 4},
+		{AccountID: accountTwo, Balance: 5},
+	}
+
+	unique := uniqueAccountEntries(records)
+	assert.Equal(t, []history.AccountEntry{
+		{AccountID: accountOne, Balance: 1},
+		{AccountID: accountTwo, Balance: 2},
+		{AccountID: signer, Balance: 4},
+	}, unique)
+	// the input is left untouched
+	assert.Len(t, records, 5)
+
+	assert.Empty(t, uniqueAccountEntries(nil))
+}
+
+func TestGetAccountsHandlerPartialResponse(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+
+	q := &history.Q{tt.HorizonSession()}
+	handler := &GetAccountsHandler{}
+
+	batch := q.NewAccountsBatchInsertBuilder(0)
+	tt.Assert.NoError(batch.Add(tt.Ctx, account1))
+	tt.Assert.NoError(batch.Add(tt.Ctx, account2))
+	tt.Assert.NoError(batch.Exec(tt.Ctx))
+
+	for _, row := range accountSigners {
+		_, err := q.CreateAccountSigner(tt.Ctx, row.Account, row.Signer, row.Weight, nil)
+		tt.Assert.NoError(err)
+	}
+
+	_, err := q.InsertTrustLine(tt.Ctx, usdTrustLine)
+	tt.Assert.NoError(err)
+	_, err = q.InsertAccountData(tt.Ctx, data2)
+	tt.Assert.NoError(err)
+
+	// make loading trustlines fail
+	_, err = tt.HorizonSession().ExecRaw(tt.Ctx, "ALTER TABLE trust_lines RENAME TO trust_lines_backup")
+	tt.Assert.NoError(err)
+	defer func() {
+		_, err = tt.HorizonSession().ExecRaw(tt.Ctx, "ALTER TABLE trust_lines_backup RENAME TO trust_lines")
+		tt.Assert.NoError(err)
+	}()
+
+	params := map[string]string{"signer": signer}
+	_, err = handler.GetResourcePage(
+		httptest.NewRecorder(),
+		makeRequest(t, params, map[string]string{}, q),
+	)
+	tt.Assert.Error(err)
+
+	params["allow_partial"] = "true"
+	records, err := handler.GetResourcePage(
+		httptest.NewRecorder(),
+		makeRequest(t, params, map[string]string{}, q),
+	)
+	tt.Assert.NoError(err)
+	tt.Assert.Len(records, 2)
+	for _, record := range records {
+		result := record.(Account)
+		tt.Assert.True(result.Partial)
+		tt.Assert.Equal([]string{"could not load trustlines"}, result.Warnings)
+		// only the native balance is included
+		tt.Assert.Len(result.Balances, 1)
+		tt.Assert.Equal("native", result.Balances[0].Type)
+		tt.Assert.Len(result.Signers, 2)
+	}
+
+	accountTwoResult := records[1].(Account)
+	tt.Assert.Equal(accountTwo, accountTwoResult.AccountID)
+	_, ok := accountTwoResult.Data[string(data2.Data.Data.DataName)]
+	tt.Assert.True(ok)
+}
+
+func TestAccountsHandlersHistoryUnavailable(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+
+	// nothing listens on port 1, so every query fails to connect
+	deadDB, err := sqlx.Open("postgres", "postgres://127.0.0.1:1/horizon?sslmode=disable")
+	tt.Assert.NoError(err)
+	defer deadDB.Close()
+	session := &db.Session{DB: deadDB}
+
+	_, err = (&GetAccountsHandler{}).GetResourcePage(
+		httptest.NewRecorder(),
+		makeRequest(t, map[string]string{"signer": accountOne}, map[string]string{}, session),
+	)
+	tt.Assert.Equal(hProblem.HistoryUnavailable, err)
+
+	_, err = (&GetAccountByIDHandler{}).GetResource(
+		httptest.NewRecorder(),
+		makeRequest(t, map[string]string{}, map[string]string{"account_id": accountOne}, session),
+	)
+	tt.Assert.Equal(hProblem.HistoryUnavailable, err)
+}
+
+// queryRecordingSession records the SQL of the queries run through it.
+type queryRecordingSession struct {
+	db.SessionInterface
+	queries []string
+}
+
+func (s *queryRecordingSession) record(query sq.Sqlizer) {
+	sql, _, _ := query.ToSql()
+	s.queries = append(s.queries, sql)
+}
+
+func (s *queryRecordingSession) Get(ctx context.Context, dest interface{}, query sq.Sqlizer) error {
+	s.record(query)
+	return s.SessionInterface.Get(ctx, dest, query)
+}
+
+func (s *queryRecordingSession) Select(ctx context.Context, dest interface{}, query sq.Sqlizer) error {
+	s.record(query)
+	return s.SessionInterface.Select(ctx, dest, query)
+}
+
+func TestGetAccountByIDHandlerNativeOnly(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+
+	q := &history.Q{tt.HorizonSession()}
+	handler := GetAccountByIDHandler{}
+
+	batch := q.NewAccountsBatchInsertBuilder(0)
+	tt.Assert.NoError(batch.Add(tt.Ctx, account1))
+	tt.Assert.NoError(batch.Exec(tt.Ctx))
+	_, err := q.InsertTrustLine(tt.Ctx, eurTrustLine)
+	tt.Assert.NoError(err)
+
+	getAccount := func(params map[string]string) (Account, []string) {
+		session := &queryRecordingSession{SessionInterface: q}
+		response, err := handler.GetResource(
+			httptest.NewRecorder(),
+			makeRequest(t, params, map[string]string{"account_id": accountOne}, session),
+		)
+		tt.Assert.NoError(err)
+		return response.(Account), session.queries
+	}
+	usesTrustLines := func(queries []string) bool {
+		for _, query := range queries {
+			if strings.Contains(query, "trust_line") {
+				return true
+			}
+		}
+		return false
+	}
+
+	account, queries := getAccount(map[string]string{})
+	tt.Assert.Len(account.Balances, 2)
+	tt.Assert.True(usesTrustLines(queries))
+
+	account, queries = getAccount(map[string]string{"native_only": "true"})
+	tt.Assert.False(usesTrustLines(queries))
+	if tt.Assert.Len(account.Balances, 1) {
+		tt.Assert.Equal("native", account.Balances[0].Type)
+		tt.Assert.Equal("0.0020000", account.Balances[0].Balance)
+	}
+	tt.Assert.Equal(accountOne, account.AccountID)
+	tt.Assert.Equal("223456789", account.Sequence)
+	tt.Assert.Equal("stellar.org", account.HomeDomain)
+}
+
+func TestGetAccountByIDHandlerOffersCount(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+
+	q := &history.Q{tt.HorizonSession()}
+	handler := GetAccountByIDHandler{}
+
+	batch := q.NewAccountsBatchInsertBuilder(0)
+	tt.Assert.NoError(batch.Add(tt.Ctx, account1))
+	tt.Assert.NoError(batch.Exec(tt.Ctx))
+
+	getAccount := func(params map[string]string) Account {
+		response, err := handler.GetResource(
+			httptest.NewRecorder(),
+			makeRequest(t, params, map[string]string{"account_id": accountOne}, q),
+		)
+		tt.Assert.NoError(err)
+		return response.(Account)
+	}
+
+	tt.Assert.Nil(getAccount(map[string]string{}).OffersCount)
+	account := getAccount(map[string]string{"include_offers_count": "true"})
+	if tt.Assert.NotNil(account.OffersCount) {
+		tt.Assert.Equal(int32(0), *account.OffersCount)
+	}
+
+	// accountOne has two open offers, accountTwo one, and a removed offer
+	// of accountOne isn't counted
+	offers := q.NewOffersBatchInsertBuilder(0)
+	for i, seller := range []string{accountOne, accountOne, accountTwo, accountOne} {
+		tt.Assert.NoError(offers.Add(tt.Ctx, history.Offer{
+			SellerID:           seller,
+			OfferID:            int64(i + 1),
+			BuyingAsset:        usd,
+			SellingAsset:       xdr.MustNewNativeAsset(),
+			Amount:             100,
+			Pricen:             1,
+			Priced:             1,
+			Price:              1,
+			LastModifiedLedger: 1234,
+		}))
+	}
+	tt.Assert.NoError(offers.Exec(tt.Ctx))
+	_, err := q.RemoveOffers(tt.Ctx, []int64{4}, 1235)
+	tt.Assert.NoError(err)
+
+	tt.Assert.Nil(getAccount(map[string]string{}).OffersCount)
+	account = getAccount(map[string]string{"include_offers_count": "true"})
+	if tt.Assert.NotNil(account.OffersCount) {
+		tt.Assert.Equal(int32(2), *account.OffersCount)
+	}
+}
+
+func TestGetAccountByIDHandlerAuthorizedLedger(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+
+	q := &history.Q{tt.HorizonSession()}
+	handler := GetAccountByIDHandler{}
+
+	batch := q.NewAccountsBatchInsertBuilder(0)
+	tt.Assert.NoError(batch.Add(tt.Ctx, account1))
+	tt.Assert.NoError(batch.Exec(tt.Ctx))
+	_, err := q.InsertTrustLine(tt.Ctx, eurTrustLine)
+	tt.Assert.NoError(err)
+	tt.Assert.NoError(q.InsertTrustLineAuthorizations(tt.Ctx, 100, []xdr.LedgerEntry{eurTrustLine}))
+
+	getEURBalance := func(params map[string]string) protocol.Balance {
+		response, err := handler.GetResource(
+			httptest.NewRecorder(),
+			makeRequest(t, params, map[string]string{"account_id": accountOne}, q),
+		)
+		tt.Assert.NoError(err)
+		balances := response.(Account).Balances
+		tt.Assert.Len(balances, 2)
+		return balances[0]
+	}
+
+	// the authorizations are only loaded on request
+	tt.Assert.Zero(getEURBalance(map[string]string{}).AuthorizedLedger)
+	tt.Assert.Equal(
+		uint32(100),
+		getEURBalance(map[string]string{"include_authorized_ledger": "true"}).AuthorizedLedger,
+	)
+}
+
+func TestGetAccountByIDHandlerRecentSignerChanges(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+
+	q := &history.Q{tt.HorizonSession()}
+	handler := GetAccountByIDHandler{}
+
+	batch := q.NewAccountsBatchInsertBuilder(0)
+	tt.Assert.NoError(batch.Add(tt.Ctx, account1))
+	tt.Assert.NoError(batch.Add(tt.Ctx, account2))
+	tt.Assert.NoError(batch.Exec(tt.Ctx))
+
+	getAccount := func(params map[string]string) Account {
+		response, err := handler.GetResource(
+			httptest.NewRecorder(),
+			makeRequest(t, params, map[string]string{"account_id": accountOne}, q),
+		)
+		tt.Assert.NoError(err)
+		return response.(Account)
+	}
+
+	// without signer effects, nothing is embedded
+	tt.Assert.Nil(getAccount(map[string]string{"include_recent_signer_changes": "true"}).Embedded)
+
+	accountIDs, err := q.CreateAccounts(tt.Ctx, []string{accountOne, accountTwo}, 2)
+	tt.Assert.NoError(err)
+	effects := q.NewEffectBatchInsertBuilder(0)
+	addEffect := func(account string, ledger int32, effectType history.EffectType, details map[string]interface{}) {
+		encoded, err := json.Marshal(details)
+		tt.Assert.NoError(err)
+		tt.Assert.NoError(effects.Add(
+			tt.Ctx,
+			accountIDs[account],
+			null.String{},
+			toid.New(ledger, 1, 1).ToInt64(),
+			1,
+			effectType,
+			encoded,
+		))
+	}
+	for ledger := int32(10); ledger < 16; ledger++ {
+		addEffect(accountOne, ledger, history.EffectSignerUpdated, map[string]interface{}{
+			"public_key": signer,
+			"weight":     ledger,
+		})
+	}
+	addEffect(accountOne, 16, history.EffectSignerRemoved, map[string]interface{}{"public_key": signer})
+	addEffect(accountOne, 17, history.EffectAccountHomeDomainUpdated, map[string]interface{}{"home_domain": "stellar.org"})
+	addEffect(accountTwo, 18, history.EffectSignerCreated, map[string]interface{}{
+		"public_key": signer,
+		"weight":     1,
+	})
+	tt.Assert.NoError(effects.Exec(tt.Ctx))
+
+	tt.Assert.Nil(getAccount(map[string]string{}).Embedded)
+
+	account := getAccount(map[string]string{"include_recent_signer_changes": "true"})
+	tt.Assert.NotNil(account.Embedded)
+	changes := account.Embedded.RecentSignerChanges
+	if tt.Assert.Len(changes, maxRecentSignerChanges) {
+		tt.Assert.Equal(protocol.SignerChange{
+			Type:        "signer_removed",
+			Key:         signer,
+			OperationID: strconv.FormatInt(toid.New(16, 1, 1).ToInt64(), 10),
+			Ledger:      16,
+		}, changes[0])
+		tt.Assert.Equal(protocol.SignerChange{
+			Type:        "signer_updated",
+			Key:         signer,
+			Weight:      12,
+			OperationID: strconv.FormatInt(toid.New(12, 1, 1).ToInt64(), 10),
+			Ledger:      12,
+		}, changes[4])
+	}
 }
@@ -0,0 +1,93 @@
+package actions
+
+import (
+	"net/http"
+	"strings"
+
+	protocol "github.com/stellar/go/protocols/horizon"
+	horizonContext "github.com/stellar/go/services/horizon/internal/context"
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/support/render/problem"
+)
+
+// SimulateThresholdHandler is the action handler for the
+// `POST /accounts/{account_id}/simulate-threshold` endpoint. Given the
+// comma separated signer keys of the `signers` field, it responds with the
+// operation categories (by threshold) they can authorize on the account.
+type SimulateThresholdHandler struct{}
+
+// GetResource returns the combined weight of the signers and the thresholds
+// it reaches.
+func (handler SimulateThresholdHandler) GetResource(w HeaderWriter, r *http.Request) (interface{}, error) {
+	accountID, err := getAccountID(r, "account_id")
+	if err != nil {
+		return nil, err
+	}
+	keys, err := getSimulatedSigners(r)
+	if err != nil {
+		return nil, err
+	}
+
+	historyQ, err := horizonContext.HistoryQFromRequest(r)
+	if err != nil {
+		return nil, err
+	}
+	account, err := AccountInfo(r.Context(), historyQ, accountID.Address())
+	if err != nil {
+		return nil, historyUnavailableProblem(err)
+	}
+
+	var weight int32
+	for _, signer := range account.Signers {
+		if keys[signer.Key] {
+			weight += signer.Weight
+		}
+	}
+
+	return protocol.AccountThresholdSimulation{
+		AccountID:     account.AccountID,
+		Weight:        weight,
+		AuthorizeLow:  reachesThreshold(weight, account.Thresholds.LowThreshold),
+		AuthorizeMed:  reachesThreshold(weight, account.Thresholds.MedThreshold),
+		AuthorizeHigh: reachesThreshold(weight, account.Thresholds.HighThreshold),
+	}, nil
+}
+
+// getSimulatedSigners returns the set of the signer keys of the comma
+// separated `signers` field.
+func getSimulatedSigners(r *http.Request) (map[string]bool, error) {
+	raw, err := getString(r, "signers")
+	if err != nil {
+		return nil, err
+	}
+	if raw == "" {
+		return nil, problem.MakeInvalidFieldProblem(
+			"signers",
+			errors.New("at least one signer key is required"),
+		)
+	}
+
+	keys := map[string]bool{}
+	for _, key := range strings.Split(raw, ",") {
+		if _, _, err = strkey.DecodeAny(key); err != nil {
+			return nil, problem.MakeInvalidFieldProblem(
+				"signers",
+				errors.Errorf("invalid signer key: %s", key),
+			)
+		}
+		keys[key] = true
+	}
+	return keys, nil
+}
+
+// reachesThreshold returns true if signatures with the given combined weight
+// can authorize operations with the threshold. Like stellar-core, a threshold
+// of 0 still requires a signature with a nonzero weight.
+func reachesThreshold(weight int32, threshold byte) bool {
+	neededWeight := int32(threshold)
+	if neededWeight == 0 {
+		neededWeight = 1
+	}
+	return weight >= neededWeight
+}
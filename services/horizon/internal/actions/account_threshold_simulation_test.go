@@ -0,0 +1,111 @@
+package actions
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	protocol "github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/services/horizon/internal/db2/history"
+	"github.com/stellar/go/services/horizon/internal/test"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/support/render/problem"
+)
+
+func TestSimulateThresholdHandler(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+
+	q := &history.Q{tt.HorizonSession()}
+	handler := SimulateThresholdHandler{}
+
+	batch := q.NewAccountsBatchInsertBuilder(0)
+	tt.Assert.NoError(batch.Add(tt.Ctx, account1))
+	tt.Assert.NoError(batch.Exec(tt.Ctx))
+	for _, row := range accountSigners {
+		_, err := q.CreateAccountSigner(tt.Ctx, row.Account, row.Signer, row.Weight, nil)
+		tt.Assert.NoError(err)
+	}
+	// with accountTwo, the signers of accountOne reach its high threshold (4)
+	_, err := q.CreateAccountSigner(tt.Ctx, accountOne, accountTwo, 2, nil)
+	tt.Assert.NoError(err)
+
+	for _, testCase := range []struct {
+		name     string
+		signers  []string
+		expected protocol.AccountThresholdSimulation
+	}{
+		{
+			name:     "master key only",
+			signers:  []string{accountOne},
+			expected: protocol.AccountThresholdSimulation{AccountID: accountOne, Weight: 1},
+		},
+		{
+			name:    "master key and signer",
+			signers: []string{accountOne, signer},
+			expected: protocol.AccountThresholdSimulation{
+				AccountID:    accountOne,
+				Weight:       2,
+				AuthorizeLow: true,
+			},
+		},
+		{
+			name:    "repeated and unknown signers",
+			signers: []string{signer, signer, trustLineIssuer},
+			expected: protocol.AccountThresholdSimulation{
+				AccountID: accountOne,
+				Weight:    1,
+			},
+		},
+		{
+			name:    "all signers",
+			signers: []string{accountOne, signer, accountTwo},
+			expected: protocol.AccountThresholdSimulation{
+				AccountID:     accountOne,
+				Weight:        4,
+				AuthorizeLow:  true,
+				AuthorizeMed:  true,
+				AuthorizeHigh: true,
+			},
+		},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			response, err := handler.GetResource(
+				httptest.NewRecorder(),
+				makeRequest(
+					t,
+					map[string]string{"signers": strings.Join(testCase.signers, ",")},
+					map[string]string{"account_id": accountOne},
+					q,
+				),
+			)
+			tt.Assert.NoError(err)
+			tt.Assert.Equal(testCase.expected, response)
+		})
+	}
+
+	_, err = handler.GetResource(
+		httptest.NewRecorder(),
+		makeRequest(t, map[string]string{"signers": "bad"}, map[string]string{"account_id": accountOne}, q),
+	)
+	tt.Assert.Error(err)
+	p, ok := err.(*problem.P)
+	tt.Assert.True(ok)
+	tt.Assert.Equal("signers", p.Extras["invalid_field"])
+	tt.Assert.Equal("invalid signer key: bad", p.Extras["reason"])
+
+	_, err = handler.GetResource(
+		httptest.NewRecorder(),
+		makeRequest(t, map[string]string{}, map[string]string{"account_id": accountOne}, q),
+	)
+	p, ok = err.(*problem.P)
+	tt.Assert.True(ok)
+	tt.Assert.Equal("at least one signer key is required", p.Extras["reason"])
+
+	_, err = handler.GetResource(
+		httptest.NewRecorder(),
+		makeRequest(t, map[string]string{"signers": signer}, map[string]string{"account_id": accountTwo}, q),
+	)
+	tt.Assert.True(q.NoRows(errors.Cause(err)))
+}
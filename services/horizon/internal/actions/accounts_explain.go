@@ -0,0 +1,81 @@
+package actions
+
+import (
+	"net/http"
+
+	horizonContext "github.com/stellar/go/services/horizon/internal/context"
+	"github.com/stellar/go/services/horizon/internal/db2/history"
+	"github.com/stellar/go/services/horizon/internal/ledger"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/support/render/problem"
+)
+
+// AccountsExplainQuery query struct for the explain mode of the accounts
+// end-point. explain=true returns the SQL of the query, explain=plan also
+// includes the plan chosen by postgres.
+type AccountsExplainQuery struct {
+	Explain string `schema:"explain" valid:"in(true|plan)~Accepted values: true or plan,required"`
+}
+
+// AccountsQueryExplanation is the response of the explain mode of the
+// accounts end-point.
+type AccountsQueryExplanation struct {
+	SQL  string        `json:"sql"`
+	Args []interface{} `json:"args"`
+	Plan []string      `json:"plan,omitempty"`
+}
+
+// ExplainAccountsHandler is the action handler for the explain mode of the
+// /accounts endpoint. It's only served on the admin port, for operators
+// debugging slow filters.
+type ExplainAccountsHandler struct {
+	LedgerState *ledger.State
+}
+
+// GetResource returns the query the /accounts endpoint would run for the
+// signer or asset filter in the request, without executing it.
+func (handler ExplainAccountsHandler) GetResource(w HeaderWriter, r *http.Request) (interface{}, error) {
+	params, err := ParseAccountsParams(handler.LedgerState, r)
+	if err != nil {
+		return nil, err
+	}
+	qp, pq := params.AccountsQuery, params.PageQuery
+
+	eq := AccountsExplainQuery{}
+	if err = getParams(&eq, r); err != nil {
+		return nil, err
+	}
+	withPlan := eq.Explain == "plan"
+
+	historyQ, err := horizonContext.HistoryQFromRequest(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var explanation history.QueryExplanation
+	switch {
+	case len(params.Signers) > 1:
+		return nil, problem.MakeInvalidFieldProblem(
+			"explain",
+			errors.New("explain is only supported for a single signer"),
+		)
+	case len(qp.Signer) > 0:
+		explanation, err = historyQ.ExplainAccountEntriesForSigner(r.Context(), qp.Signer, pq, withPlan)
+	case len(qp.AssetFilter) > 0:
+		explanation, err = historyQ.ExplainAccountsForAsset(r.Context(), *qp.Asset(), pq, withPlan)
+	default:
+		return nil, problem.MakeInvalidFieldProblem(
+			"explain",
+			errors.New("explain is only supported for the signer and asset filters"),
+		)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "explaining account records query")
+	}
+
+	return AccountsQueryExplanation{
+		SQL:  explanation.SQL,
+		Args: explanation.Args,
+		Plan: explanation.Plan,
+	}, nil
+}
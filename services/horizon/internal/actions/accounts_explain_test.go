@@ -0,0 +1,68 @@
+package actions
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stellar/go/services/horizon/internal/db2/history"
+	"github.com/stellar/go/services/horizon/internal/test"
+	"github.com/stellar/go/support/render/problem"
+)
+
+func TestExplainAccountsHandler(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+
+	q := &history.Q{tt.HorizonSession()}
+	handler := ExplainAccountsHandler{}
+
+	response, err := handler.GetResource(
+		httptest.NewRecorder(),
+		makeRequest(t, map[string]string{
+			"asset":   "USD:" + trustLineIssuer,
+			"explain": "true",
+		}, map[string]string{}, q),
+	)
+	tt.Assert.NoError(err)
+	explanation := response.(AccountsQueryExplanation)
+	tt.Assert.Contains(explanation.SQL, "JOIN trust_lines ON accounts.account_id = trust_lines.account_id")
+	tt.Assert.Contains(explanation.SQL, "WHERE trust_lines.asset_code = $1 AND trust_lines.asset_issuer = $2 AND trust_lines.asset_type = $3")
+	tt.Assert.Equal([]interface{}{"USD", trustLineIssuer, int32(1)}, explanation.Args)
+	tt.Assert.Empty(explanation.Plan)
+
+	response, err = handler.GetResource(
+		httptest.NewRecorder(),
+		makeRequest(t, map[string]string{
+			"signer":  accountOne,
+			"explain": "plan",
+		}, map[string]string{}, q),
+	)
+	tt.Assert.NoError(err)
+	explanation = response.(AccountsQueryExplanation)
+	tt.Assert.Contains(explanation.SQL, "accounts_signers.signer = $1")
+	tt.Assert.NotEmpty(explanation.Plan)
+
+	_, err = handler.GetResource(
+		httptest.NewRecorder(),
+		makeRequest(t, map[string]string{
+			"sponsor": accountOne,
+			"explain": "true",
+		}, map[string]string{}, q),
+	)
+	if tt.Assert.IsType(&problem.P{}, err) {
+		p := err.(*problem.P)
+		tt.Assert.Equal("explain", p.Extras["invalid_field"])
+	}
+
+	_, err = handler.GetResource(
+		httptest.NewRecorder(),
+		makeRequest(t, map[string]string{
+			"asset": "USD:" + trustLineIssuer,
+		}, map[string]string{}, q),
+	)
+	if tt.Assert.IsType(&problem.P{}, err) {
+		p := err.(*problem.P)
+		tt.Assert.Equal("explain", p.Extras["invalid_field"])
+	}
+}
@@ -0,0 +1,203 @@
+package actions
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/stellar/throttled"
+
+	hProblem "github.com/stellar/go/services/horizon/internal/render/problem"
+	"github.com/stellar/go/support/errors"
+)
+
+// AccountsFilterType identifies the filter used in a request to /accounts.
+type AccountsFilterType string
+
+const (
+	// AccountsSignerFilter is used for requests filtering by signer.
+	AccountsSignerFilter AccountsFilterType = "signer"
+	// AccountsSponsorFilter is used for requests filtering by sponsor.
+	AccountsSponsorFilter AccountsFilterType = "sponsor"
+	// AccountsAssetFilter is used for requests filtering by asset.
+	AccountsAssetFilter AccountsFilterType = "asset"
+	// AccountsWeakThresholdsFilter is used for requests filtering accounts
+	// with weak thresholds.
+	AccountsWeakThresholdsFilter AccountsFilterType = "weak_thresholds"
+	// AccountsLiabilitiesFilter is used for requests filtering accounts
+	// with liabilities.
+	AccountsLiabilitiesFilter AccountsFilterType = "has_liabilities"
+	// AccountsNoHomeDomainFilter is used for requests filtering accounts
+	// without a home domain.
+	AccountsNoHomeDomainFilter AccountsFilterType = "no_home_domain"
+	// AccountsHomeDomainFilter is used for requests filtering accounts by
+	// home domain.
+	AccountsHomeDomainFilter AccountsFilterType = "home_domain"
+	// AccountsSignerTypeFilter is used for requests filtering accounts by
+	// the type of their signers.
+	AccountsSignerTypeFilter AccountsFilterType = "signer_type"
+	// AccountsThresholdFilter is used for requests filtering accounts by
+	// the value of one of their thresholds.
+	AccountsThresholdFilter AccountsFilterType = "threshold"
+	// AccountsDataValueFilter is used for requests filtering accounts by
+	// the value of a data entry.
+	AccountsDataValueFilter AccountsFilterType = "data_value"
+	// AccountsModifiedFilter is used for requests filtering accounts
+	// modified in a ledger range.
+	AccountsModifiedFilter AccountsFilterType = "modified"
+	// AccountsNativeBalanceSort is used for requests listing all the
+	// accounts sorted by native balance.
+	AccountsNativeBalanceSort AccountsFilterType = "native_balance"
+)
+
+// accountsFilterTypes are all the filter types which can be throttled.
+var accountsFilterTypes = []AccountsFilterType{
+	AccountsSignerFilter,
+	AccountsSponsorFilter,
+	AccountsAssetFilter,
+	AccountsWeakThresholdsFilter,
+	AccountsLiabilitiesFilter,
+	AccountsNoHomeDomainFilter,
+	AccountsHomeDomainFilter,
+	AccountsSignerTypeFilter,
+	AccountsThresholdFilter,
+	AccountsDataValueFilter,
+	AccountsModifiedFilter,
+	AccountsNativeBalanceSort,
+}
+
+// accountsFilterRateLimiterCacheSize is the number of clients tracked by the
+// rate limiter of each filter type.
+const accountsFilterRateLimiterCacheSize = 50000
+
+// ParseAccountsFilterRateQuotas parses a comma separated list of
+// filter=count pairs, like "asset=600,weak_thresholds=60", into the quotas of
+// a GCRAAccountsFilterRateLimiter. count is the number of requests using the
+// filter allowed in a one hour period.
+func ParseAccountsFilterRateQuotas(value string) (map[AccountsFilterType]throttled.RateQuota, error) {
+	quotas := map[AccountsFilterType]throttled.RateQuota{}
+	if strings.TrimSpace(value) == "" {
+		return quotas, nil
+	}
+
+	for _, pair := range strings.Split(value, ",") {
+		parts := strings.SplitN(strings.TrimSpace(pair), "=", 2)
+		if len(parts) != 2 {
+			return nil, errors.Errorf("%q is not a filter=count pair", pair)
+		}
+
+		filter := AccountsFilterType(parts[0])
+		known := false
+		for _, filterType := range accountsFilterTypes {
+			if filter == filterType {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return nil, errors.Errorf("unknown accounts filter %q", parts[0])
+		}
+
+		perHour, err := strconv.Atoi(parts[1])
+		if err != nil || perHour <= 0 {
+			return nil, errors.Errorf("invalid count %q for %s filter", parts[1], filter)
+		}
+		quotas[filter] = throttled.RateQuota{
+			MaxRate: throttled.PerHour(perHour),
+		}
+	}
+	return quotas, nil
+}
+
+// AccountsFilterRateLimiter throttles requests to /accounts depending on the
+// filter used. Filters resulting in expensive scans (like asset) can be
+// limited more aggressively than cheap lookups (like signer).
+type AccountsFilterRateLimiter interface {
+	// Allow returns true if a request using the given filter can be served.
+	// When the request is denied, retryAfter contains the duration the client
+	// should wait before retrying.
+	Allow(r *http.Request, filter AccountsFilterType) (allowed bool, retryAfter time.Duration, err error)
+}
+
+// GCRAAccountsFilterRateLimiter is an AccountsFilterRateLimiter which keeps a
+// separate GCRA rate limiter for every configured filter type. Every client,
+// identified by the key varyBy derives from its requests, has its own quota
+// for each filter. Filter types without a configured quota are never
+// throttled.
+type GCRAAccountsFilterRateLimiter struct {
+	limiters map[AccountsFilterType]*throttled.GCRARateLimiter
+	varyBy   interface {
+		Key(*http.Request) string
+	}
+}
+
+// NewGCRAAccountsFilterRateLimiter creates a GCRAAccountsFilterRateLimiter
+// using the given quota for each filter type. Requests are grouped by the
+// key returned by varyBy, like the global rate limiter groups them by
+// remote IP.
+func NewGCRAAccountsFilterRateLimiter(
+	quotas map[AccountsFilterType]throttled.RateQuota,
+	varyBy interface {
+		Key(*http.Request) string
+	},
+) (*GCRAAccountsFilterRateLimiter, error) {
+	limiter := &GCRAAccountsFilterRateLimiter{
+		limiters: map[AccountsFilterType]*throttled.GCRARateLimiter{},
+		varyBy:   varyBy,
+	}
+	for filter, quota := range quotas {
+		gcra, err := throttled.NewGCRARateLimiter(accountsFilterRateLimiterCacheSize, quota)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not create rate limiter for %s filter", filter)
+		}
+		limiter.limiters[filter] = gcra
+	}
+	return limiter, nil
+}
+
+// Allow implements AccountsFilterRateLimiter.
+func (l *GCRAAccountsFilterRateLimiter) Allow(
+	r *http.Request,
+	filter AccountsFilterType,
+) (bool, time.Duration, error) {
+	gcra, ok := l.limiters[filter]
+	if !ok {
+		return true, 0, nil
+	}
+
+	limited, result, err := gcra.RateLimit(string(filter)+"/"+l.varyBy.Key(r), 1)
+	if err != nil {
+		return false, 0, errors.Wrap(err, "could not rate limit request")
+	}
+	return !limited, result.RetryAfter, nil
+}
+
+// checkAccountsFilterRateLimit returns a rate limit problem and sets the
+// Retry-After header if the request must be throttled.
+func checkAccountsFilterRateLimit(
+	limiter AccountsFilterRateLimiter,
+	w HeaderWriter,
+	r *http.Request,
+	filter AccountsFilterType,
+) error {
+	if limiter == nil {
+		return nil
+	}
+
+	allowed, retryAfter, err := limiter.Allow(r, filter)
+	if err != nil {
+		return err
+	}
+	if allowed {
+		return nil
+	}
+
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+	w.Header().Set("Retry-After", strconv.Itoa(seconds))
+	return hProblem.RateLimitExceeded
+}
@@ -0,0 +1,75 @@
+package actions
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stellar/throttled"
+	"github.com/stretchr/testify/assert"
+)
+
+type remoteAddrKey struct{}
+
+func (remoteAddrKey) Key(r *http.Request) string {
+	return r.RemoteAddr
+}
+
+func TestGCRAAccountsFilterRateLimiterByClient(t *testing.T) {
+	tt := assert.New(t)
+	limiter, err := NewGCRAAccountsFilterRateLimiter(map[AccountsFilterType]throttled.RateQuota{
+		AccountsAssetFilter:          {MaxRate: throttled.PerHour(1)},
+		AccountsWeakThresholdsFilter: {MaxRate: throttled.PerHour(1)},
+	}, remoteAddrKey{})
+	tt.NoError(err)
+
+	request := func(remoteAddr string) *http.Request {
+		r := httptest.NewRequest("GET", "/accounts", nil)
+		r.RemoteAddr = remoteAddr
+		return r
+	}
+
+	allowed, _, err := limiter.Allow(request("10.0.0.1"), AccountsAssetFilter)
+	tt.NoError(err)
+	tt.True(allowed)
+
+	allowed, retryAfter, err := limiter.Allow(request("10.0.0.1"), AccountsAssetFilter)
+	tt.NoError(err)
+	tt.False(allowed)
+	tt.True(retryAfter > 0)
+
+	// other clients and other filters have their own quota
+	allowed, _, err = limiter.Allow(request("10.0.0.2"), AccountsAssetFilter)
+	tt.NoError(err)
+	tt.True(allowed)
+	allowed, _, err = limiter.Allow(request("10.0.0.1"), AccountsWeakThresholdsFilter)
+	tt.NoError(err)
+	tt.True(allowed)
+
+	// filters without a quota are never throttled
+	for i := 0; i < 3; i++ {
+		allowed, _, err = limiter.Allow(request("10.0.0.1"), AccountsSignerFilter)
+		tt.NoError(err)
+		tt.True(allowed)
+	}
+}
+
+func TestParseAccountsFilterRateQuotas(t *testing.T) {
+	tt := assert.New(t)
+
+	quotas, err := ParseAccountsFilterRateQuotas("")
+	tt.NoError(err)
+	tt.Empty(quotas)
+
+	quotas, err = ParseAccountsFilterRateQuotas("asset=600, weak_thresholds=60")
+	tt.NoError(err)
+	tt.Equal(map[AccountsFilterType]throttled.RateQuota{
+		AccountsAssetFilter:          {MaxRate: throttled.PerHour(600)},
+		AccountsWeakThresholdsFilter: {MaxRate: throttled.PerHour(60)},
+	}, quotas)
+
+	for _, value := range []string{"asset", "asset=", "asset=0", "asset=x", "balance=10"} {
+		_, err = ParseAccountsFilterRateQuotas(value)
+		tt.Error(err, value)
+	}
+}
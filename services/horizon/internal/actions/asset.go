@@ -34,7 +34,7 @@ func (handler AssetStatsHandler) validateAssetParams(code, issuer string, pq db2
 	}
 
 	if issuer != "" {
-		if _, err := xdr.AddressToAccountId(issuer); err != nil {
+		if validateAccountStrkey(issuer) != nil {
 			return problem.MakeInvalidFieldProblem(
 				"asset_issuer",
 				fmt.Errorf("%s is not a valid asset issuer", issuer),
@@ -59,7 +59,7 @@ func (handler AssetStatsHandler) validateAssetParams(code, issuer string, pq db2
 			)
 		}
 
-		if _, err := xdr.AddressToAccountId(cursorIssuer); err != nil {
+		if validateAccountStrkey(cursorIssuer) != nil {
 			return problem.MakeInvalidFieldProblem(
 				"cursor",
 				fmt.Errorf("%s is not a valid asset issuer", cursorIssuer),
@@ -2,13 +2,19 @@ package actions
 
 import (
 	"context"
+	"database/sql"
+	"database/sql/driver"
 	"encoding/hex"
+	stderrors "errors"
 	"fmt"
+	"net"
 	"net/http"
 	"net/url"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 	"unicode/utf8"
 
 	"github.com/asaskevich/govalidator"
@@ -19,8 +25,10 @@ import (
 	horizonContext "github.com/stellar/go/services/horizon/internal/context"
 	"github.com/stellar/go/services/horizon/internal/db2"
 	"github.com/stellar/go/services/horizon/internal/ledger"
+	"github.com/stellar/go/services/horizon/internal/render"
 	hProblem "github.com/stellar/go/services/horizon/internal/render/problem"
 	"github.com/stellar/go/services/horizon/internal/toid"
+	"github.com/stellar/go/strkey"
 	"github.com/stellar/go/support/errors"
 	"github.com/stellar/go/support/render/problem"
 	"github.com/stellar/go/xdr"
@@ -37,6 +45,9 @@ const (
 	ParamLimit = "limit"
 	// LastLedgerHeaderName is the header which is set on all endpoints
 	LastLedgerHeaderName = "Latest-Ledger"
+	// HistoryStaleHeaderName is the header which is set on the responses
+	// served from a history database lagging behind stellar-core
+	HistoryStaleHeaderName = "X-History-Stale"
 )
 
 type Opt int
@@ -146,6 +157,22 @@ func getString(r *http.Request, name string) (string, error) {
 	return value, nil
 }
 
+// getStringSlice retrieves all the values of a query string parameter which
+// can be repeated, like `?signer=A&signer=B`, leaving out the blank ones.
+func getStringSlice(r *http.Request, name string) ([]string, error) {
+	var values []string
+	for _, value := range r.URL.Query()[name] {
+		if value == "" {
+			continue
+		}
+		if err := checkUTF8(name, value); err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
 // getLimit retrieves a uint64 limit from the action parameter of the given
 // name. Populates err if the value is not a valid limit.  Uses the provided
 // default value if the limit parameter is a blank string.
@@ -178,6 +205,46 @@ func getLimit(r *http.Request, name string, def uint64, max uint64) (uint64, err
 	return uint64(asI64), nil
 }
 
+// getBool retrieves a boolean from the action parameter of the given name,
+// false if the parameter is blank.
+func getBool(r *http.Request, name string) (bool, error) {
+	value, err := getString(r, name)
+	if err != nil || value == "" {
+		return false, err
+	}
+
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, problem.MakeInvalidFieldProblem(name, errors.New("unparseable value"))
+	}
+	return b, nil
+}
+
+// checkUnknownParams returns a bad request problem listing the query
+// parameters of the request which aren't parameters of the query struct.
+func checkUnknownParams(r *http.Request, query interface{}, paginated bool) error {
+	known := map[string]bool{}
+	for _, param := range getURIParams(query, paginated) {
+		known[param] = true
+	}
+
+	var unknown []string
+	for param := range r.URL.Query() {
+		if !known[param] {
+			unknown = append(unknown, param)
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+
+	sort.Strings(unknown)
+	p := problem.BadRequest
+	p.Detail = "The request has unrecognized query parameters: " + strings.Join(unknown, ", ")
+	p.Extras = map[string]interface{}{"unknown_params": unknown}
+	return &p
+}
+
 // GetPageQuery is a helper that returns a new db.PageQuery struct initialized
 // using the results from a call to GetPagingParams()
 func GetPageQuery(ledgerState *ledger.State, r *http.Request, opts ...Opt) (db2.PageQuery, error) {
@@ -196,6 +263,9 @@ func GetPageQuery(ledgerState *ledger.State, r *http.Request, opts ...Opt) (db2.
 	if err != nil {
 		return db2.PageQuery{}, err
 	}
+	// the order is case insensitive, "ASC" is "asc", anything other than asc
+	// or desc is rejected by NewPageQuery
+	order = strings.ToLower(order)
 	limit, err := getLimit(r, ParamLimit, db2.DefaultPageSize, db2.MaxPageSize)
 	if err != nil {
 		return db2.PageQuery{}, err
@@ -243,15 +313,50 @@ func getAccountID(r *http.Request, name string) (xdr.AccountId, error) {
 		return xdr.AccountId{}, err
 	}
 
-	result, err := xdr.AddressToAccountId(value)
-	if err != nil {
-		return result, problem.MakeInvalidFieldProblem(
-			name,
-			errors.New("invalid address"),
+	if err := validateAccountStrkey(value); err != nil {
+		return xdr.AccountId{}, problem.MakeInvalidFieldProblem(name, err)
+	}
+
+	return xdr.MustAddress(value), nil
+}
+
+// checkAssetCodeLength returns a problem when the length of code doesn't
+// match assetType, suggesting the asset type matching the code in the detail.
+func checkAssetCodeLength(prefix string, assetType xdr.AssetType, code string) error {
+	var expected xdr.AssetType
+	switch {
+	case len(code) == 0:
+		return nil
+	case len(code) <= len(xdr.AssetAlphaNum4{}.AssetCode):
+		expected = xdr.AssetTypeAssetTypeCreditAlphanum4
+	case len(code) <= len(xdr.AssetAlphaNum12{}.AssetCode):
+		expected = xdr.AssetTypeAssetTypeCreditAlphanum12
+	default:
+		return problem.MakeInvalidFieldProblem(
+			prefix+"asset_code",
+			errors.New("code too long"),
 		)
 	}
+	if assetType == expected {
+		return nil
+	}
 
-	return result, nil
+	expectedName, err := assets.String(expected)
+	if err != nil {
+		return err
+	}
+	p := problem.MakeInvalidFieldProblem(
+		prefix+"asset_type",
+		errors.Errorf("%s is not a valid asset code for this asset type", code),
+	)
+	p.Detail = fmt.Sprintf(
+		"The asset code %s has %d characters, use `%sasset_type=%s` instead.",
+		code,
+		len(code),
+		prefix,
+		expectedName,
+	)
+	return p
 }
 
 // getAssetType is a helper that returns a xdr.AssetType by reading a string
@@ -272,10 +377,50 @@ func getAssetType(r *http.Request, name string) (xdr.AssetType, error) {
 	return t, nil
 }
 
+// getAssetIssuer retrieves the issuer of an asset from the request. Muxed
+// accounts are rejected since only G-addresses can issue assets.
+func getAssetIssuer(r *http.Request, name string) (xdr.AccountId, error) {
+	value, err := getString(r, name)
+	if err != nil {
+		return xdr.AccountId{}, err
+	}
+
+	if version, err := strkey.Version(value); err == nil && version == strkey.VersionByteMuxedAccount {
+		return xdr.AccountId{}, problem.MakeInvalidFieldProblem(
+			name,
+			errors.New("asset issuer can't be a muxed account, only G-addresses can issue assets"),
+		)
+	}
+
+	return getAccountID(r, name)
+}
+
 // getAsset decodes an asset from the request fields prefixed by `prefix`.  To
-// succeed, three prefixed fields must be present: asset_type, asset_code, and
-// asset_issuer.
+// succeed, either the asset field in its canonical form (`native` or
+// `CODE:ISSUER`) or three prefixed fields must be present: asset_type,
+// asset_code, and asset_issuer.
 func getAsset(r *http.Request, prefix string) (xdr.Asset, error) {
+	canonical, err := getString(r, prefix+"asset")
+	if err != nil {
+		return xdr.Asset{}, err
+	}
+	if canonical != "" {
+		assetType, err := getString(r, prefix+"asset_type")
+		if err != nil {
+			return xdr.Asset{}, err
+		}
+		if assetType != "" {
+			return xdr.Asset{}, problem.MakeInvalidFieldProblem(
+				prefix+"asset_type",
+				errors.Errorf(
+					"Ambiguous parameter, you can't include both `%[1]sasset` and `%[1]sasset_type`",
+					prefix,
+				),
+			)
+		}
+		return parseCanonicalAsset(prefix+"asset", canonical)
+	}
+
 	var value interface{}
 	t, err := getAssetType(r, prefix+"asset_type")
 	if err != nil {
@@ -285,7 +430,7 @@ func getAsset(r *http.Request, prefix string) (xdr.Asset, error) {
 	switch t {
 	case xdr.AssetTypeAssetTypeCreditAlphanum4:
 		a := xdr.AssetAlphaNum4{}
-		a.Issuer, err = getAccountID(r, prefix+"asset_issuer")
+		a.Issuer, err = getAssetIssuer(r, prefix+"asset_issuer")
 		if err != nil {
 			return xdr.Asset{}, err
 		}
@@ -295,11 +440,7 @@ func getAsset(r *http.Request, prefix string) (xdr.Asset, error) {
 		if err != nil {
 			return xdr.Asset{}, err
 		}
-		if len(code) > len(a.AssetCode) {
-			err := problem.MakeInvalidFieldProblem(
-				prefix+"asset_code",
-				errors.New("code too long"),
-			)
+		if err = checkAssetCodeLength(prefix, t, code); err != nil {
 			return xdr.Asset{}, err
 		}
 
@@ -307,7 +448,7 @@ func getAsset(r *http.Request, prefix string) (xdr.Asset, error) {
 		value = a
 	case xdr.AssetTypeAssetTypeCreditAlphanum12:
 		a := xdr.AssetAlphaNum12{}
-		a.Issuer, err = getAccountID(r, prefix+"asset_issuer")
+		a.Issuer, err = getAssetIssuer(r, prefix+"asset_issuer")
 		if err != nil {
 			return xdr.Asset{}, err
 		}
@@ -317,11 +458,7 @@ func getAsset(r *http.Request, prefix string) (xdr.Asset, error) {
 		if err != nil {
 			return xdr.Asset{}, err
 		}
-		if len(code) > len(a.AssetCode) {
-			err := problem.MakeInvalidFieldProblem(
-				prefix+"asset_code",
-				errors.New("code too long"),
-			)
+		if err = checkAssetCodeLength(prefix, t, code); err != nil {
 			return xdr.Asset{}, err
 		}
 
@@ -337,6 +474,29 @@ func getAsset(r *http.Request, prefix string) (xdr.Asset, error) {
 	return result, nil
 }
 
+// parseCanonicalAsset parses an asset in its canonical form, either `native`
+// or `CODE:ISSUER`.
+func parseCanonicalAsset(name, value string) (xdr.Asset, error) {
+	if value == "native" {
+		return xdr.MustNewNativeAsset(), nil
+	}
+
+	parts := strings.Split(value, ":")
+	if len(parts) != 2 {
+		return xdr.Asset{}, problem.MakeInvalidFieldProblem(
+			name,
+			errors.New("missing colon"),
+		)
+	}
+
+	asset, err := xdr.NewCreditAsset(parts[0], parts[1])
+	if err != nil {
+		return xdr.Asset{}, problem.MakeInvalidFieldProblem(name, err)
+	}
+
+	return asset, nil
+}
+
 // getURLParam returns the corresponding URL parameter value from the request
 // routing context and an additional boolean reflecting whether or not the
 // param was found. This is ported from Chi since the Chi version returns ""
@@ -609,6 +769,59 @@ func countNonEmpty(params ...interface{}) (int, error) {
 	return count, nil
 }
 
+// ErrNotModified is returned by actions when the resource hasn't changed
+// since the time in the If-Modified-Since header of the request.
+var ErrNotModified = errors.New("not modified")
+
+// isConditionalRequest reports whether r has an If-Modified-Since header,
+// the only requests for which the last modification of a resource is looked
+// up.
+func isConditionalRequest(r *http.Request) bool {
+	return r.Header.Get("If-Modified-Since") != ""
+}
+
+// checkNotModified sets the Last-Modified header to lastModified and returns
+// ErrNotModified if the resource hasn't changed since the If-Modified-Since
+// header of the request. Missing or invalid headers are ignored, and
+// streaming requests are never conditional.
+func checkNotModified(w HeaderWriter, r *http.Request, lastModified *time.Time) error {
+	if lastModified == nil || render.Negotiate(r) == render.MimeEventStream {
+		return nil
+	}
+	w.Header().Set("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+
+	since, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	if err != nil {
+		return nil
+	}
+	// http dates have a one second precision
+	if !lastModified.Truncate(time.Second).After(since) {
+		return ErrNotModified
+	}
+	return nil
+}
+
+// IsHistoryUnavailable reports whether err was caused by the history
+// database being unreachable (e.g. refused or dropped connections).
+func IsHistoryUnavailable(err error) bool {
+	err = errors.Cause(err)
+	if stderrors.Is(err, driver.ErrBadConn) || stderrors.Is(err, sql.ErrConnDone) {
+		return true
+	}
+	var netErr net.Error
+	return stderrors.As(err, &netErr)
+}
+
+// historyUnavailableProblem replaces errors caused by the history database
+// being unreachable with hProblem.HistoryUnavailable, so clients get a 503
+// instead of a generic server error.
+func historyUnavailableProblem(err error) error {
+	if err != nil && IsHistoryUnavailable(err) {
+		return hProblem.HistoryUnavailable
+	}
+	return err
+}
+
 func init() {
 	decoder.IgnoreUnknownKeys(true)
 }
@@ -62,6 +62,130 @@ func TestGetAssetType(t *testing.T) {
 	}
 }
 
+func TestGetAsset(t *testing.T) {
+	tt := assert.New(t)
+	issuer := "GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H"
+
+	testCases := []struct {
+		desc       string
+		prefix     string
+		threeParam string
+		canonical  string
+		expected   xdr.Asset
+	}{
+		{
+			desc:       "native",
+			threeParam: "/?asset_type=native",
+			canonical:  "/?asset=native",
+			expected:   xdr.MustNewNativeAsset(),
+		},
+		{
+			desc:       "credit_alphanum4",
+			threeParam: "/?asset_type=credit_alphanum4&asset_code=USD&asset_issuer=" + issuer,
+			canonical:  "/?asset=USD:" + issuer,
+			expected:   xdr.MustNewCreditAsset("USD", issuer),
+		},
+		{
+			desc:       "credit_alphanum12",
+			prefix:     "selling_",
+			threeParam: "/?selling_asset_type=credit_alphanum12&selling_asset_code=SOMELONGCODE&selling_asset_issuer=" + issuer,
+			canonical:  "/?selling_asset=SOMELONGCODE:" + issuer,
+			expected:   xdr.MustNewCreditAsset("SOMELONGCODE", issuer),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			fromThreeParams, err := getAsset(makeTestActionRequest(tc.threeParam, nil), tc.prefix)
+			tt.NoError(err)
+			fromCanonical, err := getAsset(makeTestActionRequest(tc.canonical, nil), tc.prefix)
+			tt.NoError(err)
+
+			tt.Equal(tc.expected, fromThreeParams)
+			tt.Equal(tc.expected, fromCanonical)
+		})
+	}
+
+	_, err := getAsset(makeTestActionRequest("/?asset=USD", nil), "")
+	if tt.IsType(&problem.P{}, err) {
+		tt.Equal("asset", err.(*problem.P).Extras["invalid_field"])
+	}
+
+	_, err = getAsset(makeTestActionRequest("/?asset=USD:GNOTANISSUER", nil), "")
+	if tt.IsType(&problem.P{}, err) {
+		tt.Equal("asset", err.(*problem.P).Extras["invalid_field"])
+	}
+
+	_, err = getAsset(makeTestActionRequest("/?asset=native&asset_type=native", nil), "")
+	if tt.IsType(&problem.P{}, err) {
+		tt.Equal("asset_type", err.(*problem.P).Extras["invalid_field"])
+	}
+
+	muxed := "MAQAA5L65LSYH7CQ3VTJ7F3HHLGCL3DSLAR2Y47263D56MNNGHSQSAAAAAAAAAAE2LP26"
+	_, err = getAsset(makeTestActionRequest("/?asset_type=credit_alphanum4&asset_code=USD&asset_issuer="+muxed, nil), "")
+	if tt.IsType(&problem.P{}, err) {
+		p := err.(*problem.P)
+		tt.Equal(http.StatusBadRequest, p.Status)
+		tt.Equal("asset_issuer", p.Extras["invalid_field"])
+		tt.Equal(
+			"asset issuer can't be a muxed account, only G-addresses can issue assets",
+			p.Extras["reason"],
+		)
+	}
+}
+
+func TestGetAssetCodeTypeMismatch(t *testing.T) {
+	issuer := "GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H"
+
+	for _, tc := range []struct {
+		desc           string
+		prefix         string
+		query          string
+		expectedDetail string
+	}{
+		{
+			desc:           "4 characters code with credit_alphanum12",
+			query:          "/?asset_type=credit_alphanum12&asset_code=USDC&asset_issuer=" + issuer,
+			expectedDetail: "The asset code USDC has 4 characters, use `asset_type=credit_alphanum4` instead.",
+		},
+		{
+			desc:           "7 characters code with credit_alphanum4",
+			query:          "/?asset_type=credit_alphanum4&asset_code=USDCOIN&asset_issuer=" + issuer,
+			expectedDetail: "The asset code USDCOIN has 7 characters, use `asset_type=credit_alphanum12` instead.",
+		},
+		{
+			desc:           "12 characters code with credit_alphanum4",
+			prefix:         "selling_",
+			query:          "/?selling_asset_type=credit_alphanum4&selling_asset_code=SOMELONGCODE&selling_asset_issuer=" + issuer,
+			expectedDetail: "The asset code SOMELONGCODE has 12 characters, use `selling_asset_type=credit_alphanum12` instead.",
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, err := getAsset(makeTestActionRequest(tc.query, nil), tc.prefix)
+			if assert.IsType(t, &problem.P{}, err) {
+				p := err.(*problem.P)
+				assert.Equal(t, http.StatusBadRequest, p.Status)
+				assert.Equal(t, tc.prefix+"asset_type", p.Extras["invalid_field"])
+				assert.Equal(t, tc.expectedDetail, p.Detail)
+			}
+		})
+	}
+
+	_, err := getAsset(
+		makeTestActionRequest("/?asset_type=credit_alphanum4&asset_code=USDC&asset_issuer="+issuer, nil),
+		"",
+	)
+	assert.NoError(t, err)
+
+	_, err = getAsset(
+		makeTestActionRequest("/?asset_type=credit_alphanum12&asset_code=OHMYGODITSSOLONG&asset_issuer="+issuer, nil),
+		"",
+	)
+	if assert.IsType(t, &problem.P{}, err) {
+		assert.Equal(t, "asset_code", err.(*problem.P).Extras["invalid_field"])
+	}
+}
+
 func TestGetCursor(t *testing.T) {
 	tt := test.Start(t)
 	defer tt.Finish()
@@ -289,6 +413,33 @@ func TestGetPageQuery(t *testing.T) {
 	tt.Assert.Error(err)
 }
 
+func TestGetPageQueryOrder(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	ledgerState := &ledger.State{}
+
+	for order, expected := range map[string]string{
+		"":     "asc",
+		"asc":  "asc",
+		"ASC":  "asc",
+		"desc": "desc",
+		"Desc": "desc",
+	} {
+		pq, err := GetPageQuery(ledgerState, makeTestActionRequest("/?order="+order, nil))
+		tt.Assert.NoError(err)
+		tt.Assert.Equal(expected, pq.Order)
+	}
+
+	for _, order := range []string{"invalid", "descending"} {
+		_, err := GetPageQuery(ledgerState, makeTestActionRequest("/?order="+order, nil))
+		if tt.Assert.IsType(&problem.P{}, err) {
+			p := err.(*problem.P)
+			tt.Assert.Equal("bad_request", p.Type)
+			tt.Assert.Equal("order", p.Extras["invalid_field"])
+		}
+	}
+}
+
 func TestGetString(t *testing.T) {
 	tt := test.Start(t)
 	defer tt.Finish()
@@ -304,6 +455,19 @@ func TestGetString(t *testing.T) {
 	tt.Assert.Equal("goodbye", cursor)
 }
 
+func TestGetStringSlice(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	r := makeTestActionRequest("/foo-bar/blah?signer=a&signer=&signer=b", testURLParams())
+	values, err := getStringSlice(r, "signer")
+	tt.Assert.NoError(err)
+	tt.Assert.Equal([]string{"a", "b"}, values)
+
+	values, err = getStringSlice(r, "missing")
+	tt.Assert.NoError(err)
+	tt.Assert.Empty(values)
+}
+
 func TestPath(t *testing.T) {
 	tt := test.Start(t)
 	defer tt.Finish()
@@ -0,0 +1,119 @@
+package actions
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/stellar/go/support/render/problem"
+)
+
+// problemTranslations is the catalog of translations for the problems
+// returned by the account endpoints. It is keyed by language and then by the
+// English text, which is also used when a text has no translation. Keys ending
+// in %s match any text with the same prefix and keep the rest of the text
+// untranslated.
+var problemTranslations = map[string]map[string]string{
+	"es": {
+		"Bad Request": "Solicitud incorrecta",
+		"The request you sent was invalid in some way.": "La solicitud que enviaste no es válida.",
+		"Invalid Accounts Parameters":                   "Parámetros de cuentas no válidos",
+		"Exactly one filter is required. Please ensure that you are including a signer, an asset, or a sponsor filter.": "Se requiere exactamente un filtro. Asegúrate de incluir un filtro signer, asset o sponsor.",
+		customTagsErrorMessages["accountID"]:                         "El ID de cuenta debe empezar con `G` y contener 56 caracteres alfanuméricos",
+		customTagsErrorMessages["asset"]:                             "El activo debe ser la cadena \"native\" o una cadena de la forma \"Código:IDCuentaEmisora\" para activos emitidos.",
+		customTagsErrorMessages["bool"]:                              "El filtro debe ser true o false",
+		"you can't filter by asset: native":                          "no se puede filtrar por el activo native",
+		"only_matching_asset can only be used with the asset filter": "only_matching_asset solo puede usarse con el filtro asset",
+		"unknown signer type: %s":                                    "tipo de firmante desconocido: %s",
+	},
+}
+
+// localizeProblem translates the title, detail and reason of problems
+// returned by the account endpoints to the preferred language of the
+// Accept-Language header of r. English is used by default, and errors which
+// are not problems are returned unchanged.
+func localizeProblem(r *http.Request, err error) error {
+	var p problem.P
+	switch e := err.(type) {
+	case *problem.P:
+		p = *e
+	case problem.P:
+		p = e
+	default:
+		return err
+	}
+
+	catalog := problemCatalog(r.Header.Get("Accept-Language"))
+	if catalog == nil {
+		return err
+	}
+
+	p.Title = translateProblemText(catalog, p.Title)
+	p.Detail = translateProblemText(catalog, p.Detail)
+	if reason, ok := p.Extras["reason"].(string); ok {
+		extras := make(map[string]interface{}, len(p.Extras))
+		for k, v := range p.Extras {
+			extras[k] = v
+		}
+		extras["reason"] = translateProblemText(catalog, reason)
+		p.Extras = extras
+	}
+	return &p
+}
+
+// problemCatalog returns the translations for the language with the highest
+// quality value in acceptLanguage or nil when English should be used.
+func problemCatalog(acceptLanguage string) map[string]string {
+	type weightedLanguage struct {
+		language string
+		quality  float64
+	}
+
+	var languages []weightedLanguage
+	for _, entry := range strings.Split(acceptLanguage, ",") {
+		parts := strings.Split(strings.TrimSpace(entry), ";")
+		quality := 1.0
+		for _, param := range parts[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				q, err := strconv.ParseFloat(strings.TrimPrefix(param, "q="), 64)
+				if err != nil {
+					q = 0
+				}
+				quality = q
+			}
+		}
+		if quality <= 0 {
+			continue
+		}
+		// Only the primary subtag is used: es-AR and es-ES are both es.
+		language := strings.ToLower(strings.SplitN(parts[0], "-", 2)[0])
+		languages = append(languages, weightedLanguage{language, quality})
+	}
+	sort.SliceStable(languages, func(i, j int) bool {
+		return languages[i].quality > languages[j].quality
+	})
+
+	for _, l := range languages {
+		if l.language == "en" || l.language == "*" {
+			return nil
+		}
+		if catalog, ok := problemTranslations[l.language]; ok {
+			return catalog
+		}
+	}
+	return nil
+}
+
+func translateProblemText(catalog map[string]string, text string) string {
+	if translation, ok := catalog[text]; ok {
+		return translation
+	}
+	for key, translation := range catalog {
+		if prefix := strings.TrimSuffix(key, "%s"); prefix != key && strings.HasPrefix(text, prefix) {
+			return strings.Replace(translation, "%s", strings.TrimPrefix(text, prefix), 1)
+		}
+	}
+	return text
+}
@@ -0,0 +1,79 @@
+package actions
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stellar/go/support/render/problem"
+)
+
+func TestGetAccountsHandlerLocalizedProblems(t *testing.T) {
+	handler := GetAccountsHandler{}
+
+	request := makeRequest(t, map[string]string{"signer": "invalid"}, map[string]string{}, nil)
+	request.Header.Set("Accept-Language", "es-ES,es;q=0.9,en;q=0.8")
+	_, err := handler.GetResourcePage(httptest.NewRecorder(), request)
+	if assert.IsType(t, &problem.P{}, err) {
+		p := err.(*problem.P)
+		assert.Equal(t, "Solicitud incorrecta", p.Title)
+		assert.Equal(t, "La solicitud que enviaste no es válida.", p.Detail)
+		assert.Equal(t, "signer", p.Extras["invalid_field"])
+		assert.Equal(
+			t,
+			"El ID de cuenta debe empezar con `G` y contener 56 caracteres alfanuméricos",
+			p.Extras["reason"],
+		)
+	}
+
+	// English is used when no language is requested
+	request = makeRequest(t, map[string]string{"signer": "invalid"}, map[string]string{}, nil)
+	_, err = handler.GetResourcePage(httptest.NewRecorder(), request)
+	if assert.IsType(t, &problem.P{}, err) {
+		p := err.(*problem.P)
+		assert.Equal(t, "Bad Request", p.Title)
+		assert.Equal(t, customTagsErrorMessages["accountID"], p.Extras["reason"])
+	}
+
+	request = makeRequest(t, map[string]string{}, map[string]string{}, nil)
+	request.Header.Set("Accept-Language", "es")
+	_, err = handler.GetResourcePage(httptest.NewRecorder(), request)
+	if assert.IsType(t, &problem.P{}, err) {
+		p := err.(*problem.P)
+		assert.Equal(t, "invalid_accounts_params", p.Type)
+		assert.Equal(t, "Parámetros de cuentas no válidos", p.Title)
+	}
+	// the shared problem is left untouched
+	assert.Equal(t, "Invalid Accounts Parameters", invalidAccountsParams.Title)
+}
+
+func TestProblemCatalog(t *testing.T) {
+	for _, testCase := range []struct {
+		acceptLanguage string
+		spanish        bool
+	}{
+		{"", false},
+		{"es", true},
+		{"es-AR", true},
+		{"fr, es;q=0.5", true},
+		{"en;q=0.5, es;q=0.8", true},
+		{"es;q=0.5, en;q=0.8", false},
+		{"fr, *;q=0.5, es;q=0.1", false},
+		{"es;q=0", false},
+	} {
+		t.Run(testCase.acceptLanguage, func(t *testing.T) {
+			assert.Equal(t, testCase.spanish, problemCatalog(testCase.acceptLanguage) != nil)
+		})
+	}
+}
+
+func TestTranslateProblemText(t *testing.T) {
+	catalog := problemTranslations["es"]
+	assert.Equal(
+		t,
+		"tipo de firmante desconocido: ed25519",
+		translateProblemText(catalog, "unknown signer type: ed25519"),
+	)
+	assert.Equal(t, "not in the catalog", translateProblemText(catalog, "not in the catalog"))
+}
@@ -0,0 +1,101 @@
+package actions
+
+import (
+	"net/http"
+	"strings"
+
+	protocol "github.com/stellar/go/protocols/horizon"
+	horizonContext "github.com/stellar/go/services/horizon/internal/context"
+	"github.com/stellar/go/services/horizon/internal/db2/history"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/support/render/problem"
+	"github.com/stellar/go/xdr"
+)
+
+// maxTrustLineAuthStatesAccounts is the maximum number of accounts of a
+// request to the trust line authorization states endpoint.
+const maxTrustLineAuthStatesAccounts = 200
+
+// TrustLineAuthStatesQuery query struct for the
+// `/accounts/trustline_auth_states` end-point
+type TrustLineAuthStatesQuery struct {
+	AssetFilter string `schema:"asset" valid:"asset,required"`
+	// AccountsFilter is the comma separated list of the accounts.
+	AccountsFilter string `schema:"accounts" valid:"required"`
+}
+
+// Validate runs custom validations.
+func (q TrustLineAuthStatesQuery) Validate() error {
+	if q.AssetFilter == "native" {
+		return problem.MakeInvalidFieldProblem(
+			"asset",
+			errors.New("native balances are not trust lines"),
+		)
+	}
+
+	accounts := q.Accounts()
+	if len(accounts) > maxTrustLineAuthStatesAccounts {
+		return problem.MakeInvalidFieldProblem(
+			"accounts",
+			errors.Errorf("at most %d accounts are allowed", maxTrustLineAuthStatesAccounts),
+		)
+	}
+	if fieldErrors := validateAccountIDs(accounts); len(fieldErrors) > 0 {
+		return invalidAccountIDsProblem("accounts", fieldErrors)
+	}
+	return nil
+}
+
+// Asset returns an xdr.Asset representing the asset of the trust lines.
+func (q TrustLineAuthStatesQuery) Asset() xdr.Asset {
+	parts := strings.Split(q.AssetFilter, ":")
+	return xdr.MustNewCreditAsset(parts[0], parts[1])
+}
+
+// Accounts returns the list of accounts of the comma separated accounts
+// parameter.
+func (q TrustLineAuthStatesQuery) Accounts() []string {
+	return strings.Split(q.AccountsFilter, ",")
+}
+
+// GetTrustLineAuthStatesHandler is the action handler for the
+// `/accounts/trustline_auth_states` endpoint. It lets issuers check the
+// authorization of their asset across a list of accounts in one request.
+type GetTrustLineAuthStatesHandler struct{}
+
+// GetResource returns the authorization state of the trust line of every
+// account, in the order of the request. The accounts without a trust line to
+// the asset are reported as "none".
+func (handler GetTrustLineAuthStatesHandler) GetResource(w HeaderWriter, r *http.Request) (interface{}, error) {
+	qp := TrustLineAuthStatesQuery{}
+	if err := getParams(&qp, r); err != nil {
+		return nil, err
+	}
+
+	historyQ, err := horizonContext.HistoryQFromRequest(r)
+	if err != nil {
+		return nil, err
+	}
+
+	accounts := qp.Accounts()
+	states, err := historyQ.TrustlineAuthStates(r.Context(), qp.Asset(), accounts)
+	if err != nil {
+		return nil, errors.Wrap(err, "loading trust line authorization states")
+	}
+
+	response := protocol.TrustLineAuthStates{
+		Asset:  qp.AssetFilter,
+		States: make([]protocol.TrustLineAuthState, 0, len(accounts)),
+	}
+	for _, account := range accounts {
+		state, ok := states[account]
+		if !ok {
+			state = history.TrustLineNotAuthorized
+		}
+		response.States = append(response.States, protocol.TrustLineAuthState{
+			AccountID: account,
+			State:     string(state),
+		})
+	}
+	return response, nil
+}
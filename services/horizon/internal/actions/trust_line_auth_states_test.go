@@ -0,0 +1,88 @@
+package actions
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	protocol "github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/services/horizon/internal/db2/history"
+	"github.com/stellar/go/services/horizon/internal/test"
+	"github.com/stellar/go/support/render/problem"
+	"github.com/stellar/go/xdr"
+)
+
+func TestGetTrustLineAuthStatesHandler(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+
+	q := &history.Q{tt.HorizonSession()}
+	handler := GetTrustLineAuthStatesHandler{}
+
+	// usdTrustLine of accountTwo isn't authorized, accountOne holds an
+	// authorized USD trust line
+	authorizedTrustLine := usdTrustLine
+	authorizedEntry := *usdTrustLine.Data.TrustLine
+	authorizedEntry.AccountId = xdr.MustAddress(accountOne)
+	authorizedEntry.Flags = xdr.Uint32(xdr.TrustLineFlagsAuthorizedFlag)
+	authorizedTrustLine.Data.TrustLine = &authorizedEntry
+	for _, entry := range []xdr.LedgerEntry{usdTrustLine, authorizedTrustLine} {
+		_, err := q.InsertTrustLine(tt.Ctx, entry)
+		tt.Assert.NoError(err)
+	}
+
+	getAuthStates := func(params map[string]string) (interface{}, error) {
+		return handler.GetResource(
+			httptest.NewRecorder(),
+			makeRequest(t, params, map[string]string{}, q),
+		)
+	}
+
+	// signer doesn't hold a trust line to USD
+	response, err := getAuthStates(map[string]string{
+		"asset":    "USD:" + trustLineIssuer,
+		"accounts": strings.Join([]string{accountTwo, accountOne, signer}, ","),
+	})
+	tt.Assert.NoError(err)
+	tt.Assert.Equal(protocol.TrustLineAuthStates{
+		Asset: "USD:" + trustLineIssuer,
+		States: []protocol.TrustLineAuthState{
+			{AccountID: accountTwo, State: "none"},
+			{AccountID: accountOne, State: "authorized"},
+			{AccountID: signer, State: "none"},
+		},
+	}, response)
+
+	for _, testCase := range []struct {
+		params       map[string]string
+		invalidField string
+	}{
+		{map[string]string{"asset": "native", "accounts": accountOne}, "asset"},
+		{map[string]string{"asset": "USD:" + trustLineIssuer, "accounts": accountOne + ",GINVALID"}, "accounts"},
+		{
+			map[string]string{
+				"asset":    "USD:" + trustLineIssuer,
+				"accounts": strings.Repeat(accountOne+",", maxTrustLineAuthStatesAccounts) + accountOne,
+			},
+			"accounts",
+		},
+	} {
+		_, err = getAuthStates(testCase.params)
+		if tt.Assert.IsType(&problem.P{}, err) {
+			tt.Assert.Equal(testCase.invalidField, err.(*problem.P).Extras["invalid_field"])
+		}
+	}
+
+	// every invalid account is listed in a single problem
+	_, err = getAuthStates(map[string]string{
+		"asset":    "USD:" + trustLineIssuer,
+		"accounts": strings.Join([]string{"GINVALID", accountOne, "GALSOINVALID"}, ","),
+	})
+	if tt.Assert.IsType(&problem.P{}, err) {
+		fieldErrors := err.(*problem.P).Extras["invalid_values"].([]FieldError)
+		tt.Assert.Len(fieldErrors, 2)
+		tt.Assert.Equal("GINVALID", fieldErrors[0].Value)
+		tt.Assert.Equal("GALSOINVALID", fieldErrors[1].Value)
+	}
+}
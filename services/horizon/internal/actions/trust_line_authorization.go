@@ -0,0 +1,118 @@
+package actions
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	protocol "github.com/stellar/go/protocols/horizon"
+	horizonContext "github.com/stellar/go/services/horizon/internal/context"
+	"github.com/stellar/go/services/horizon/internal/db2/history"
+	"github.com/stellar/go/services/horizon/internal/ledger"
+	"github.com/stellar/go/services/horizon/internal/resourceadapter"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/support/render/hal"
+	"github.com/stellar/go/support/render/problem"
+	"github.com/stellar/go/xdr"
+)
+
+// TrustLineAuthorizationsQuery query struct for the
+// `/accounts/{account_id}/trustline_authorizations` end-point
+type TrustLineAuthorizationsQuery struct {
+	AccountID   string `schema:"account_id" valid:"accountID,required"`
+	AssetFilter string `schema:"asset" valid:"asset,required"`
+}
+
+// Validate runs custom validations.
+func (q TrustLineAuthorizationsQuery) Validate() error {
+	if q.AssetFilter == "native" {
+		return problem.MakeInvalidFieldProblem(
+			"asset",
+			errors.New("native balances are not trust lines"),
+		)
+	}
+	return nil
+}
+
+// Asset returns an xdr.Asset representing the asset of the trust line.
+func (q TrustLineAuthorizationsQuery) Asset() xdr.Asset {
+	parts := strings.Split(q.AssetFilter, ":")
+	return xdr.MustNewCreditAsset(parts[0], parts[1])
+}
+
+// GetTrustLineAuthorizationsHandler is the action handler for the
+// `/accounts/{account_id}/trustline_authorizations` endpoint. It lists the
+// ledgers in which the issuer authorized or deauthorized the trust line.
+type GetTrustLineAuthorizationsHandler struct {
+	LedgerState *ledger.State
+}
+
+// GetResourcePage returns a page of authorization changes of a trust line.
+func (handler GetTrustLineAuthorizationsHandler) GetResourcePage(
+	w HeaderWriter,
+	r *http.Request,
+) ([]hal.Pageable, error) {
+	ctx := r.Context()
+	pq, err := GetPageQuery(handler.LedgerState, r)
+	if err != nil {
+		return nil, err
+	}
+
+	qp := TrustLineAuthorizationsQuery{}
+	if err = getParams(&qp, r); err != nil {
+		return nil, err
+	}
+
+	historyQ, err := horizonContext.HistoryQFromRequest(r)
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := historyQ.TrustlineAuthHistory(ctx, qp.AccountID, qp.Asset(), pq)
+	if err != nil {
+		return nil, errors.Wrap(err, "loading trust line authorization history")
+	}
+
+	var authorizations []hal.Pageable
+	for _, record := range records {
+		var res protocol.TrustLineAuthorization
+		if err = resourceadapter.PopulateTrustLineAuthorization(ctx, &res, record); err != nil {
+			return nil, errors.Wrap(err, "could not populate trust line authorization")
+		}
+		authorizations = append(authorizations, res)
+	}
+
+	return authorizations, nil
+}
+
+// includeAuthorizedLedgers sets the last ledger in which each trust line of
+// the account was authorized on its balance.
+func includeAuthorizedLedgers(
+	ctx context.Context,
+	hq *history.Q,
+	accountID string,
+	balances []protocol.Balance,
+) error {
+	records, err := hq.LastAuthorizedLedgers(ctx, accountID)
+	if err != nil {
+		return errors.Wrap(err, "loading last authorized ledgers")
+	}
+
+	setAuthorizedLedgers(records, balances)
+	return nil
+}
+
+// setAuthorizedLedgers sets the ledger of the matching authorization record
+// on each balance. All the records must belong to the balances' account.
+func setAuthorizedLedgers(records []history.TrustLineAuthorization, balances []protocol.Balance) {
+	ledgers := map[string]uint32{}
+	for _, record := range records {
+		ledgers[record.AssetCode+":"+record.AssetIssuer] = record.LedgerSequence
+	}
+	for i := range balances {
+		if balances[i].Type == "native" {
+			continue
+		}
+		balances[i].AuthorizedLedger = ledgers[balances[i].Code+":"+balances[i].Issuer]
+	}
+}
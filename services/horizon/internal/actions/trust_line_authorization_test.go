@@ -0,0 +1,106 @@
+package actions
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	protocol "github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/services/horizon/internal/db2/history"
+	"github.com/stellar/go/services/horizon/internal/test"
+	"github.com/stellar/go/support/render/problem"
+	"github.com/stellar/go/xdr"
+)
+
+func TestGetTrustLineAuthorizationsHandler(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+
+	q := &history.Q{tt.HorizonSession()}
+	handler := GetTrustLineAuthorizationsHandler{}
+
+	usdTrustLineWithFlags := func(flags xdr.TrustLineFlags) xdr.LedgerEntry {
+		entry := usdTrustLine
+		trustLine := *usdTrustLine.Data.TrustLine
+		trustLine.Flags = xdr.Uint32(flags)
+		entry.Data.TrustLine = &trustLine
+		return entry
+	}
+
+	// created unauthorized, authorized, then reduced to maintain liabilities
+	for ledger, flags := range map[uint32]xdr.TrustLineFlags{
+		100: 0,
+		101: xdr.TrustLineFlagsAuthorizedFlag,
+		102: xdr.TrustLineFlagsAuthorizedToMaintainLiabilitiesFlag,
+	} {
+		tt.Assert.NoError(q.InsertTrustLineAuthorizations(
+			tt.Ctx,
+			ledger,
+			[]xdr.LedgerEntry{usdTrustLineWithFlags(flags)},
+		))
+	}
+
+	var assetType, code, issuer string
+	usd.MustExtract(&assetType, &code, &issuer)
+	routeParams := map[string]string{"account_id": accountTwo}
+
+	records, err := handler.GetResourcePage(
+		httptest.NewRecorder(),
+		makeRequest(t, map[string]string{"asset": code + ":" + issuer}, routeParams, q),
+	)
+	tt.Assert.NoError(err)
+	tt.Assert.Len(records, 3)
+
+	expected := []struct {
+		ledger                            uint32
+		isAuthorized                      bool
+		isAuthorizedToMaintainLiabilities bool
+	}{
+		{100, false, false},
+		{101, true, true},
+		{102, false, true},
+	}
+	for i, e := range expected {
+		record := records[i].(protocol.TrustLineAuthorization)
+		tt.Assert.Equal(e.ledger, record.Ledger)
+		tt.Assert.Equal(accountTwo, record.AccountID)
+		tt.Assert.Equal(code, record.Code)
+		tt.Assert.Equal(issuer, record.Issuer)
+		tt.Assert.Equal(e.isAuthorized, record.IsAuthorized)
+		tt.Assert.Equal(e.isAuthorizedToMaintainLiabilities, record.IsAuthorizedToMaintainLiabilities)
+	}
+
+	records, err = handler.GetResourcePage(
+		httptest.NewRecorder(),
+		makeRequest(
+			t,
+			map[string]string{
+				"asset":  code + ":" + issuer,
+				"order":  "desc",
+				"cursor": "102",
+				"limit":  "1",
+			},
+			routeParams,
+			q,
+		),
+	)
+	tt.Assert.NoError(err)
+	tt.Assert.Len(records, 1)
+	tt.Assert.Equal(uint32(101), records[0].(protocol.TrustLineAuthorization).Ledger)
+
+	euro.MustExtract(&assetType, &code, &issuer)
+	records, err = handler.GetResourcePage(
+		httptest.NewRecorder(),
+		makeRequest(t, map[string]string{"asset": code + ":" + issuer}, routeParams, q),
+	)
+	tt.Assert.NoError(err)
+	tt.Assert.Len(records, 0)
+
+	_, err = handler.GetResourcePage(
+		httptest.NewRecorder(),
+		makeRequest(t, map[string]string{"asset": "native"}, routeParams, q),
+	)
+	if tt.Assert.IsType(&problem.P{}, err) {
+		tt.Assert.Equal("asset", err.(*problem.P).Extras["invalid_field"])
+	}
+}
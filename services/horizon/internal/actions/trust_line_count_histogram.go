@@ -0,0 +1,106 @@
+package actions
+
+import (
+	"net/http"
+	"sync"
+
+	protocol "github.com/stellar/go/protocols/horizon"
+	horizonContext "github.com/stellar/go/services/horizon/internal/context"
+	"github.com/stellar/go/services/horizon/internal/ledger"
+	"github.com/stellar/go/support/errors"
+)
+
+// TrustLineCountHistogramCache holds the trust line count histogram of the
+// latest ingested ledger. The mutex only guards the cached values, the
+// histogram is computed without holding it.
+type TrustLineCountHistogramCache struct {
+	mutex     sync.Mutex
+	ledger    int32
+	histogram protocol.TrustLineCountHistogram
+	// refreshing is set while a request computes the histogram of a newer
+	// ledger, the other requests are served the previous one meanwhile.
+	refreshing bool
+}
+
+// lookup returns the cached histogram and whether it can be served for the
+// given ledger. When it can't, refresh is true for the single request which
+// must compute the histogram and store it with store.
+func (c *TrustLineCountHistogramCache) lookup(latest int32) (histogram protocol.TrustLineCountHistogram, ok, refresh bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.ledger == latest && latest > 0 {
+		return c.histogram, true, false
+	}
+	if c.refreshing {
+		return c.histogram, c.ledger > 0, false
+	}
+	c.refreshing = true
+	return protocol.TrustLineCountHistogram{}, false, true
+}
+
+// store ends a refresh started by lookup, caching the histogram unless it
+// couldn't be computed.
+func (c *TrustLineCountHistogramCache) store(latest int32, histogram protocol.TrustLineCountHistogram, err error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.refreshing = false
+	if err == nil && latest >= c.ledger {
+		c.ledger = latest
+		c.histogram = histogram
+	}
+}
+
+// GetTrustLineCountHistogramHandler is the action handler for the
+// /accounts/trustline_count_histogram endpoint
+type GetTrustLineCountHistogramHandler struct {
+	LedgerState *ledger.State
+	// Cache is optional, when set the histogram, which scans all the
+	// accounts, is computed at most once per ledger.
+	Cache *TrustLineCountHistogramCache
+}
+
+// GetResource returns the number of accounts by the number of trust lines
+// they hold.
+func (handler GetTrustLineCountHistogramHandler) GetResource(w HeaderWriter, r *http.Request) (interface{}, error) {
+	if handler.Cache == nil {
+		return loadTrustLineCountHistogram(r)
+	}
+
+	latest := handler.LedgerState.CurrentStatus().HistoryLatest
+	histogram, ok, refresh := handler.Cache.lookup(latest)
+	if ok {
+		return histogram, nil
+	}
+
+	histogram, err := loadTrustLineCountHistogram(r)
+	if refresh {
+		handler.Cache.store(latest, histogram, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return histogram, nil
+}
+
+func loadTrustLineCountHistogram(r *http.Request) (protocol.TrustLineCountHistogram, error) {
+	historyQ, err := horizonContext.HistoryQFromRequest(r)
+	if err != nil {
+		return protocol.TrustLineCountHistogram{}, err
+	}
+
+	buckets, err := historyQ.TrustlineCountHistogram(r.Context())
+	if err != nil {
+		return protocol.TrustLineCountHistogram{}, errors.Wrap(err, "loading trust line count histogram")
+	}
+
+	histogram := protocol.TrustLineCountHistogram{
+		Buckets: make([]protocol.TrustLineCountBucket, 0, len(buckets)),
+	}
+	for _, bucket := range buckets {
+		histogram.Buckets = append(histogram.Buckets, protocol.TrustLineCountBucket{
+			Bucket:   bucket.Bucket,
+			Accounts: bucket.Accounts,
+		})
+	}
+	return histogram, nil
+}
@@ -0,0 +1,90 @@
+package actions
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	protocol "github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/services/horizon/internal/db2/history"
+	"github.com/stellar/go/services/horizon/internal/ledger"
+	"github.com/stellar/go/services/horizon/internal/test"
+	"github.com/stellar/go/support/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetTrustLineCountHistogramHandlerCache(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+
+	q := &history.Q{tt.HorizonSession()}
+	ledgerState := &ledger.State{}
+	ledgerState.SetStatus(ledger.Status{HistoryLatest: 1234})
+	handler := GetTrustLineCountHistogramHandler{
+		LedgerState: ledgerState,
+		Cache:       &TrustLineCountHistogramCache{},
+	}
+
+	withoutTrustLines := func() int64 {
+		response, err := handler.GetResource(
+			httptest.NewRecorder(),
+			makeRequest(t, map[string]string{}, map[string]string{}, q),
+		)
+		tt.Assert.NoError(err)
+		return response.(protocol.TrustLineCountHistogram).Buckets[0].Accounts
+	}
+
+	tt.Assert.Equal(int64(0), withoutTrustLines())
+
+	batch := q.NewAccountsBatchInsertBuilder(0)
+	tt.Assert.NoError(batch.Add(tt.Ctx, account1))
+	tt.Assert.NoError(batch.Exec(tt.Ctx))
+
+	// the histogram is cached until the next ledger
+	tt.Assert.Equal(int64(0), withoutTrustLines())
+	ledgerState.SetStatus(ledger.Status{HistoryLatest: 1235})
+	tt.Assert.Equal(int64(1), withoutTrustLines())
+}
+
+func TestTrustLineCountHistogramCacheRefresh(t *testing.T) {
+	tt := assert.New(t)
+	cache := &TrustLineCountHistogramCache{}
+	histogram := func(accounts int64) protocol.TrustLineCountHistogram {
+		return protocol.TrustLineCountHistogram{
+			Buckets: []protocol.TrustLineCountBucket{{Bucket: "0", Accounts: accounts}},
+		}
+	}
+
+	// a single request refreshes the histogram, the others compute it
+	// without caching it while nothing is cached
+	_, ok, refresh := cache.lookup(1234)
+	tt.False(ok)
+	tt.True(refresh)
+	_, ok, refresh = cache.lookup(1234)
+	tt.False(ok)
+	tt.False(refresh)
+	cache.store(1234, histogram(1), nil)
+
+	cached, ok, _ := cache.lookup(1234)
+	tt.True(ok)
+	tt.Equal(histogram(1), cached)
+
+	// the previous histogram is served while a newer one is computed
+	_, ok, refresh = cache.lookup(1235)
+	tt.False(ok)
+	tt.True(refresh)
+	cached, ok, refresh = cache.lookup(1235)
+	tt.True(ok)
+	tt.False(refresh)
+	tt.Equal(histogram(1), cached)
+
+	// failures aren't cached and let the next request refresh
+	cache.store(1235, protocol.TrustLineCountHistogram{}, errors.New("timeout"))
+	_, ok, refresh = cache.lookup(1235)
+	tt.False(ok)
+	tt.True(refresh)
+	cache.store(1235, histogram(2), nil)
+	cached, ok, _ = cache.lookup(1235)
+	tt.True(ok)
+	tt.Equal(histogram(2), cached)
+}
@@ -0,0 +1,69 @@
+package actions
+
+import (
+	"database/sql"
+	"net/http"
+	"strings"
+
+	horizonContext "github.com/stellar/go/services/horizon/internal/context"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/support/render/problem"
+	"github.com/stellar/go/xdr"
+)
+
+// TrustLineExistsQuery query struct for the
+// `/accounts/{account_id}/trustlines/{asset}/exists` end-point
+type TrustLineExistsQuery struct {
+	AccountID   string `schema:"account_id" valid:"accountID,required"`
+	AssetFilter string `schema:"asset" valid:"asset,required"`
+}
+
+// Validate runs custom validations.
+func (q TrustLineExistsQuery) Validate() error {
+	if q.AssetFilter == "native" {
+		return problem.MakeInvalidFieldProblem(
+			"asset",
+			errors.New("native balances are not trust lines"),
+		)
+	}
+	return nil
+}
+
+// Asset returns an xdr.Asset representing the asset of the trust line.
+func (q TrustLineExistsQuery) Asset() xdr.Asset {
+	parts := strings.Split(q.AssetFilter, ":")
+	return xdr.MustNewCreditAsset(parts[0], parts[1])
+}
+
+type trustLineExistsResponse struct {
+	Exists bool `json:"exists"`
+}
+
+// GetTrustLineExistsHandler is the action handler for the
+// `/accounts/{account_id}/trustlines/{asset}/exists` endpoint. It lets
+// wallets check that the recipient of a payment trusts the asset without
+// loading the whole account.
+type GetTrustLineExistsHandler struct{}
+
+// GetResource responds with 200 when the account holds a trust line to the
+// asset and with 404 otherwise.
+func (handler GetTrustLineExistsHandler) GetResource(w HeaderWriter, r *http.Request) (interface{}, error) {
+	qp := TrustLineExistsQuery{}
+	if err := getParams(&qp, r); err != nil {
+		return nil, err
+	}
+
+	historyQ, err := horizonContext.HistoryQFromRequest(r)
+	if err != nil {
+		return nil, err
+	}
+
+	exists, err := historyQ.HasTrustline(r.Context(), qp.AccountID, qp.Asset())
+	if err != nil {
+		return nil, errors.Wrap(err, "checking trust line")
+	}
+	if !exists {
+		return nil, sql.ErrNoRows
+	}
+	return trustLineExistsResponse{Exists: true}, nil
+}
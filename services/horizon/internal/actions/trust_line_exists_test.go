@@ -0,0 +1,51 @@
+package actions
+
+import (
+	"database/sql"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stellar/go/services/horizon/internal/db2/history"
+	"github.com/stellar/go/services/horizon/internal/test"
+	"github.com/stellar/go/support/render/problem"
+	"github.com/stellar/go/xdr"
+)
+
+func TestGetTrustLineExistsHandler(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+
+	q := &history.Q{tt.HorizonSession()}
+	handler := GetTrustLineExistsHandler{}
+
+	for _, entry := range []xdr.LedgerEntry{eurTrustLine, usdTrustLine} {
+		_, err := q.InsertTrustLine(tt.Ctx, entry)
+		tt.Assert.NoError(err)
+	}
+
+	checkTrustLine := func(accountID, asset string) (interface{}, error) {
+		return handler.GetResource(
+			httptest.NewRecorder(),
+			makeRequest(
+				t,
+				map[string]string{},
+				map[string]string{"account_id": accountID, "asset": asset},
+				q,
+			),
+		)
+	}
+
+	response, err := checkTrustLine(accountTwo, "USD:"+trustLineIssuer)
+	tt.Assert.NoError(err)
+	tt.Assert.Equal(trustLineExistsResponse{Exists: true}, response)
+
+	// the EUR trust line belongs to account1
+	_, err = checkTrustLine(accountTwo, "EUR:"+trustLineIssuer)
+	tt.Assert.Equal(sql.ErrNoRows, err)
+
+	_, err = checkTrustLine(accountTwo, "native")
+	if tt.Assert.IsType(&problem.P{}, err) {
+		tt.Assert.Equal("asset", err.(*problem.P).Extras["invalid_field"])
+	}
+}
@@ -1,15 +1,21 @@
 package actions
 
 import (
+	"encoding/base32"
 	"encoding/hex"
+	"fmt"
+	"runtime"
 	"strings"
+	"sync"
 
 	"github.com/asaskevich/govalidator"
 	"github.com/gorilla/schema"
 
 	"github.com/stellar/go/amount"
 	"github.com/stellar/go/services/horizon/internal/assets"
+	"github.com/stellar/go/strkey"
 	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/support/render/problem"
 	"github.com/stellar/go/xdr"
 )
 
@@ -60,10 +66,10 @@ func isAsset(assetString string) bool {
 			return false
 		}
 
-		issuer, err := xdr.AddressToAccountId(parts[1])
-		if err != nil {
+		if validateAccountStrkey(parts[1]) != nil {
 			return false
 		}
+		issuer := xdr.MustAddress(parts[1])
 
 		if err := asset.SetCredit(code, issuer); err != nil {
 			return false
@@ -122,11 +128,87 @@ func isAssetType(str string) bool {
 }
 
 func isAccountID(str string) bool {
-	if _, err := xdr.AddressToAccountId(str); err != nil {
-		return false
+	return validateAccountStrkey(str) == nil
+}
+
+// accountStrkeyLength is the length of a strkey encoded account ID: a
+// version byte, a 32 bytes ed25519 public key and a 2 bytes checksum.
+const accountStrkeyLength = 56
+
+// validateAccountStrkey returns an error describing why s is not a valid
+// strkey encoded account ID (G...), or nil if it is.
+func validateAccountStrkey(s string) error {
+	if len(s) != accountStrkeyLength {
+		return fmt.Errorf(
+			"account ID must be %d characters long, got %d",
+			accountStrkeyLength,
+			len(s),
+		)
 	}
 
-	return true
+	raw, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(s)
+	if err != nil {
+		return errors.New("account ID must be base32 encoded")
+	}
+	if strkey.VersionByte(raw[0]) != strkey.VersionByteAccountID {
+		return errors.New("account ID must start with G, wrong version byte")
+	}
+
+	if _, err := strkey.Decode(strkey.VersionByteAccountID, s); err != nil {
+		return errors.New("account ID checksum is invalid")
+	}
+
+	return nil
+}
+
+// FieldError describes why one of the values of a list parameter is invalid.
+type FieldError struct {
+	Value  string `json:"value"`
+	Reason string `json:"reason"`
+}
+
+// validateAccountIDs validates the given account IDs in parallel and returns
+// an error for each invalid one, in the order of ids.
+func validateAccountIDs(ids []string) []FieldError {
+	reasons := make([]error, len(ids))
+	workers := runtime.NumCPU()
+	if workers > len(ids) {
+		workers = len(ids)
+	}
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(first int) {
+			defer wg.Done()
+			for i := first; i < len(ids); i += workers {
+				reasons[i] = validateAccountStrkey(ids[i])
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	var fieldErrors []FieldError
+	for i, reason := range reasons {
+		if reason != nil {
+			fieldErrors = append(fieldErrors, FieldError{
+				Value:  ids[i],
+				Reason: reason.Error(),
+			})
+		}
+	}
+	return fieldErrors
+}
+
+// invalidAccountIDsProblem returns a single bad request problem listing all
+// the invalid account IDs of field.
+func invalidAccountIDsProblem(field string, fieldErrors []FieldError) *problem.P {
+	p := problem.MakeInvalidFieldProblem(
+		field,
+		fmt.Errorf("%d invalid account IDs", len(fieldErrors)),
+	)
+	p.Extras["invalid_values"] = fieldErrors
+	return p
 }
 
 func isTransactionHash(str string) bool {
@@ -6,6 +6,9 @@ import (
 
 	"github.com/asaskevich/govalidator"
 	"github.com/stretchr/testify/assert"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/support/render/problem"
 )
 
 func TestAssetTypeValidator(t *testing.T) {
@@ -100,6 +103,54 @@ func TestAccountIDValidator(t *testing.T) {
 	}
 }
 
+func TestValidateAccountStrkey(t *testing.T) {
+	for _, testCase := range []struct {
+		name          string
+		value         string
+		expectedError string
+	}{
+		{
+			"valid account ID",
+			"GAN4WOTCFSASG3J6SGLLQZURDDUVNBQANAHEQJ3PBNDZ74X63UZWQPZW",
+			"",
+		},
+		{
+			"wrong length",
+			"GAN4WOTCFSASG3J6SGLLQZURDDUVNBQANAHEQJ3PBNDZ74X63UZWQPZ",
+			"account ID must be 56 characters long, got 55",
+		},
+		{
+			"empty",
+			"",
+			"account ID must be 56 characters long, got 0",
+		},
+		{
+			"not base32",
+			"gan4wotcfsasg3j6sgllqzurdduvnbqanaheqj3pbndz74x63uzwqpzw",
+			"account ID must be base32 encoded",
+		},
+		{
+			"wrong version byte",
+			"TAN4WOTCFSASG3J6SGLLQZURDDUVNBQANAHEQJ3PBNDZ74X63UZWRSLH",
+			"account ID must start with G, wrong version byte",
+		},
+		{
+			"bad checksum",
+			"GAN4WOTCFSASG3J6SGLLQZURDDUVNBQANAHEQJ3PBNDZ74X63UZWQPZX",
+			"account ID checksum is invalid",
+		},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := validateAccountStrkey(testCase.value)
+			if testCase.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, testCase.expectedError)
+			}
+		})
+	}
+}
+
 func TestAssetValidator(t *testing.T) {
 	type Query struct {
 		Asset string `valid:"asset"`
@@ -277,3 +328,39 @@ func TestTransactionHashValidator(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateAccountIDs(t *testing.T) {
+	assert.Empty(t, validateAccountIDs(nil))
+
+	var ids []string
+	for i := 0; i < 200; i++ {
+		ids = append(ids, keypair.MustRandom().Address())
+	}
+	assert.Empty(t, validateAccountIDs(ids))
+
+	ids[3] = "GAN4WOTCFSASG3J6SGLLQZURDDUVNBQANAHEQJ3PBNDZ74X63UZWQPZ"
+	ids[42] = "TAN4WOTCFSASG3J6SGLLQZURDDUVNBQANAHEQJ3PBNDZ74X63UZWRSLH"
+	ids[199] = "GAN4WOTCFSASG3J6SGLLQZURDDUVNBQANAHEQJ3PBNDZ74X63UZWQPZX"
+	expected := []FieldError{
+		{
+			Value:  ids[3],
+			Reason: "account ID must be 56 characters long, got 55",
+		},
+		{
+			Value:  ids[42],
+			Reason: "account ID must start with G, wrong version byte",
+		},
+		{
+			Value:  ids[199],
+			Reason: "account ID checksum is invalid",
+		},
+	}
+	fieldErrors := validateAccountIDs(ids)
+	assert.Equal(t, expected, fieldErrors)
+
+	p := invalidAccountIDsProblem("account_ids", fieldErrors)
+	assert.Equal(t, problem.BadRequest.Status, p.Status)
+	assert.Equal(t, "account_ids", p.Extras["invalid_field"])
+	assert.Equal(t, "3 invalid account IDs", p.Extras["reason"])
+	assert.Equal(t, expected, p.Extras["invalid_values"])
+}
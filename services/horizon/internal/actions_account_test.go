@@ -1,18 +1,19 @@
 package horizon
 
 import (
+	"net/http"
+	"net/url"
 	"testing"
 
 	"github.com/stellar/go/services/horizon/internal/db2/history"
 	"github.com/stellar/go/services/horizon/internal/ingest"
+	"github.com/stellar/go/support/render/msgpack"
 	"github.com/stellar/go/xdr"
 )
 
-func TestAccountActions_InvalidID(t *testing.T) {
-	ht := StartHTTPTestWithoutScenario(t)
-	defer ht.Finish()
-
-	// Makes StateMiddleware happy
+// setUpStateMiddleware ingests ledger 100, which makes StateMiddleware
+// serve the requests.
+func setUpStateMiddleware(ht *HTTPT) history.Q {
 	q := history.Q{ht.HorizonSession()}
 	err := q.UpdateLastLedgerIngest(ht.Ctx, 100)
 	ht.Assert.NoError(err)
@@ -24,6 +25,14 @@ func TestAccountActions_InvalidID(t *testing.T) {
 		},
 	}, 0, 0, 0, 0, 0)
 	ht.Assert.NoError(err)
+	return q
+}
+
+func TestAccountActions_InvalidID(t *testing.T) {
+	ht := StartHTTPTestWithoutScenario(t)
+	defer ht.Finish()
+
+	setUpStateMiddleware(ht)
 
 	// existing account
 	w := ht.Get(
@@ -31,3 +40,191 @@ func TestAccountActions_InvalidID(t *testing.T) {
 	)
 	ht.Assert.Equal(400, w.Code)
 }
+
+func TestAccountActions_PageLinks(t *testing.T) {
+	ht := StartHTTPTestWithoutScenario(t)
+	defer ht.Finish()
+
+	q := setUpStateMiddleware(ht)
+
+	// sorted by account id, all signed by signer
+	signer := "GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H"
+	accountIDs := []string{
+		"GA5WBPYA5Y4WAEHXWR2UKO2UO4BUGHUQ74EUPKON2QHV4WRHOIRNKKH2",
+		"GABGMPEKKDWR2WFH5AJOZV5PDKLJEHGCR3Q24ALETWR5H3A7GI3YTS7V",
+		"GCXKG6RN4ONIEPCMNFB732A436Z5PNDSRLGWK7GBLCMQLIFO4S7EYWVU",
+	}
+	batch := q.NewAccountsBatchInsertBuilder(0)
+	for _, accountID := range accountIDs {
+		ht.Assert.NoError(batch.Add(ht.Ctx, xdr.LedgerEntry{
+			LastModifiedLedgerSeq: 100,
+			Data: xdr.LedgerEntryData{
+				Type: xdr.LedgerEntryTypeAccount,
+				Account: &xdr.AccountEntry{
+					AccountId:  xdr.MustAddress(accountID),
+					Balance:    10000,
+					Thresholds: xdr.Thresholds{1, 0, 0, 0},
+				},
+			},
+		}))
+		_, err := q.CreateAccountSigner(ht.Ctx, accountID, signer, 1, nil)
+		ht.Assert.NoError(err)
+	}
+	ht.Assert.NoError(batch.Exec(ht.Ctx))
+
+	linkQuery := func(link string) url.Values {
+		parsed, err := url.Parse(link)
+		ht.Require.NoError(err)
+		return parsed.Query()
+	}
+
+	// the middle page
+	w := ht.Get("/accounts?signer=" + signer + "&limit=1&cursor=" + accountIDs[0])
+	if ht.Assert.Equal(200, w.Code) {
+		var records []map[string]interface{}
+		links := ht.UnmarshalPage(w.Body, &records)
+		if ht.Assert.Len(records, 1) {
+			ht.Assert.Equal(accountIDs[1], records[0]["account_id"])
+		}
+
+		self := linkQuery(links.Self.Href)
+		ht.Assert.Equal(signer, self.Get("signer"))
+		ht.Assert.Equal(accountIDs[0], self.Get("cursor"))
+		ht.Assert.Equal("1", self.Get("limit"))
+		ht.Assert.Equal("asc", self.Get("order"))
+
+		next := linkQuery(links.Next.Href)
+		ht.Assert.Equal(signer, next.Get("signer"))
+		ht.Assert.Equal(accountIDs[1], next.Get("cursor"))
+		ht.Assert.Equal("asc", next.Get("order"))
+
+		prev := linkQuery(links.Prev.Href)
+		ht.Assert.Equal(signer, prev.Get("signer"))
+		ht.Assert.Equal(accountIDs[1], prev.Get("cursor"))
+		ht.Assert.Equal("desc", prev.Get("order"))
+	}
+
+	// cursor=now continues from the latest account id instead of a ledger
+	w = ht.Get("/accounts?signer=" + signer + "&cursor=now")
+	if ht.Assert.Equal(200, w.Code) {
+		var records []map[string]interface{}
+		links := ht.UnmarshalPage(w.Body, &records)
+		ht.Assert.Len(records, 0)
+		ht.Assert.Equal(accountIDs[2], linkQuery(links.Self.Href).Get("cursor"))
+		ht.Assert.Equal(accountIDs[2], linkQuery(links.Next.Href).Get("cursor"))
+
+		w = ht.Get(links.Next.Href)
+		ht.Assert.Equal(200, w.Code)
+	}
+}
+
+func TestAccountActions_Msgpack(t *testing.T) {
+	ht := StartHTTPTestWithoutScenario(t)
+	defer ht.Finish()
+	q := setUpStateMiddleware(ht)
+
+	accountID := "GADTXHUTHIAESMMQ2ZWSTIIGBZRLHUCBLCHPLLUEIAWDEFRDC4SYDKOZ"
+	batch := q.NewAccountsBatchInsertBuilder(0)
+	ht.Assert.NoError(batch.Add(ht.Ctx, xdr.LedgerEntry{
+		LastModifiedLedgerSeq: 100,
+		Data: xdr.LedgerEntryData{
+			Type: xdr.LedgerEntryTypeAccount,
+			Account: &xdr.AccountEntry{
+				AccountId:     xdr.MustAddress(accountID),
+				Balance:       50000,
+				SeqNum:        648736,
+				NumSubEntries: 10,
+				Flags:         2,
+				HomeDomain:    "meridian.stellar.org",
+				Thresholds:    xdr.Thresholds{5, 6, 7, 8},
+			},
+		},
+	}))
+	ht.Assert.NoError(batch.Exec(ht.Ctx))
+
+	w := ht.Get("/accounts/"+accountID, func(r *http.Request) {
+		r.Header.Set("Accept", "application/msgpack")
+	})
+	if ht.Assert.Equal(200, w.Code) {
+		ht.Assert.Equal("application/msgpack", w.Header().Get("Content-Type"))
+		decoded, err := msgpack.Unmarshal(w.Body.Bytes())
+		ht.Require.NoError(err)
+		account := decoded.(map[string]interface{})
+		ht.Assert.Equal(accountID, account["account_id"])
+		ht.Assert.Equal("648736", account["sequence"])
+		ht.Assert.Equal(int64(10), account["subentry_count"])
+		ht.Assert.Equal("meridian.stellar.org", account["home_domain"])
+		ht.Assert.Equal(int64(5), account["master_key_weight"])
+		ht.Assert.Equal(
+			map[string]interface{}{"low_threshold": int64(6), "med_threshold": int64(7), "high_threshold": int64(8)},
+			account["thresholds"],
+		)
+		balances := account["balances"].([]interface{})
+		ht.Assert.Equal("0.0050000", balances[0].(map[string]interface{})["balance"])
+	}
+
+	// JSON stays the default
+	w = ht.Get("/accounts/" + accountID)
+	if ht.Assert.Equal(200, w.Code) {
+		ht.Assert.Contains(w.Header().Get("Content-Type"), "application/hal+json")
+	}
+
+	// other resources are not available in MessagePack
+	w = ht.Get("/ledgers/100", func(r *http.Request) {
+		r.Header.Set("Accept", "application/msgpack")
+	})
+	ht.Assert.Equal(406, w.Code)
+}
+
+func TestAccountActions_Head(t *testing.T) {
+	ht := StartHTTPTestWithoutScenario(t)
+	defer ht.Finish()
+	q := setUpStateMiddleware(ht)
+
+	accountID := "GADTXHUTHIAESMMQ2ZWSTIIGBZRLHUCBLCHPLLUEIAWDEFRDC4SYDKOZ"
+	batch := q.NewAccountsBatchInsertBuilder(0)
+	ht.Assert.NoError(batch.Add(ht.Ctx, xdr.LedgerEntry{
+		LastModifiedLedgerSeq: 100,
+		Data: xdr.LedgerEntryData{
+			Type: xdr.LedgerEntryTypeAccount,
+			Account: &xdr.AccountEntry{
+				AccountId:  xdr.MustAddress(accountID),
+				Balance:    50000,
+				SeqNum:     648736,
+				Thresholds: xdr.Thresholds{1, 1, 1, 1},
+			},
+		},
+	}))
+	ht.Assert.NoError(batch.Exec(ht.Ctx))
+	_, err := q.CreateAccountSigner(ht.Ctx, accountID, accountID, 1, nil)
+	ht.Assert.NoError(err)
+
+	head := func(r *http.Request) {
+		r.Method = http.MethodHead
+	}
+
+	get := ht.Get("/accounts/" + accountID)
+	ht.Assert.Equal(200, get.Code)
+	w := ht.Get("/accounts/"+accountID, head)
+	if ht.Assert.Equal(200, w.Code) {
+		ht.Assert.Equal("100", w.Header().Get("Latest-Ledger"))
+		ht.Assert.Equal(get.Header().Get("Last-Modified"), w.Header().Get("Last-Modified"))
+		ht.Assert.Equal(get.Header().Get("Content-Type"), w.Header().Get("Content-Type"))
+		ht.Assert.Empty(w.Body.Bytes())
+	}
+
+	w = ht.Get("/accounts/"+accountID, head, func(r *http.Request) {
+		r.Header.Set("If-Modified-Since", get.Header().Get("Last-Modified"))
+	})
+	ht.Assert.Equal(304, w.Code)
+
+	w = ht.Get("/accounts/GABGMPEKKDWR2WFH5AJOZV5PDKLJEHGCR3Q24ALETWR5H3A7GI3YTS7V", head)
+	ht.Assert.Equal(404, w.Code)
+	ht.Assert.Empty(w.Body.Bytes())
+
+	w = ht.Get("/accounts?signer="+accountID, head)
+	if ht.Assert.Equal(200, w.Code) {
+		ht.Assert.Equal("100", w.Header().Get("Latest-Ledger"))
+		ht.Assert.Empty(w.Body.Bytes())
+	}
+}
@@ -90,6 +90,32 @@ func TestDataActions_Show(t *testing.T) {
 	w = ht.Get(prefix+"/data/name1", test.RequestHelperRaw)
 	if ht.Assert.Equal(200, w.Code) {
 		ht.Assert.Equal([]byte(data1.Data.Data.DataValue), w.Body.Bytes())
+		ht.Assert.Equal("application/octet-stream", w.Header().Get("Content-Type"))
+	}
+
+	// decoded values
+	for decode, expected := range map[string]string{
+		"base64": "AAECAwQFBgcICQ==",
+		"hex":    "00010203040506070809",
+	} {
+		result = map[string]string{}
+		w = ht.Get(prefix + "/data/name1?decode=" + decode)
+		if ht.Assert.Equal(200, w.Code) {
+			ht.Assert.NoError(json.Unmarshal(w.Body.Bytes(), &result))
+			ht.Assert.Equal(expected, result["value"])
+		}
+	}
+
+	result = map[string]string{}
+	w = ht.Get(prefix + "/data/name%20?decode=utf8")
+	if ht.Assert.Equal(200, w.Code) {
+		ht.Assert.NoError(json.Unmarshal(w.Body.Bytes(), &result))
+		ht.Assert.Equal("it got spaces!", result["value"])
+	}
+
+	w = ht.Get(prefix + "/data/name1?decode=protobuf")
+	if ht.Assert.Equal(400, w.Code) {
+		ht.Assert.Contains(w.Body.String(), "Accepted values: utf8; hex or base64")
 	}
 
 	result = map[string]string{}
@@ -109,6 +135,8 @@ func TestDataActions_Show(t *testing.T) {
 	w = ht.Get(prefix+"/data/name%20", test.RequestHelperRaw)
 	if ht.Assert.Equal(200, w.Code) {
 		ht.Assert.Equal("it got spaces!", w.Body.String())
+		// text values are not sniffed as text/plain
+		ht.Assert.Equal("application/octet-stream", w.Header().Get("Content-Type"))
 	}
 
 	// missing
@@ -117,6 +145,7 @@ func TestDataActions_Show(t *testing.T) {
 
 	w = ht.Get(prefix+"/data/missing", test.RequestHelperRaw)
 	ht.Assert.Equal(404, w.Code)
+	ht.Assert.Equal("application/problem+json; charset=utf-8", w.Header().Get("Content-Type"))
 
 	// Too long
 	w = ht.Get(prefix+"/data/01234567890123456789012345678901234567890123456789012345678901234567890123456789", test.RequestHelperRaw)
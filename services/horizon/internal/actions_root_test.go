@@ -46,7 +46,7 @@ func TestRootAction(t *testing.T) {
 		err = json.Unmarshal(w.Body.Bytes(), &actual)
 		ht.Require.NoError(err)
 		ht.Assert.Equal(
-			"http://localhost/accounts{?signer,sponsor,asset,cursor,limit,order}",
+			"http://localhost/accounts{?signer,sponsor,asset,assets,match,weak_thresholds,has_liabilities,no_home_domain,home_domain,signer_type,threshold,threshold_op,threshold_value,data_name,data_value,modified_from,modified_to,only_matching_asset,zero_balance,top,allow_partial,omit_empty,sort,summary,strict_params,cursor,limit,order}",
 			actual.Links.Accounts.Href,
 		)
 		ht.Assert.Equal(
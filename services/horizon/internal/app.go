@@ -464,6 +464,8 @@ func (a *App) init() error {
 		BehindAWSLoadBalancer: a.config.BehindAWSLoadBalancer,
 		SSEUpdateFrequency:    a.config.SSEUpdateFrequency,
 		StaleThreshold:        a.config.StaleThreshold,
+		ReplicaLagTolerance:   a.config.ReplicaLagTolerance,
+		StrictAccountsParams:  a.config.StrictAccountsParams,
 		ConnectionTimeout:     a.config.ConnectionTimeout,
 		NetworkPassphrase:     a.config.NetworkPassphrase,
 		MaxPathLength:         a.config.MaxPathLength,
@@ -481,6 +483,7 @@ func (a *App) init() error {
 			},
 			cache: newHealthCache(healthCacheTTL),
 		},
+		AccountsFilterRateQuotas: a.config.AccountsFilterRateQuotas,
 	}
 
 	if a.primaryHistoryQ != nil {
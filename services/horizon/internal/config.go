@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/stellar/go/ingest/ledgerbackend"
+	"github.com/stellar/go/services/horizon/internal/actions"
 
 	"github.com/sirupsen/logrus"
 	"github.com/stellar/throttled"
@@ -68,6 +69,13 @@ type Config struct {
 	// out-of-date by before horizon begins to respond with an error to history
 	// requests.
 	StaleThreshold uint
+	// ReplicaLagTolerance represents the number of ledgers the read-replica
+	// may lag behind the primary database before horizon begins to respond
+	// with a stale history error.
+	ReplicaLagTolerance uint
+	// StrictAccountsParams causes the accounts endpoint to reject requests with
+	// unrecognized query parameters instead of ignoring them.
+	StrictAccountsParams bool
 	// SkipCursorUpdate causes the ingestor to skip reporting the "last imported
 	// ledger" state to stellar-core.
 	SkipCursorUpdate bool
@@ -86,4 +94,8 @@ type Config struct {
 	// balances like ELB or ALB. In such case http.Request.RemoteAddr will be
 	// replaced with the last IP in X-Forwarded-For header.
 	BehindAWSLoadBalancer bool
+	// AccountsFilterRateQuotas are the quotas of the requests to /accounts
+	// using each filter, by remote ip address. Filters without a quota
+	// aren't throttled.
+	AccountsFilterRateQuotas map[actions.AccountsFilterType]throttled.RateQuota
 }
@@ -3,8 +3,12 @@ package history
 import (
 	"context"
 	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
 
 	sq "github.com/Masterminds/squirrel"
+	"github.com/stellar/go/services/horizon/internal/db2"
 	"github.com/stellar/go/support/errors"
 	"github.com/stellar/go/xdr"
 )
@@ -159,6 +163,68 @@ func (q *Q) GetAccountDataByAccountsID(ctx context.Context, id []string) ([]Data
 	return data, err
 }
 
+// CountDataForAccounts returns the number of data entries of each of the
+// given accounts, without loading them.
+func (q *Q) CountDataForAccounts(ctx context.Context, accounts []string) (map[string]int, error) {
+	return q.countRowsByAccount(ctx, "accounts_data", accounts)
+}
+
+// DataSizeCursor returns the paging token of a data entry in the pages of
+// DataByAddressOrderedBySize.
+func DataSizeCursor(data Data) string {
+	return fmt.Sprintf("%d-%s", len(data.Value), data.Name)
+}
+
+// ParseDataSizeCursor returns the value size and the name of a cursor built
+// by DataSizeCursor.
+func ParseDataSizeCursor(cursor string) (int, string, error) {
+	parts := strings.SplitN(cursor, "-", 2)
+	if len(parts) != 2 {
+		return 0, "", errors.New("invalid cursor")
+	}
+
+	size, err := strconv.Atoi(parts[0])
+	if err != nil || size < 0 {
+		return 0, "", errors.New("invalid cursor - first value should be a size")
+	}
+
+	return size, parts[1], nil
+}
+
+// DataByAddressOrderedBySize returns the data entries of an account sorted
+// by the length in bytes of their values, using the name to break ties.
+func (q *Q) DataByAddressOrderedBySize(ctx context.Context, addr string, page db2.PageQuery) ([]Data, error) {
+	// values are stored base64 encoded
+	size := "length(decode(value, 'base64'))"
+	sql := selectAccountData.Where(sq.Eq{"account_id": addr}).Limit(page.Limit)
+
+	var op string
+	switch page.Order {
+	case db2.OrderAscending:
+		op = ">"
+	case db2.OrderDescending:
+		op = "<"
+	default:
+		return nil, errors.Errorf("invalid order: %s", page.Order)
+	}
+
+	if page.Cursor != "" {
+		cursorSize, name, err := ParseDataSizeCursor(page.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		sql = sql.Where(sq.Expr("("+size+", name) "+op+" (?, ?)", cursorSize, name))
+	}
+	sql = sql.OrderBy(size+" "+page.Order, "name "+page.Order)
+
+	var data []Data
+	if err := q.Select(ctx, &data, sql); err != nil {
+		return nil, errors.Wrap(err, "could not run select query")
+	}
+
+	return data, nil
+}
+
 var selectAccountData = sq.Select(`
 	account_id,
 	name,
@@ -3,6 +3,7 @@ package history
 import (
 	"testing"
 
+	"github.com/stellar/go/services/horizon/internal/db2"
 	"github.com/stellar/go/services/horizon/internal/test"
 	"github.com/stellar/go/xdr"
 	"github.com/stretchr/testify/assert"
@@ -197,3 +198,55 @@ func TestGetAccountDataByName(t *testing.T) {
 	tt.Assert.Equal([]byte(data2.Data.Data.DataValue), []byte(record.Value))
 
 }
+
+func TestDataByAddressOrderedBySize(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+	q := &Q{tt.HorizonSession()}
+
+	// data1 and data2 hold 10 bytes, largeData holds 64 bytes
+	largeData := data1
+	entry := *data1.Data.Data
+	entry.DataName = "large data"
+	entry.DataValue = make([]byte, 64)
+	largeData.Data.Data = &entry
+
+	for _, data := range []xdr.LedgerEntry{data1, data2, largeData} {
+		_, err := q.InsertAccountData(tt.Ctx, data)
+		tt.Assert.NoError(err)
+	}
+
+	names := func(records []Data) []string {
+		var result []string
+		for _, record := range records {
+			result = append(result, record.Name)
+		}
+		return result
+	}
+	address := data1.Data.Data.AccountId.Address()
+
+	page := db2.PageQuery{Order: db2.OrderDescending, Limit: 2}
+	records, err := q.DataByAddressOrderedBySize(tt.Ctx, address, page)
+	tt.Assert.NoError(err)
+	tt.Assert.Equal([]string{"large data", "test data2"}, names(records))
+	tt.Assert.Len(records[0].Value, 64)
+
+	page.Cursor = DataSizeCursor(records[1])
+	tt.Assert.Equal("10-test data2", page.Cursor)
+	records, err = q.DataByAddressOrderedBySize(tt.Ctx, address, page)
+	tt.Assert.NoError(err)
+	tt.Assert.Equal([]string{"test data"}, names(records))
+
+	page = db2.PageQuery{Order: db2.OrderAscending, Limit: 10}
+	records, err = q.DataByAddressOrderedBySize(tt.Ctx, address, page)
+	tt.Assert.NoError(err)
+	tt.Assert.Equal([]string{"test data", "test data2", "large data"}, names(records))
+
+	_, err = q.DataByAddressOrderedBySize(
+		tt.Ctx,
+		address,
+		db2.PageQuery{Order: db2.OrderAscending, Limit: 10, Cursor: "large-test data"},
+	)
+	tt.Assert.EqualError(err, "invalid cursor - first value should be a size")
+}
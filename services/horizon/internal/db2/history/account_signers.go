@@ -4,6 +4,7 @@ import (
 	"context"
 
 	sq "github.com/Masterminds/squirrel"
+	"github.com/lib/pq"
 
 	"github.com/stellar/go/services/horizon/internal/db2"
 	"github.com/stellar/go/support/errors"
@@ -22,9 +23,64 @@ func (q *Q) GetAccountSignersByAccountID(ctx context.Context, id string) ([]Acco
 	return results, nil
 }
 
+// SignersForAccounts returns the signers of all the given accounts. The
+// accounts are bound as a single array parameter instead of expanding one
+// placeholder per account, which reduces the query planning overhead for large
+// pages.
 func (q *Q) SignersForAccounts(ctx context.Context, accounts []string) ([]AccountSigner, error) {
 	sql := selectAccountSigners.
-		Where(map[string]interface{}{"accounts_signers.account_id": accounts})
+		Where("accounts_signers.account_id = ANY(?)", pq.Array(accounts))
+
+	var results []AccountSigner
+	if err := q.Select(ctx, &results, sql); err != nil {
+		return nil, errors.Wrap(err, "could not run select query")
+	}
+
+	return results, nil
+}
+
+// AccountSignersCount summarizes the signers of an account.
+type AccountSignersCount struct {
+	Count       int   `db:"count"`
+	TotalWeight int32 `db:"total_weight"`
+}
+
+// CountSignersForAccounts returns the number of signers of each of the given
+// accounts and their combined weight, without loading them. A master key
+// with a zero weight isn't stored, so it isn't counted.
+func (q *Q) CountSignersForAccounts(ctx context.Context, accounts []string) (map[string]AccountSignersCount, error) {
+	sql := sq.Select("account_id", "COUNT(*) AS count", "SUM(weight) AS total_weight").
+		From("accounts_signers").
+		Where("account_id = ANY(?)", pq.Array(accounts)).
+		GroupBy("account_id")
+
+	var rows []struct {
+		AccountID string `db:"account_id"`
+		AccountSignersCount
+	}
+	if err := q.Select(ctx, &rows, sql); err != nil {
+		return nil, errors.Wrap(err, "could not run select query")
+	}
+
+	counts := make(map[string]AccountSignersCount, len(rows))
+	for _, row := range rows {
+		counts[row.AccountID] = row.AccountSignersCount
+	}
+	return counts, nil
+}
+
+// SignersSponsoredBy returns the signers, of any account, whose reserve is
+// paid by the given sponsor.
+func (q *Q) SignersSponsoredBy(ctx context.Context, sponsor string) ([]AccountSigner, error) {
+	return q.SignersSponsoredByAny(ctx, []string{sponsor})
+}
+
+// SignersSponsoredByAny returns the signers, of any account, whose reserve is
+// paid by one of the given sponsors.
+func (q *Q) SignersSponsoredByAny(ctx context.Context, sponsors []string) ([]AccountSigner, error) {
+	sql := selectAccountSigners.
+		Where(sq.Eq{"accounts_signers.sponsor": sponsors}).
+		OrderBy("accounts_signers.account_id asc", "accounts_signers.signer asc")
 
 	var results []AccountSigner
 	if err := q.Select(ctx, &results, sql); err != nil {
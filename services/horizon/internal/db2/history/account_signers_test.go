@@ -1,11 +1,15 @@
 package history
 
 import (
+	"context"
 	"testing"
 
 	"github.com/guregu/null"
+	"github.com/stellar/go/keypair"
 	"github.com/stellar/go/services/horizon/internal/db2"
 	"github.com/stellar/go/services/horizon/internal/test"
+	tdb "github.com/stellar/go/services/horizon/internal/test/db"
+	"github.com/stellar/go/support/db"
 )
 
 func TestQueryEmptyAccountSigners(t *testing.T) {
@@ -192,3 +196,100 @@ func TestGetAccountSignersByAccountID(t *testing.T) {
 	tt.Assert.Len(results, 2)
 	tt.Assert.Equal(expected, results)
 }
+
+func TestSignersSponsoredBy(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+	q := &Q{tt.HorizonSession()}
+
+	accountA := "GA5WBPYA5Y4WAEHXWR2UKO2UO4BUGHUQ74EUPKON2QHV4WRHOIRNKKH2"
+	accountB := "GC23QF2HUE52AMXUFUH3AYJAXXGXXV2VHXYYR6EYXETPKDXZSAW67XO4"
+	sponsor := "GCO26ZSBD63TKYX45H2C7D2WOFWOUSG5BMTNC3BG4QMXM3PAYI6WHKVZ"
+	signer := "GC2WJF6YWMAEHGGAK2UOMZCIOMH4RU7KY2CQEWZQJV2ZQJVXJ335ZSXG"
+	_, err := q.CreateAccountSigner(tt.Ctx, accountB, signer, 1, &sponsor)
+	tt.Assert.NoError(err)
+	_, err = q.CreateAccountSigner(tt.Ctx, accountA, signer, 1, &sponsor)
+	tt.Assert.NoError(err)
+	_, err = q.CreateAccountSigner(tt.Ctx, accountA, accountA, 1, nil)
+	tt.Assert.NoError(err)
+
+	results, err := q.SignersSponsoredBy(tt.Ctx, sponsor)
+	tt.Assert.NoError(err)
+	tt.Assert.Equal([]AccountSigner{
+		{Account: accountA, Signer: signer, Weight: 1, Sponsor: null.StringFrom(sponsor)},
+		{Account: accountB, Signer: signer, Weight: 1, Sponsor: null.StringFrom(sponsor)},
+	}, results)
+
+	results, err = q.SignersSponsoredBy(tt.Ctx, accountA)
+	tt.Assert.NoError(err)
+	tt.Assert.Len(results, 0)
+
+	results, err = q.SignersSponsoredByAny(tt.Ctx, []string{accountA, sponsor})
+	tt.Assert.NoError(err)
+	tt.Assert.Len(results, 2)
+}
+
+func TestSignersForAccounts(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+	q := &Q{tt.HorizonSession()}
+
+	accountA := "GA5WBPYA5Y4WAEHXWR2UKO2UO4BUGHUQ74EUPKON2QHV4WRHOIRNKKH2"
+	accountB := "GC23QF2HUE52AMXUFUH3AYJAXXGXXV2VHXYYR6EYXETPKDXZSAW67XO4"
+	accountC := "GCO26ZSBD63TKYX45H2C7D2WOFWOUSG5BMTNC3BG4QMXM3PAYI6WHKVZ"
+	for _, account := range []string{accountA, accountB, accountC} {
+		_, err := q.CreateAccountSigner(tt.Ctx, account, account, 1, nil)
+		tt.Assert.NoError(err)
+	}
+
+	results, err := q.SignersForAccounts(tt.Ctx, []string{accountA, accountC})
+	tt.Assert.NoError(err)
+	tt.Assert.Len(results, 2)
+	accounts := map[string]bool{}
+	for _, result := range results {
+		accounts[result.Account] = true
+	}
+	tt.Assert.Equal(map[string]bool{accountA: true, accountC: true}, accounts)
+
+	results, err = q.SignersForAccounts(tt.Ctx, []string{})
+	tt.Assert.NoError(err)
+	tt.Assert.Len(results, 0)
+}
+
+// BenchmarkSignersForAccounts compares binding the account ids as a single
+// array parameter with expanding one placeholder per account in an IN clause.
+func BenchmarkSignersForAccounts(b *testing.B) {
+	horizonDB := tdb.Horizon(b)
+	test.ResetHorizonDB(b, horizonDB)
+	q := &Q{&db.Session{DB: horizonDB}}
+	ctx := context.Background()
+
+	accounts := make([]string, 200)
+	for i := range accounts {
+		accounts[i] = keypair.MustRandom().Address()
+		if _, err := q.CreateAccountSigner(ctx, accounts[i], accounts[i], 1, nil); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	b.Run("in", func(b *testing.B) {
+		sql := selectAccountSigners.
+			Where(map[string]interface{}{"accounts_signers.account_id": accounts})
+		for i := 0; i < b.N; i++ {
+			var results []AccountSigner
+			if err := q.Select(ctx, &results, sql); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("any", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := q.SignersForAccounts(ctx, accounts); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -2,6 +2,9 @@ package history
 
 import (
 	"context"
+	"fmt"
+	"strconv"
+	"strings"
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/guregu/null"
@@ -48,6 +51,19 @@ func (q *Q) CountAccounts(ctx context.Context) (int, error) {
 	return count, nil
 }
 
+// LatestAccountID returns the highest account id in the accounts table, or
+// an empty string if there are no accounts.
+func (q *Q) LatestAccountID(ctx context.Context) (string, error) {
+	sql := sq.Select("COALESCE(MAX(account_id), '')").From("accounts")
+
+	var accountID string
+	if err := q.Get(ctx, &accountID, sql); err != nil {
+		return "", errors.Wrap(err, "could not run select query")
+	}
+
+	return accountID, nil
+}
+
 func (q *Q) GetAccountByID(ctx context.Context, id string) (AccountEntry, error) {
 	var account AccountEntry
 	sql := selectAccounts.Where(sq.Eq{"account_id": id})
@@ -55,6 +71,15 @@ func (q *Q) GetAccountByID(ctx context.Context, id string) (AccountEntry, error)
 	return account, err
 }
 
+// SequenceByAddress returns the sequence number of the account, or
+// sql.ErrNoRows if it doesn't exist.
+func (q *Q) SequenceByAddress(ctx context.Context, addr string) (int64, error) {
+	var sequence int64
+	sql := sq.Select("sequence_number").From("accounts").Where(sq.Eq{"account_id": addr})
+	err := q.Get(ctx, &sequence, sql)
+	return sequence, err
+}
+
 func (q *Q) GetAccountsByIDs(ctx context.Context, ids []string) ([]AccountEntry, error) {
 	var accounts []AccountEntry
 	sql := selectAccounts.Where(map[string]interface{}{"accounts.account_id": ids})
@@ -62,9 +87,62 @@ func (q *Q) GetAccountsByIDs(ctx context.Context, ids []string) ([]AccountEntry,
 	return accounts, err
 }
 
+// AccountSubentriesSummary counts the sub-entries of an account.
+type AccountSubentriesSummary struct {
+	NumSigners    int `db:"num_signers"`
+	NumTrustLines int `db:"num_trustlines"`
+	NumData       int `db:"num_data"`
+	// LastModifiedLedger is the last ledger in which one of the trust lines
+	// or data entries of the account changed, 0 if it holds none.
+	LastModifiedLedger uint32 `db:"last_modified_ledger"`
+}
+
+// SummarizeAccountSubentries counts the signers, trust lines and data
+// entries of an account in a single query, without loading them.
+func (q *Q) SummarizeAccountSubentries(ctx context.Context, accountID string) (AccountSubentriesSummary, error) {
+	var summary AccountSubentriesSummary
+	err := q.GetRaw(ctx, &summary, `
+		SELECT
+			(SELECT COUNT(*) FROM accounts_signers WHERE account_id = $1) AS num_signers,
+			(SELECT COUNT(*) FROM trust_lines WHERE account_id = $1) AS num_trustlines,
+			(SELECT COUNT(*) FROM accounts_data WHERE account_id = $1) AS num_data,
+			COALESCE(GREATEST(
+				(SELECT MAX(last_modified_ledger) FROM trust_lines WHERE account_id = $1),
+				(SELECT MAX(last_modified_ledger) FROM accounts_data WHERE account_id = $1)
+			), 0) AS last_modified_ledger
+	`, accountID)
+	if err != nil {
+		return AccountSubentriesSummary{}, errors.Wrap(err, "could not summarize account subentries")
+	}
+	return summary, nil
+}
+
+// countRowsByAccount returns the number of rows of table held by each of the
+// given accounts. Accounts without rows are left out.
+func (q *Q) countRowsByAccount(ctx context.Context, table string, accounts []string) (map[string]int, error) {
+	sql := sq.Select("account_id", "COUNT(*) AS count").
+		From(table).
+		Where("account_id = ANY(?)", pq.Array(accounts)).
+		GroupBy("account_id")
+
+	var rows []struct {
+		AccountID string `db:"account_id"`
+		Count     int    `db:"count"`
+	}
+	if err := q.Select(ctx, &rows, sql); err != nil {
+		return nil, errors.Wrapf(err, "could not count %s", table)
+	}
+
+	counts := make(map[string]int, len(rows))
+	for _, row := range rows {
+		counts[row.AccountID] = row.Count
+	}
+	return counts, nil
+}
+
 func accountToMap(entry xdr.LedgerEntry) map[string]interface{} {
 	account := entry.Data.MustAccount()
-	liabilities := account.Liabilities()
+	ext := account.NormalizedExt()
 
 	var inflationDestination = ""
 	if account.InflationDest != nil {
@@ -74,8 +152,8 @@ func accountToMap(entry xdr.LedgerEntry) map[string]interface{} {
 	return map[string]interface{}{
 		"account_id":            account.AccountId.Address(),
 		"balance":               account.Balance,
-		"buying_liabilities":    liabilities.Buying,
-		"selling_liabilities":   liabilities.Selling,
+		"buying_liabilities":    ext.Liabilities.Buying,
+		"selling_liabilities":   ext.Liabilities.Selling,
 		"sequence_number":       account.SeqNum,
 		"num_subentries":        account.NumSubEntries,
 		"inflation_destination": inflationDestination,
@@ -87,8 +165,42 @@ func accountToMap(entry xdr.LedgerEntry) map[string]interface{} {
 		"threshold_high":        account.ThresholdHigh(),
 		"last_modified_ledger":  entry.LastModifiedLedgerSeq,
 		"sponsor":               ledgerEntrySponsorToNullString(entry),
-		"num_sponsored":         account.NumSponsored(),
-		"num_sponsoring":        account.NumSponsoring(),
+		"num_sponsored":         ext.NumSponsored,
+		"num_sponsoring":        ext.NumSponsoring,
+	}
+}
+
+// AccountFromCore converts an account ledger entry, as stored by
+// stellar-core, into an AccountEntry. The fields are converted like the
+// ingestion of accounts converts them, so the result matches the stored
+// record apart from its row id.
+func AccountFromCore(entry xdr.LedgerEntry) AccountEntry {
+	account := entry.Data.MustAccount()
+	ext := account.NormalizedExt()
+
+	var inflationDestination string
+	if account.InflationDest != nil {
+		inflationDestination = account.InflationDest.Address()
+	}
+
+	return AccountEntry{
+		AccountID:            account.AccountId.Address(),
+		Balance:              int64(account.Balance),
+		BuyingLiabilities:    int64(ext.Liabilities.Buying),
+		SellingLiabilities:   int64(ext.Liabilities.Selling),
+		SequenceNumber:       int64(account.SeqNum),
+		NumSubEntries:        uint32(account.NumSubEntries),
+		InflationDestination: inflationDestination,
+		HomeDomain:           string(account.HomeDomain),
+		Flags:                uint32(account.Flags),
+		MasterWeight:         account.MasterKeyWeight(),
+		ThresholdLow:         account.ThresholdLow(),
+		ThresholdMedium:      account.ThresholdMedium(),
+		ThresholdHigh:        account.ThresholdHigh(),
+		LastModifiedLedger:   uint32(entry.LastModifiedLedgerSeq),
+		Sponsor:              ledgerEntrySponsorToNullString(entry),
+		NumSponsored:         uint32(ext.NumSponsored),
+		NumSponsoring:        uint32(ext.NumSponsoring),
 	}
 }
 
@@ -226,27 +338,247 @@ func (q *Q) RemoveAccount(ctx context.Context, accountID string) (int64, error)
 	return result.RowsAffected()
 }
 
-// AccountsForAsset returns a list of `AccountEntry` rows who are trustee to an
-// asset
-func (q *Q) AccountsForAsset(ctx context.Context, asset xdr.Asset, page db2.PageQuery) ([]AccountEntry, error) {
-	var assetType, code, issuer string
-	asset.MustExtract(&assetType, &code, &issuer)
+// AssetsMatchMode defines how AccountsForAssets matches accounts against a
+// set of assets.
+type AssetsMatchMode string
 
-	sql := sq.
-		Select("accounts.*").
-		From("accounts").
-		Join("trust_lines ON accounts.account_id = trust_lines.account_id").
-		Where(map[string]interface{}{
+const (
+	// MatchAnyAsset matches accounts having a trustline to at least one of
+	// the assets.
+	MatchAnyAsset AssetsMatchMode = "any"
+	// MatchAllAssets matches accounts having a trustline to every asset.
+	MatchAllAssets AssetsMatchMode = "all"
+)
+
+// AccountsForAssets returns a list of `AccountEntry` rows who are trustee to
+// any or all (depending on mode) of the given assets.
+func (q *Q) AccountsForAssets(ctx context.Context, assets []xdr.Asset, mode AssetsMatchMode, page db2.PageQuery) ([]AccountEntry, error) {
+	if len(assets) == 0 {
+		return nil, errors.New("at least one asset is required")
+	}
+
+	seen := map[string]bool{}
+	filter := sq.Or{}
+	for _, asset := range assets {
+		key := asset.String()
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		var assetType, code, issuer string
+		asset.MustExtract(&assetType, &code, &issuer)
+		filter = append(filter, sq.Eq{
 			"trust_lines.asset_type":   int32(asset.Type),
 			"trust_lines.asset_issuer": issuer,
 			"trust_lines.asset_code":   code,
 		})
+	}
 
-	sql, err := page.ApplyToUsingCursor(sql, "trust_lines.account_id", page.Cursor)
-	if err != nil {
-		return nil, errors.Wrap(err, "could not apply query to page")
+	query := newAccountsQueryBuilder().
+		join("trust_lines ON accounts.account_id = trust_lines.account_id").
+		where(filter)
+
+	switch mode {
+	case MatchAnyAsset:
+		query = query.groupByAccount(nil)
+	case MatchAllAssets:
+		query = query.groupByAccount("COUNT(*) = ?", len(filter))
+	default:
+		return nil, errors.Errorf("invalid assets match mode: %s", mode)
 	}
 
+	return q.selectAccountsPage(ctx, query, page)
+}
+
+// AccountsWithWeakThresholds returns a list of `AccountEntry` rows whose
+// medium or high threshold is lower than the weight of the master key.
+func (q *Q) AccountsWithWeakThresholds(ctx context.Context, page db2.PageQuery) ([]AccountEntry, error) {
+	query := newAccountsQueryBuilder().
+		where("(accounts.threshold_medium < accounts.master_weight OR accounts.threshold_high < accounts.master_weight)")
+
+	return q.selectAccountsPage(ctx, query, page)
+}
+
+// thresholdColumns maps the thresholds accepted by AccountsWithThreshold to
+// their columns.
+var thresholdColumns = map[string]string{
+	"low":  "accounts.threshold_low",
+	"med":  "accounts.threshold_medium",
+	"high": "accounts.threshold_high",
+}
+
+// AccountsWithThreshold returns a list of `AccountEntry` rows whose low, med
+// or high threshold (depending on which) compares to value with op, either
+// ">=" or "<=".
+func (q *Q) AccountsWithThreshold(ctx context.Context, which, op string, value int, page db2.PageQuery) ([]AccountEntry, error) {
+	column, ok := thresholdColumns[which]
+	if !ok {
+		return nil, errors.Errorf("invalid threshold: %s", which)
+	}
+
+	var filter sq.Sqlizer
+	switch op {
+	case ">=":
+		filter = sq.GtOrEq{column: value}
+	case "<=":
+		filter = sq.LtOrEq{column: value}
+	default:
+		return nil, errors.Errorf("invalid threshold operator: %s", op)
+	}
+
+	return q.selectAccountsPage(ctx, newAccountsQueryBuilder().where(filter), page)
+}
+
+// AccountsWithDataValue returns a list of `AccountEntry` rows holding a data
+// entry with the given name and value.
+func (q *Q) AccountsWithDataValue(ctx context.Context, name string, value []byte, page db2.PageQuery) ([]AccountEntry, error) {
+	query := newAccountsQueryBuilder().
+		join("accounts_data ON accounts.account_id = accounts_data.account_id").
+		where(sq.Eq{
+			"accounts_data.name":  name,
+			"accounts_data.value": AccountDataValue(value),
+		})
+
+	return q.selectAccountsPage(ctx, query, page)
+}
+
+// AccountsWithLiabilities returns a list of `AccountEntry` rows with nonzero
+// buying or selling liabilities, either in the native balance or in any of
+// their trust lines.
+func (q *Q) AccountsWithLiabilities(ctx context.Context, page db2.PageQuery) ([]AccountEntry, error) {
+	query := newAccountsQueryBuilder().
+		where(`(
+			accounts.buying_liabilities > 0 OR accounts.selling_liabilities > 0 OR
+			EXISTS (
+				SELECT 1 FROM trust_lines
+				WHERE trust_lines.account_id = accounts.account_id
+				AND (trust_lines.buying_liabilities > 0 OR trust_lines.selling_liabilities > 0)
+			)
+		)`)
+
+	return q.selectAccountsPage(ctx, query, page)
+}
+
+// AccountsWithoutHomeDomain returns a list of `AccountEntry` rows whose
+// home domain is unset.
+func (q *Q) AccountsWithoutHomeDomain(ctx context.Context, page db2.PageQuery) ([]AccountEntry, error) {
+	query := newAccountsQueryBuilder().where(sq.Eq{"accounts.home_domain": ""})
+	return q.selectAccountsPage(ctx, query, page)
+}
+
+// AccountsForHomeDomain returns a list of `AccountEntry` rows whose home
+// domain is exactly the given domain.
+func (q *Q) AccountsForHomeDomain(ctx context.Context, domain string, page db2.PageQuery) ([]AccountEntry, error) {
+	query := newAccountsQueryBuilder().where(sq.Eq{"accounts.home_domain": domain})
+	return q.selectAccountsPage(ctx, query, page)
+}
+
+// signerTypePrefixes maps the signer types to the first character of the
+// strkey of their keys.
+var signerTypePrefixes = map[string]string{
+	"ed25519_public_key":  "G",
+	"ed25519_secret_seed": "S",
+	"sha256_hash":         "X",
+	"preauth_tx":          "T",
+}
+
+// AccountsWithSignerType returns a list of `AccountEntry` rows with at least
+// one signer of the given type, like preauth_tx. Every account has its
+// master key as an ed25519_public_key signer.
+func (q *Q) AccountsWithSignerType(ctx context.Context, signerType string, page db2.PageQuery) ([]AccountEntry, error) {
+	prefix, ok := signerTypePrefixes[signerType]
+	if !ok {
+		return nil, errors.Errorf("unknown signer type: %s", signerType)
+	}
+
+	query := newAccountsQueryBuilder().
+		where(`EXISTS (
+			SELECT 1 FROM accounts_signers
+			WHERE accounts_signers.account_id = accounts.account_id
+			AND accounts_signers.signer LIKE ?
+		)`, prefix+"%")
+
+	return q.selectAccountsPage(ctx, query, page)
+}
+
+// AccountsModifiedBetween returns a list of `AccountEntry` rows last modified
+// in a ledger between from and to, both included.
+func (q *Q) AccountsModifiedBetween(ctx context.Context, from, to uint32, page db2.PageQuery) ([]AccountEntry, error) {
+	query := newAccountsQueryBuilder().
+		where("accounts.last_modified_ledger BETWEEN ? AND ?", from, to)
+
+	return q.selectAccountsPage(ctx, query, page)
+}
+
+// NativeBalanceCursor returns the paging token of an account in the pages of
+// AccountsByNativeBalance.
+func NativeBalanceCursor(account AccountEntry) string {
+	return fmt.Sprintf("%d-%s", account.Balance, account.AccountID)
+}
+
+// AccountRowIDCursor returns the numeric paging token of an account, its row
+// id, accepted by the queries allowing row id cursors. Row ids change when the
+// state is rebuilt, so these tokens don't outlive a rebuild.
+func AccountRowIDCursor(account AccountEntry) string {
+	return strconv.FormatInt(account.RowID, 10)
+}
+
+// ParseAccountRowIDCursor returns the row id of a numeric cursor, ok is false
+// when the cursor isn't numeric, like an account id.
+func ParseAccountRowIDCursor(cursor string) (rowID int64, ok bool) {
+	rowID, err := strconv.ParseInt(cursor, 10, 64)
+	if err != nil || rowID < 0 {
+		return 0, false
+	}
+	return rowID, true
+}
+
+// ParseNativeBalanceCursor returns the balance and the account id of a
+// cursor built by NativeBalanceCursor.
+func ParseNativeBalanceCursor(cursor string) (int64, string, error) {
+	parts := strings.SplitN(cursor, "-", 2)
+	if len(parts) != 2 {
+		return 0, "", errors.New("invalid cursor")
+	}
+
+	balance, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil || balance < 0 {
+		return 0, "", errors.New("invalid cursor - first value should be a balance")
+	}
+
+	return balance, parts[1], nil
+}
+
+// AccountsByNativeBalance returns a list of `AccountEntry` rows sorted by
+// native balance, using the account id to break ties.
+func (q *Q) AccountsByNativeBalance(ctx context.Context, page db2.PageQuery) ([]AccountEntry, error) {
+	sql := newAccountsQueryBuilder().sql.Limit(page.Limit)
+
+	var op string
+	switch page.Order {
+	case db2.OrderAscending:
+		op = ">"
+	case db2.OrderDescending:
+		op = "<"
+	default:
+		return nil, errors.Errorf("invalid order: %s", page.Order)
+	}
+
+	if page.Cursor != "" {
+		balance, accountID, err := ParseNativeBalanceCursor(page.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		sql = sql.Where(
+			sq.Expr("(accounts.balance, accounts.account_id) "+op+" (?, ?)", balance, accountID),
+		)
+	}
+	sql = sql.OrderBy(
+		"accounts.balance "+page.Order,
+		"accounts.account_id "+page.Order,
+	)
+
 	var results []AccountEntry
 	if err := q.Select(ctx, &results, sql); err != nil {
 		return nil, errors.Wrap(err, "could not run select query")
@@ -255,6 +587,97 @@ func (q *Q) AccountsForAsset(ctx context.Context, asset xdr.Asset, page db2.Page
 	return results, nil
 }
 
+// AccountsForAsset returns a list of `AccountEntry` rows who are trustee to an
+// asset
+func (q *Q) AccountsForAsset(ctx context.Context, asset xdr.Asset, page db2.PageQuery) ([]AccountEntry, error) {
+	return q.selectAccountsPage(ctx, accountsForAssetQuery(asset), page)
+}
+
+// AccountsForAssetWithBalance returns a list of `AccountEntry` rows who are
+// trustee to an asset and hold exactly the given balance of it, like 0 for
+// the accounts which opted in but weren't funded yet.
+func (q *Q) AccountsForAssetWithBalance(ctx context.Context, asset xdr.Asset, balance int64, page db2.PageQuery) ([]AccountEntry, error) {
+	query := accountsForAssetQuery(asset).where(sq.Eq{"trust_lines.balance": balance})
+	return q.selectAccountsPage(ctx, query, page)
+}
+
+const (
+	// MinAccountIDPrefixLength is the minimum length of the prefixes of
+	// AccountsWithIDPrefix, shorter prefixes would match too many accounts.
+	MinAccountIDPrefixLength = 5
+	// accountIDLength is the length of the strkey of an account id.
+	accountIDLength = 56
+)
+
+// AccountsWithIDPrefix returns the ids of the accounts starting with prefix,
+// up to limit, in ascending order. The ids are bounded by the prefix padded
+// with the lowest and the highest base32 characters, so the query is served
+// by the primary key of the accounts.
+func (q *Q) AccountsWithIDPrefix(ctx context.Context, prefix string, limit int) ([]string, error) {
+	if len(prefix) < MinAccountIDPrefixLength {
+		return nil, errors.Errorf("prefix must be at least %d characters long", MinAccountIDPrefixLength)
+	}
+	if len(prefix) > accountIDLength {
+		return nil, errors.Errorf("prefix must be at most %d characters long", accountIDLength)
+	}
+	padding := accountIDLength - len(prefix)
+
+	sql := sq.Select("account_id").
+		From("accounts").
+		Where(sq.GtOrEq{"account_id": prefix + strings.Repeat("2", padding)}).
+		Where(sq.LtOrEq{"account_id": prefix + strings.Repeat("Z", padding)}).
+		Where("account_id LIKE ?", prefix+"%").
+		OrderBy("account_id asc").
+		Limit(uint64(limit))
+
+	var accountIDs []string
+	if err := q.Select(ctx, &accountIDs, sql); err != nil {
+		return nil, errors.Wrap(err, "could not run select query")
+	}
+	return accountIDs, nil
+}
+
+// TopHoldersForAsset returns the n accounts holding the largest balances of
+// the asset, largest first. Accounts holding the same balance are ordered by
+// account id.
+func (q *Q) TopHoldersForAsset(ctx context.Context, asset xdr.Asset, n uint64) ([]AccountEntry, error) {
+	sql := accountsForAssetQuery(asset).sql.
+		OrderBy("trust_lines.balance desc", "accounts.account_id asc").
+		Limit(n)
+
+	var results []AccountEntry
+	if err := q.Select(ctx, &results, sql); err != nil {
+		return nil, errors.Wrap(err, "could not run select query")
+	}
+	return results, nil
+}
+
+// ExplainAccountsForAsset returns the query AccountsForAsset would run
+// without executing it.
+func (q *Q) ExplainAccountsForAsset(ctx context.Context, asset xdr.Asset, page db2.PageQuery, withPlan bool) (QueryExplanation, error) {
+	sql, err := accountsForAssetQuery(asset).build(page)
+	if err != nil {
+		return QueryExplanation{}, err
+	}
+
+	return q.explain(ctx, sql, withPlan)
+}
+
+func accountsForAssetQuery(asset xdr.Asset) accountsQueryBuilder {
+	var assetType, code, issuer string
+	asset.MustExtract(&assetType, &code, &issuer)
+
+	return newAccountsQueryBuilder().
+		join("trust_lines ON accounts.account_id = trust_lines.account_id").
+		where(map[string]interface{}{
+			"trust_lines.asset_type":   int32(asset.Type),
+			"trust_lines.asset_issuer": issuer,
+			"trust_lines.asset_code":   code,
+		}).
+		pageBy("trust_lines.account_id").
+		withNumericCursor()
+}
+
 func selectBySponsor(table, sponsor string, page db2.PageQuery) (sq.SelectBuilder, error) {
 	sql := sq.
 		Select("account_id").
@@ -292,7 +715,7 @@ func selectUnionBySponsor(tables []string, sponsor string, page db2.PageQuery) (
 	}
 
 	return sq.
-		Select("accounts.*").
+		Select(qualifiedAccountColumns()...).
 		FromSelect(selectIDs, "accountSet").
 		Join("accounts ON accounts.account_id = accountSet.account_id").
 		OrderBy("accounts.account_id " + page.Order).
@@ -321,43 +744,86 @@ func (q *Q) AccountsForSponsor(ctx context.Context, sponsor string, page db2.Pag
 
 // AccountEntriesForSigner returns a list of `AccountEntry` rows for a given signer
 func (q *Q) AccountEntriesForSigner(ctx context.Context, signer string, page db2.PageQuery) ([]AccountEntry, error) {
-	sql := sq.
-		Select("accounts.*").
-		From("accounts").
-		Join("accounts_signers ON accounts.account_id = accounts_signers.account_id").
-		Where(map[string]interface{}{
-			"accounts_signers.signer": signer,
-		})
+	return q.selectAccountsPage(ctx, accountEntriesForSignerQuery(signer), page)
+}
 
-	sql, err := page.ApplyToUsingCursor(sql, "accounts_signers.account_id", page.Cursor)
+// ExplainAccountEntriesForSigner returns the query AccountEntriesForSigner
+// would run without executing it.
+func (q *Q) ExplainAccountEntriesForSigner(ctx context.Context, signer string, page db2.PageQuery, withPlan bool) (QueryExplanation, error) {
+	sql, err := accountEntriesForSignerQuery(signer).build(page)
 	if err != nil {
-		return nil, errors.Wrap(err, "could not apply query to page")
+		return QueryExplanation{}, err
 	}
 
-	var results []AccountEntry
-	if err := q.Select(ctx, &results, sql); err != nil {
-		return nil, errors.Wrap(err, "could not run select query")
+	return q.explain(ctx, sql, withPlan)
+}
+
+// AccountsForAllSigners returns a list of `AccountEntry` rows which have all
+// the given signers.
+func (q *Q) AccountsForAllSigners(ctx context.Context, signers []string, page db2.PageQuery) ([]AccountEntry, error) {
+	if len(signers) == 0 {
+		return nil, errors.New("at least one signer is required")
 	}
 
-	return results, nil
+	seen := map[string]bool{}
+	var unique []string
+	for _, signer := range signers {
+		if !seen[signer] {
+			seen[signer] = true
+			unique = append(unique, signer)
+		}
+	}
+
+	query := newAccountsQueryBuilder().
+		join("accounts_signers ON accounts.account_id = accounts_signers.account_id").
+		where("accounts_signers.signer = ANY(?)", pq.Array(unique)).
+		groupByAccount("COUNT(*) = ?", len(unique))
+
+	return q.selectAccountsPage(ctx, query, page)
+}
+
+func accountEntriesForSignerQuery(signer string) accountsQueryBuilder {
+	return newAccountsQueryBuilder().
+		join("accounts_signers ON accounts.account_id = accounts_signers.account_id").
+		where(map[string]interface{}{
+			"accounts_signers.signer": signer,
+		}).
+		pageBy("accounts_signers.account_id").
+		withNumericCursor()
+}
+
+// accountColumns is the allowlist of the columns of the accounts table loaded
+// into AccountEntry. Accounts are never selected with `accounts.*`, so columns
+// added to the table for internal use are not read unless listed here.
+var accountColumns = []string{
+	"account_id",
+	"balance",
+	"buying_liabilities",
+	"selling_liabilities",
+	"sequence_number",
+	"num_subentries",
+	"inflation_destination",
+	"flags",
+	"home_domain",
+	"master_weight",
+	"threshold_low",
+	"threshold_medium",
+	"threshold_high",
+	"last_modified_ledger",
+	"sponsor",
+	"num_sponsored",
+	"num_sponsoring",
+	"row_id",
+}
+
+// qualifiedAccountColumns returns accountColumns prefixed with the accounts
+// table, for queries joining other tables.
+func qualifiedAccountColumns() []string {
+	columns := make([]string, len(accountColumns))
+	for i, column := range accountColumns {
+		columns[i] = "accounts." + column
+	}
+	return columns
 }
 
-var selectAccounts = sq.Select(`
-	account_id,
-	balance,
-	buying_liabilities,
-	selling_liabilities,
-	sequence_number,
-	num_subentries,
-	inflation_destination,
-	flags,
-	home_domain,
-	master_weight,
-	threshold_low,
-	threshold_medium,
-	threshold_high,
-	last_modified_ledger,
-	sponsor,
-	num_sponsored,
-	num_sponsoring
-`).From("accounts")
+var selectAccounts = sq.Select(accountColumns...).From("accounts")
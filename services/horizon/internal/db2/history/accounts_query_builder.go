@@ -0,0 +1,99 @@
+package history
+
+import (
+	"context"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"github.com/stellar/go/services/horizon/internal/db2"
+	"github.com/stellar/go/support/errors"
+)
+
+// accountsQueryBuilder assembles the queries listing pages of accounts.
+// Filters only add their joins and conditions, the cursor, order and limit
+// are applied by build on the paging column.
+type accountsQueryBuilder struct {
+	sql          sq.SelectBuilder
+	pagingColumn string
+	// numericCursor enables paging by accounts.row_id for numeric cursors.
+	numericCursor bool
+}
+
+// newAccountsQueryBuilder returns a builder selecting all the accounts,
+// paged by accounts.account_id.
+func newAccountsQueryBuilder() accountsQueryBuilder {
+	return accountsQueryBuilder{
+		sql:          sq.Select(qualifiedAccountColumns()...).From("accounts"),
+		pagingColumn: "accounts.account_id",
+	}
+}
+
+// join joins the accounts with another table.
+func (b accountsQueryBuilder) join(join string, args ...interface{}) accountsQueryBuilder {
+	b.sql = b.sql.Join(join, args...)
+	return b
+}
+
+// where adds a condition the accounts must match.
+func (b accountsQueryBuilder) where(pred interface{}, args ...interface{}) accountsQueryBuilder {
+	b.sql = b.sql.Where(pred, args...)
+	return b
+}
+
+// groupByAccount groups the joined rows by account, keeping only the
+// groups matching having.
+func (b accountsQueryBuilder) groupByAccount(having interface{}, args ...interface{}) accountsQueryBuilder {
+	b.sql = b.sql.GroupBy("accounts.account_id")
+	if having != nil {
+		b.sql = b.sql.Having(having, args...)
+	}
+	return b
+}
+
+// pageBy pages the accounts by a column of a joined table equal to
+// accounts.account_id, so the query can be served by an index of that table.
+func (b accountsQueryBuilder) pageBy(column string) accountsQueryBuilder {
+	b.pagingColumn = column
+	return b
+}
+
+// withNumericCursor supports the numeric cursor, the row id of an account:
+// when the cursor is numeric the accounts are paged by accounts.row_id
+// instead of the paging column, see ParseAccountRowIDCursor.
+func (b accountsQueryBuilder) withNumericCursor() accountsQueryBuilder {
+	b.numericCursor = true
+	return b
+}
+
+// build applies the paging to the query.
+func (b accountsQueryBuilder) build(page db2.PageQuery) (sq.SelectBuilder, error) {
+	var column string
+	var cursor interface{}
+	if rowID, ok := ParseAccountRowIDCursor(page.Cursor); b.numericCursor && ok {
+		column, cursor = "accounts.row_id", rowID
+	} else {
+		column, cursor = b.pagingColumn, page.Cursor
+	}
+
+	sql, err := page.ApplyToUsingCursor(b.sql, column, cursor)
+	if err != nil {
+		return sql, errors.Wrap(err, "could not apply query to page")
+	}
+
+	return sql, nil
+}
+
+// selectAccountsPage loads the page of accounts matching the query of b.
+func (q *Q) selectAccountsPage(ctx context.Context, b accountsQueryBuilder, page db2.PageQuery) ([]AccountEntry, error) {
+	sql, err := b.build(page)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []AccountEntry
+	if err := q.Select(ctx, &results, sql); err != nil {
+		return nil, errors.Wrap(err, "could not run select query")
+	}
+
+	return results, nil
+}
@@ -0,0 +1,133 @@
+package history
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/stellar/go/services/horizon/internal/db2"
+	"github.com/stellar/go/services/horizon/internal/test"
+	"github.com/stellar/go/xdr"
+)
+
+func TestAccountsQueryBuilderBuild(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+
+	page := db2.PageQuery{
+		Order:  db2.OrderDescending,
+		Limit:  2,
+		Cursor: "GCT2NQM5KJJEF55NPMY444C6M6CA7T33HRNCMA6ZFBIIXKNCRO6J25K7",
+	}
+
+	sql, err := newAccountsQueryBuilder().build(page)
+	tt.Assert.NoError(err)
+	sqlStr, args, err := sql.ToSql()
+	tt.Assert.NoError(err)
+	tt.Assert.Contains(sqlStr, "FROM accounts WHERE accounts.account_id < ? ORDER BY accounts.account_id desc LIMIT 2")
+	tt.Assert.Equal([]interface{}{page.Cursor}, args)
+
+	// filters joining another table can page by its account_id column
+	sql, err = accountEntriesForSignerQuery(page.Cursor).build(page)
+	tt.Assert.NoError(err)
+	sqlStr, _, err = sql.ToSql()
+	tt.Assert.NoError(err)
+	tt.Assert.Contains(sqlStr, "accounts_signers.account_id < ? ORDER BY accounts_signers.account_id desc LIMIT 2")
+
+	page.Order = "random"
+	_, err = newAccountsQueryBuilder().build(page)
+	tt.Assert.EqualError(err, "could not apply query to page: invalid order: random")
+}
+
+func TestAccountsFiltersPaging(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+	q := &Q{tt.HorizonSession()}
+
+	// every account has weak thresholds, liabilities, a trust line to EUR
+	// and the same signer, so all the filters match all of them
+	signer := "GCXKG6RN4ONIEPCMNFB732A436Z5PNDSRLGWK7GBLCMQLIFO4S7EYWVU"
+	var addresses []string
+	batch := q.NewAccountsBatchInsertBuilder(0)
+	for _, entry := range []xdr.LedgerEntry{account1, account2, account3} {
+		account := *entry.Data.Account
+		account.Thresholds = xdr.Thresholds{9, 6, 7, 8}
+		entry.Data.Account = &account
+		tt.Assert.NoError(batch.Add(tt.Ctx, entry))
+
+		trustLine := eurTrustLine
+		trustLineEntry := *eurTrustLine.Data.TrustLine
+		trustLineEntry.AccountId = account.AccountId
+		trustLine.Data.TrustLine = &trustLineEntry
+		_, err := q.InsertTrustLine(tt.Ctx, trustLine)
+		tt.Assert.NoError(err)
+
+		_, err = q.CreateAccountSigner(tt.Ctx, account.AccountId.Address(), signer, 1, nil)
+		tt.Assert.NoError(err)
+
+		addresses = append(addresses, account.AccountId.Address())
+	}
+	tt.Assert.NoError(batch.Exec(tt.Ctx))
+	// account1 < account2 < account3
+	tt.Assert.True(sort.StringsAreSorted(addresses))
+
+	eur := eurTrustLine.Data.TrustLine.Asset
+	for _, filter := range []struct {
+		name  string
+		query func(context.Context, db2.PageQuery) ([]AccountEntry, error)
+	}{
+		{"signer", func(ctx context.Context, page db2.PageQuery) ([]AccountEntry, error) {
+			return q.AccountEntriesForSigner(ctx, signer, page)
+		}},
+		{"asset", func(ctx context.Context, page db2.PageQuery) ([]AccountEntry, error) {
+			return q.AccountsForAsset(ctx, eur, page)
+		}},
+		{"any assets", func(ctx context.Context, page db2.PageQuery) ([]AccountEntry, error) {
+			return q.AccountsForAssets(ctx, []xdr.Asset{eur}, MatchAnyAsset, page)
+		}},
+		{"all assets", func(ctx context.Context, page db2.PageQuery) ([]AccountEntry, error) {
+			return q.AccountsForAssets(ctx, []xdr.Asset{eur}, MatchAllAssets, page)
+		}},
+		{"all signers", func(ctx context.Context, page db2.PageQuery) ([]AccountEntry, error) {
+			return q.AccountsForAllSigners(ctx, []string{signer}, page)
+		}},
+		{"weak thresholds", q.AccountsWithWeakThresholds},
+		{"liabilities", q.AccountsWithLiabilities},
+		{"threshold", func(ctx context.Context, page db2.PageQuery) ([]AccountEntry, error) {
+			return q.AccountsWithThreshold(ctx, "low", ">=", 0, page)
+		}},
+	} {
+		t.Run(filter.name, func(t *testing.T) {
+			for _, testCase := range []struct {
+				page     db2.PageQuery
+				expected []string
+			}{
+				{
+					db2.PageQuery{Order: db2.OrderAscending, Limit: 2},
+					addresses[:2],
+				},
+				{
+					db2.PageQuery{Order: db2.OrderAscending, Limit: 2, Cursor: addresses[1]},
+					addresses[2:],
+				},
+				{
+					db2.PageQuery{Order: db2.OrderDescending, Limit: 2},
+					[]string{addresses[2], addresses[1]},
+				},
+				{
+					db2.PageQuery{Order: db2.OrderDescending, Limit: 2, Cursor: addresses[1]},
+					addresses[:1],
+				},
+			} {
+				accounts, err := filter.query(tt.Ctx, testCase.page)
+				tt.Assert.NoError(err)
+				var ids []string
+				for _, account := range accounts {
+					ids = append(ids, account.AccountID)
+				}
+				tt.Assert.Equal(testCase.expected, ids)
+			}
+		})
+	}
+}
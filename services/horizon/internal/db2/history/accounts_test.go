@@ -1,6 +1,7 @@
 package history
 
 import (
+	"reflect"
 	"testing"
 
 	"github.com/guregu/null"
@@ -302,6 +303,78 @@ func TestRemoveAccount(t *testing.T) {
 	assert.Equal(t, int64(0), rows)
 }
 
+func TestSequenceByAddress(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+	q := &Q{tt.HorizonSession()}
+
+	batch := q.NewAccountsBatchInsertBuilder(0)
+	tt.Assert.NoError(batch.Add(tt.Ctx, account1))
+	tt.Assert.NoError(batch.Exec(tt.Ctx))
+
+	sequence, err := q.SequenceByAddress(tt.Ctx, account1.Data.Account.AccountId.Address())
+	tt.Assert.NoError(err)
+	tt.Assert.Equal(int64(account1.Data.Account.SeqNum), sequence)
+
+	_, err = q.SequenceByAddress(tt.Ctx, account2.Data.Account.AccountId.Address())
+	tt.Assert.True(q.NoRows(err))
+}
+
+func TestAccountsByNativeBalance(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+	q := &Q{tt.HorizonSession()}
+
+	batch := q.NewAccountsBatchInsertBuilder(0)
+	// account1 has 20000 stroops, account2 and account3 have 50000
+	tt.Assert.NoError(batch.Add(tt.Ctx, account1))
+	tt.Assert.NoError(batch.Add(tt.Ctx, account2))
+	tt.Assert.NoError(batch.Add(tt.Ctx, account3))
+	tt.Assert.NoError(batch.Exec(tt.Ctx))
+
+	address1 := account1.Data.Account.AccountId.Address()
+	address2 := account2.Data.Account.AccountId.Address()
+	address3 := account3.Data.Account.AccountId.Address()
+	accountIDs := func(page db2.PageQuery) []string {
+		accounts, err := q.AccountsByNativeBalance(tt.Ctx, page)
+		tt.Assert.NoError(err)
+		var ids []string
+		for _, account := range accounts {
+			ids = append(ids, account.AccountID)
+		}
+		return ids
+	}
+
+	tt.Assert.Equal(
+		[]string{address3, address2, address1},
+		accountIDs(db2.PageQuery{Order: db2.OrderDescending, Limit: 10}),
+	)
+	tt.Assert.Equal(
+		[]string{address1, address2, address3},
+		accountIDs(db2.PageQuery{Order: db2.OrderAscending, Limit: 10}),
+	)
+
+	// the cursor breaks the tie between account2 and account3
+	tt.Assert.Equal(
+		[]string{address2, address1},
+		accountIDs(db2.PageQuery{Order: db2.OrderDescending, Limit: 10, Cursor: "50000-" + address3}),
+	)
+	tt.Assert.Equal(
+		[]string{address3},
+		accountIDs(db2.PageQuery{Order: db2.OrderAscending, Limit: 10, Cursor: "50000-" + address2}),
+	)
+
+	tt.Assert.Equal(
+		"20000-"+address1,
+		NativeBalanceCursor(AccountEntry{AccountID: address1, Balance: 20000}),
+	)
+
+	_, err := q.AccountsByNativeBalance(tt.Ctx, db2.PageQuery{Order: db2.OrderDescending, Limit: 10, Cursor: address1})
+	tt.Assert.EqualError(err, "invalid cursor")
+}
+
 func TestAccountsForAsset(t *testing.T) {
 	tt := test.Start(t)
 	defer tt.Finish()
@@ -355,6 +428,625 @@ func TestAccountsForAsset(t *testing.T) {
 	tt.Assert.Len(accounts, 1)
 }
 
+func TestAccountsPagedByRowID(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+	q := &Q{tt.HorizonSession()}
+
+	// account2 is inserted first, so the row ids are in the opposite order
+	// of the account ids
+	for _, entry := range []xdr.LedgerEntry{account2, account1} {
+		batch := q.NewAccountsBatchInsertBuilder(0)
+		tt.Assert.NoError(batch.Add(tt.Ctx, entry))
+		tt.Assert.NoError(batch.Exec(tt.Ctx))
+	}
+
+	const signer = "GCXKG6RN4ONIEPCMNFB732A436Z5PNDSRLGWK7GBLCMQLIFO4S7EYWVU"
+	asset := usdTrustLine.Data.TrustLine.Asset
+	for _, entry := range []xdr.LedgerEntry{account1, account2} {
+		address := entry.Data.Account.AccountId.Address()
+		_, err := q.CreateAccountSigner(tt.Ctx, address, signer, 1, nil)
+		tt.Assert.NoError(err)
+
+		trustLine := usdTrustLine
+		trustLineEntry := *usdTrustLine.Data.TrustLine
+		trustLineEntry.AccountId = entry.Data.Account.AccountId
+		trustLine.Data.TrustLine = &trustLineEntry
+		_, err = q.InsertTrustLine(tt.Ctx, trustLine)
+		tt.Assert.NoError(err)
+	}
+
+	for _, load := range []func(db2.PageQuery) ([]AccountEntry, error){
+		func(pq db2.PageQuery) ([]AccountEntry, error) {
+			return q.AccountEntriesForSigner(tt.Ctx, signer, pq)
+		},
+		func(pq db2.PageQuery) ([]AccountEntry, error) {
+			return q.AccountsForAsset(tt.Ctx, asset, pq)
+		},
+	} {
+		pq := db2.PageQuery{Order: db2.OrderAscending, Limit: 1, Cursor: "0"}
+		accounts, err := load(pq)
+		tt.Assert.NoError(err)
+		if !tt.Assert.Len(accounts, 1) {
+			continue
+		}
+		tt.Assert.Equal(account2.Data.Account.AccountId.Address(), accounts[0].AccountID)
+
+		pq.Cursor = AccountRowIDCursor(accounts[0])
+		accounts, err = load(pq)
+		tt.Assert.NoError(err)
+		if !tt.Assert.Len(accounts, 1) {
+			continue
+		}
+		tt.Assert.Equal(account1.Data.Account.AccountId.Address(), accounts[0].AccountID)
+
+		pq.Cursor = AccountRowIDCursor(accounts[0])
+		accounts, err = load(pq)
+		tt.Assert.NoError(err)
+		tt.Assert.Len(accounts, 0)
+
+		pq.Order = db2.OrderDescending
+		accounts, err = load(pq)
+		tt.Assert.NoError(err)
+		if tt.Assert.Len(accounts, 1) {
+			tt.Assert.Equal(account2.Data.Account.AccountId.Address(), accounts[0].AccountID)
+		}
+
+		// the string cursor still pages by account id
+		pq = db2.PageQuery{
+			Order:  db2.OrderAscending,
+			Limit:  1,
+			Cursor: account1.Data.Account.AccountId.Address(),
+		}
+		accounts, err = load(pq)
+		tt.Assert.NoError(err)
+		if tt.Assert.Len(accounts, 1) {
+			tt.Assert.Equal(account2.Data.Account.AccountId.Address(), accounts[0].AccountID)
+		}
+	}
+}
+
+func TestParseAccountRowIDCursor(t *testing.T) {
+	rowID, ok := ParseAccountRowIDCursor("42")
+	assert.True(t, ok)
+	assert.Equal(t, int64(42), rowID)
+	assert.Equal(t, "42", AccountRowIDCursor(AccountEntry{RowID: 42}))
+
+	for _, cursor := range []string{"", "-1", "GAOQJGUAB7NI7K7I62ORBXMN3J4SSWQUQ7FOEPSDJ322W2HMCNWPHXFB", "now"} {
+		_, ok = ParseAccountRowIDCursor(cursor)
+		assert.False(t, ok, cursor)
+	}
+}
+
+func TestAccountsForAssetWithBalance(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+	q := &Q{tt.HorizonSession()}
+
+	batch := q.NewAccountsBatchInsertBuilder(0)
+	tt.Assert.NoError(batch.Add(tt.Ctx, account1))
+	tt.Assert.NoError(batch.Add(tt.Ctx, account2))
+	tt.Assert.NoError(batch.Exec(tt.Ctx))
+
+	// account1 trusts USD but wasn't funded, account2 holds 10000
+	usdTrustLineWith := func(account xdr.AccountId, balance xdr.Int64) xdr.LedgerEntry {
+		entry := usdTrustLine
+		trustLine := *usdTrustLine.Data.TrustLine
+		trustLine.AccountId = account
+		trustLine.Balance = balance
+		entry.Data.TrustLine = &trustLine
+		return entry
+	}
+	for _, entry := range []xdr.LedgerEntry{
+		usdTrustLineWith(account1.Data.Account.AccountId, 0),
+		usdTrustLineWith(account2.Data.Account.AccountId, 10000),
+	} {
+		_, err := q.InsertTrustLine(tt.Ctx, entry)
+		tt.Assert.NoError(err)
+	}
+
+	pq := db2.PageQuery{Order: db2.OrderAscending, Limit: db2.DefaultPageSize}
+	usd := usdTrustLine.Data.TrustLine.Asset
+
+	accounts, err := q.AccountsForAssetWithBalance(tt.Ctx, usd, 0, pq)
+	tt.Assert.NoError(err)
+	if tt.Assert.Len(accounts, 1) {
+		tt.Assert.Equal(account1.Data.Account.AccountId.Address(), accounts[0].AccountID)
+	}
+
+	accounts, err = q.AccountsForAssetWithBalance(tt.Ctx, usd, 10000, pq)
+	tt.Assert.NoError(err)
+	if tt.Assert.Len(accounts, 1) {
+		tt.Assert.Equal(account2.Data.Account.AccountId.Address(), accounts[0].AccountID)
+	}
+
+	accounts, err = q.AccountsForAssetWithBalance(tt.Ctx, eurTrustLine.Data.TrustLine.Asset, 0, pq)
+	tt.Assert.NoError(err)
+	tt.Assert.Empty(accounts)
+}
+
+func TestExplainAccountsForAsset(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+	q := &Q{tt.HorizonSession()}
+
+	pq := db2.PageQuery{
+		Order:  db2.OrderAscending,
+		Limit:  db2.DefaultPageSize,
+		Cursor: "",
+	}
+
+	explanation, err := q.ExplainAccountsForAsset(tt.Ctx, eurTrustLine.Data.TrustLine.Asset, pq, false)
+	tt.Assert.NoError(err)
+	tt.Assert.Contains(explanation.SQL, "FROM accounts JOIN trust_lines ON accounts.account_id = trust_lines.account_id")
+	tt.Assert.Contains(explanation.SQL, "trust_lines.asset_code = $1")
+	tt.Assert.Contains(explanation.SQL, "ORDER BY trust_lines.account_id asc LIMIT 10")
+	tt.Assert.Equal([]interface{}{"EUR", trustLineIssuer.Address(), int32(1)}, explanation.Args)
+	tt.Assert.Empty(explanation.Plan)
+
+	explanation, err = q.ExplainAccountsForAsset(tt.Ctx, eurTrustLine.Data.TrustLine.Asset, pq, true)
+	tt.Assert.NoError(err)
+	tt.Assert.NotEmpty(explanation.Plan)
+}
+
+func TestAccountsForAssetBindsAssetCode(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+	q := &Q{tt.HorizonSession()}
+
+	batch := q.NewAccountsBatchInsertBuilder(0)
+	tt.Assert.NoError(batch.Add(tt.Ctx, account1))
+	tt.Assert.NoError(batch.Exec(tt.Ctx))
+	trustLine := eurTrustLine
+	trustLineEntry := *eurTrustLine.Data.TrustLine
+	trustLineEntry.AccountId = account1.Data.Account.AccountId
+	trustLine.Data.TrustLine = &trustLineEntry
+	_, err := q.InsertTrustLine(tt.Ctx, trustLine)
+	tt.Assert.NoError(err)
+
+	// the asset is built directly to skip the validation of the code done
+	// by the actions
+	var code xdr.AssetCode12
+	copy(code[:], "x' OR '1'='1")
+	asset := xdr.Asset{
+		Type: xdr.AssetTypeAssetTypeCreditAlphanum12,
+		AlphaNum12: &xdr.AssetAlphaNum12{
+			AssetCode: code,
+			Issuer:    trustLineIssuer,
+		},
+	}
+
+	pq := db2.PageQuery{
+		Order:  db2.OrderAscending,
+		Limit:  db2.DefaultPageSize,
+		Cursor: "",
+	}
+
+	accounts, err := q.AccountsForAsset(tt.Ctx, asset, pq)
+	tt.Assert.NoError(err)
+	tt.Assert.Empty(accounts)
+
+	explanation, err := q.ExplainAccountsForAsset(tt.Ctx, asset, pq, false)
+	tt.Assert.NoError(err)
+	tt.Assert.NotContains(explanation.SQL, "x'")
+	tt.Assert.Contains(explanation.Args, "x' OR '1'='1")
+}
+
+func TestAccountsForAssets(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+	q := &Q{tt.HorizonSession()}
+
+	trustLineFor := func(base xdr.LedgerEntry, account xdr.LedgerEntry) xdr.LedgerEntry {
+		trustLine := *base.Data.TrustLine
+		trustLine.AccountId = account.Data.Account.AccountId
+		entry := base
+		entry.Data.TrustLine = &trustLine
+		return entry
+	}
+
+	batch := q.NewAccountsBatchInsertBuilder(0)
+	tt.Assert.NoError(batch.Add(tt.Ctx, account1))
+	tt.Assert.NoError(batch.Add(tt.Ctx, account2))
+	tt.Assert.NoError(batch.Add(tt.Ctx, account3))
+	tt.Assert.NoError(batch.Exec(tt.Ctx))
+
+	// account1 holds EUR and USD, account2 only USD and account3 only EUR
+	for _, entry := range []xdr.LedgerEntry{
+		trustLineFor(eurTrustLine, account1),
+		trustLineFor(usdTrustLine, account1),
+		trustLineFor(usdTrustLine, account2),
+		trustLineFor(eurTrustLine, account3),
+	} {
+		_, err := q.InsertTrustLine(tt.Ctx, entry)
+		tt.Assert.NoError(err)
+	}
+
+	assets := []xdr.Asset{
+		usdTrustLine.Data.TrustLine.Asset,
+		eurTrustLine.Data.TrustLine.Asset,
+	}
+	pq := db2.PageQuery{
+		Order:  db2.OrderAscending,
+		Limit:  db2.DefaultPageSize,
+		Cursor: "",
+	}
+	accountIDs := func(accounts []AccountEntry) []string {
+		ids := []string{}
+		for _, account := range accounts {
+			ids = append(ids, account.AccountID)
+		}
+		return ids
+	}
+
+	accounts, err := q.AccountsForAssets(tt.Ctx, assets, MatchAnyAsset, pq)
+	tt.Assert.NoError(err)
+	tt.Assert.ElementsMatch([]string{
+		account1.Data.Account.AccountId.Address(),
+		account2.Data.Account.AccountId.Address(),
+		account3.Data.Account.AccountId.Address(),
+	}, accountIDs(accounts))
+
+	accounts, err = q.AccountsForAssets(tt.Ctx, assets, MatchAllAssets, pq)
+	tt.Assert.NoError(err)
+	tt.Assert.Equal([]string{account1.Data.Account.AccountId.Address()}, accountIDs(accounts))
+
+	// repeated assets are only counted once
+	accounts, err = q.AccountsForAssets(tt.Ctx, append(assets, assets[0]), MatchAllAssets, pq)
+	tt.Assert.NoError(err)
+	tt.Assert.Equal([]string{account1.Data.Account.AccountId.Address()}, accountIDs(accounts))
+
+	pq.Cursor = account1.Data.Account.AccountId.Address()
+	accounts, err = q.AccountsForAssets(tt.Ctx, assets, MatchAllAssets, pq)
+	tt.Assert.NoError(err)
+	tt.Assert.Len(accounts, 0)
+
+	_, err = q.AccountsForAssets(tt.Ctx, assets, AssetsMatchMode("some"), pq)
+	tt.Assert.EqualError(err, "invalid assets match mode: some")
+}
+
+func TestAccountsWithWeakThresholds(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+	q := &Q{tt.HorizonSession()}
+
+	// account2 with a master weight higher than all its thresholds
+	weakAccount := account2
+	weakAccountEntry := *account2.Data.Account
+	weakAccountEntry.Thresholds = xdr.Thresholds{9, 6, 7, 8}
+	weakAccount.Data.Account = &weakAccountEntry
+
+	batch := q.NewAccountsBatchInsertBuilder(0)
+	// account1's thresholds are {1, 2, 3, 4}
+	tt.Assert.NoError(batch.Add(tt.Ctx, account1))
+	tt.Assert.NoError(batch.Add(tt.Ctx, weakAccount))
+	tt.Assert.NoError(batch.Exec(tt.Ctx))
+
+	pq := db2.PageQuery{
+		Order:  db2.OrderAscending,
+		Limit:  db2.DefaultPageSize,
+		Cursor: "",
+	}
+	accounts, err := q.AccountsWithWeakThresholds(tt.Ctx, pq)
+	tt.Assert.NoError(err)
+	tt.Assert.Len(accounts, 1)
+	tt.Assert.Equal(account2.Data.Account.AccountId.Address(), accounts[0].AccountID)
+
+	pq.Cursor = account2.Data.Account.AccountId.Address()
+	accounts, err = q.AccountsWithWeakThresholds(tt.Ctx, pq)
+	tt.Assert.NoError(err)
+	tt.Assert.Len(accounts, 0)
+}
+
+func TestAccountsWithThreshold(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+	q := &Q{tt.HorizonSession()}
+
+	// the medium threshold of account1 is 3, the one of account2 is 7
+	batch := q.NewAccountsBatchInsertBuilder(0)
+	tt.Assert.NoError(batch.Add(tt.Ctx, account1))
+	tt.Assert.NoError(batch.Add(tt.Ctx, account2))
+	tt.Assert.NoError(batch.Exec(tt.Ctx))
+
+	pq := db2.PageQuery{
+		Order:  db2.OrderAscending,
+		Limit:  db2.DefaultPageSize,
+		Cursor: "",
+	}
+	accountIDs := func(accounts []AccountEntry) []string {
+		ids := []string{}
+		for _, account := range accounts {
+			ids = append(ids, account.AccountID)
+		}
+		return ids
+	}
+
+	accounts, err := q.AccountsWithThreshold(tt.Ctx, "med", ">=", 2, pq)
+	tt.Assert.NoError(err)
+	tt.Assert.Equal([]string{
+		account1.Data.Account.AccountId.Address(),
+		account2.Data.Account.AccountId.Address(),
+	}, accountIDs(accounts))
+
+	accounts, err = q.AccountsWithThreshold(tt.Ctx, "med", ">=", 4, pq)
+	tt.Assert.NoError(err)
+	tt.Assert.Equal([]string{account2.Data.Account.AccountId.Address()}, accountIDs(accounts))
+
+	accounts, err = q.AccountsWithThreshold(tt.Ctx, "med", "<=", 3, pq)
+	tt.Assert.NoError(err)
+	tt.Assert.Equal([]string{account1.Data.Account.AccountId.Address()}, accountIDs(accounts))
+
+	accounts, err = q.AccountsWithThreshold(tt.Ctx, "high", ">=", 9, pq)
+	tt.Assert.NoError(err)
+	tt.Assert.Len(accounts, 0)
+
+	_, err = q.AccountsWithThreshold(tt.Ctx, "master", ">=", 1, pq)
+	tt.Assert.EqualError(err, "invalid threshold: master")
+	_, err = q.AccountsWithThreshold(tt.Ctx, "med", ">", 1, pq)
+	tt.Assert.EqualError(err, "invalid threshold operator: >")
+}
+
+func TestAccountsWithDataValue(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+	q := &Q{tt.HorizonSession()}
+
+	batch := q.NewAccountsBatchInsertBuilder(0)
+	tt.Assert.NoError(batch.Add(tt.Ctx, account1))
+	tt.Assert.NoError(batch.Add(tt.Ctx, account2))
+	tt.Assert.NoError(batch.Add(tt.Ctx, account3))
+	tt.Assert.NoError(batch.Exec(tt.Ctx))
+
+	// account1 and account2 share the label, account3 has another one
+	label := func(account xdr.LedgerEntry, name string, value []byte) xdr.LedgerEntry {
+		entry := data1
+		dataEntry := *data1.Data.Data
+		dataEntry.AccountId = account.Data.Account.AccountId
+		dataEntry.DataName = xdr.String64(name)
+		dataEntry.DataValue = value
+		entry.Data.Data = &dataEntry
+		return entry
+	}
+	for _, entry := range []xdr.LedgerEntry{
+		label(account1, "label", []byte("exchange")),
+		label(account2, "label", []byte("exchange")),
+		label(account3, "label", []byte("wallet")),
+		label(account3, "other", []byte("exchange")),
+	} {
+		_, err := q.InsertAccountData(tt.Ctx, entry)
+		tt.Assert.NoError(err)
+	}
+
+	pq := db2.PageQuery{
+		Order:  db2.OrderAscending,
+		Limit:  db2.DefaultPageSize,
+		Cursor: "",
+	}
+	accounts, err := q.AccountsWithDataValue(tt.Ctx, "label", []byte("exchange"), pq)
+	tt.Assert.NoError(err)
+	if tt.Assert.Len(accounts, 2) {
+		tt.Assert.Equal(account1.Data.Account.AccountId.Address(), accounts[0].AccountID)
+		tt.Assert.Equal(account2.Data.Account.AccountId.Address(), accounts[1].AccountID)
+	}
+
+	pq.Cursor = account1.Data.Account.AccountId.Address()
+	accounts, err = q.AccountsWithDataValue(tt.Ctx, "label", []byte("exchange"), pq)
+	tt.Assert.NoError(err)
+	if tt.Assert.Len(accounts, 1) {
+		tt.Assert.Equal(account2.Data.Account.AccountId.Address(), accounts[0].AccountID)
+	}
+
+	pq.Cursor = ""
+	accounts, err = q.AccountsWithDataValue(tt.Ctx, "label", []byte("bank"), pq)
+	tt.Assert.NoError(err)
+	tt.Assert.Len(accounts, 0)
+}
+
+func TestAccountsWithoutHomeDomain(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+	q := &Q{tt.HorizonSession()}
+
+	// account1 and account2 claim a domain, account3 doesn't
+	batch := q.NewAccountsBatchInsertBuilder(0)
+	tt.Assert.NoError(batch.Add(tt.Ctx, account1))
+	tt.Assert.NoError(batch.Add(tt.Ctx, account2))
+	tt.Assert.NoError(batch.Add(tt.Ctx, account3))
+	tt.Assert.NoError(batch.Exec(tt.Ctx))
+
+	pq := db2.PageQuery{
+		Order:  db2.OrderAscending,
+		Limit:  db2.DefaultPageSize,
+		Cursor: "",
+	}
+	accounts, err := q.AccountsWithoutHomeDomain(tt.Ctx, pq)
+	tt.Assert.NoError(err)
+	if tt.Assert.Len(accounts, 1) {
+		tt.Assert.Equal(account3.Data.Account.AccountId.Address(), accounts[0].AccountID)
+	}
+
+	pq.Cursor = account3.Data.Account.AccountId.Address()
+	accounts, err = q.AccountsWithoutHomeDomain(tt.Ctx, pq)
+	tt.Assert.NoError(err)
+	tt.Assert.Len(accounts, 0)
+}
+
+func TestAccountsForHomeDomain(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+	q := &Q{tt.HorizonSession()}
+
+	batch := q.NewAccountsBatchInsertBuilder(0)
+	tt.Assert.NoError(batch.Add(tt.Ctx, account1))
+	tt.Assert.NoError(batch.Add(tt.Ctx, account2))
+	tt.Assert.NoError(batch.Add(tt.Ctx, account3))
+	tt.Assert.NoError(batch.Exec(tt.Ctx))
+
+	pq := db2.PageQuery{
+		Order:  db2.OrderAscending,
+		Limit:  db2.DefaultPageSize,
+		Cursor: "",
+	}
+	// account2's meridian.stellar.org is not an exact match
+	accounts, err := q.AccountsForHomeDomain(tt.Ctx, "stellar.org", pq)
+	tt.Assert.NoError(err)
+	if tt.Assert.Len(accounts, 1) {
+		tt.Assert.Equal(account1.Data.Account.AccountId.Address(), accounts[0].AccountID)
+	}
+
+	accounts, err = q.AccountsForHomeDomain(tt.Ctx, "example.com", pq)
+	tt.Assert.NoError(err)
+	tt.Assert.Len(accounts, 0)
+}
+
+func TestAccountsWithSignerType(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+	q := &Q{tt.HorizonSession()}
+
+	batch := q.NewAccountsBatchInsertBuilder(0)
+	tt.Assert.NoError(batch.Add(tt.Ctx, account1))
+	tt.Assert.NoError(batch.Add(tt.Ctx, account2))
+	tt.Assert.NoError(batch.Add(tt.Ctx, account3))
+	tt.Assert.NoError(batch.Exec(tt.Ctx))
+
+	// every account has its master key, only account2 has a preauth_tx
+	// signer
+	for _, account := range []xdr.LedgerEntry{account1, account2, account3} {
+		address := account.Data.Account.AccountId.Address()
+		_, err := q.CreateAccountSigner(tt.Ctx, address, address, 1, nil)
+		tt.Assert.NoError(err)
+	}
+	preauthTxSigner := "TD3VSDLM5OANOIUKL3EYZ4OEWSMIMRWA7HNUVSZ2E43HS7AU3AEFDVTN"
+	_, err := q.CreateAccountSigner(tt.Ctx, account2.Data.Account.AccountId.Address(), preauthTxSigner, 1, nil)
+	tt.Assert.NoError(err)
+
+	pq := db2.PageQuery{
+		Order:  db2.OrderAscending,
+		Limit:  db2.DefaultPageSize,
+		Cursor: "",
+	}
+	accounts, err := q.AccountsWithSignerType(tt.Ctx, "preauth_tx", pq)
+	tt.Assert.NoError(err)
+	if tt.Assert.Len(accounts, 1) {
+		tt.Assert.Equal(account2.Data.Account.AccountId.Address(), accounts[0].AccountID)
+	}
+
+	accounts, err = q.AccountsWithSignerType(tt.Ctx, "ed25519_public_key", pq)
+	tt.Assert.NoError(err)
+	tt.Assert.Len(accounts, 3)
+
+	accounts, err = q.AccountsWithSignerType(tt.Ctx, "sha256_hash", pq)
+	tt.Assert.NoError(err)
+	tt.Assert.Len(accounts, 0)
+
+	_, err = q.AccountsWithSignerType(tt.Ctx, "unknown", pq)
+	tt.Assert.EqualError(err, "unknown signer type: unknown")
+}
+
+func TestAccountsWithIDPrefix(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+	q := &Q{tt.HorizonSession()}
+
+	// twin shares the first 51 characters of account1's id
+	twin := account1
+	twinEntry := *account1.Data.Account
+	twinEntry.AccountId = xdr.MustAddress("GAOQJGUAB7NI7K7I62ORBXMN3J4SSWQUQ7FOEPSDJ322W2HMCNWKNDFL")
+	twin.Data.Account = &twinEntry
+
+	batch := q.NewAccountsBatchInsertBuilder(0)
+	tt.Assert.NoError(batch.Add(tt.Ctx, account1))
+	tt.Assert.NoError(batch.Add(tt.Ctx, twin))
+	tt.Assert.NoError(batch.Add(tt.Ctx, account2))
+	tt.Assert.NoError(batch.Exec(tt.Ctx))
+
+	account1ID := account1.Data.Account.AccountId.Address()
+	twinID := twinEntry.AccountId.Address()
+	for _, testCase := range []struct {
+		prefix   string
+		limit    int
+		expected []string
+	}{
+		{"GAOQJ", 10, []string{twinID, account1ID}},
+		{"GAOQJ", 1, []string{twinID}},
+		{account1ID[:52], 10, []string{account1ID}},
+		{account1ID, 10, []string{account1ID}},
+		{"GCT2N", 10, []string{account2.Data.Account.AccountId.Address()}},
+		{"GDPGO", 10, nil},
+	} {
+		accountIDs, err := q.AccountsWithIDPrefix(tt.Ctx, testCase.prefix, testCase.limit)
+		tt.Assert.NoError(err)
+		tt.Assert.Equal(testCase.expected, accountIDs)
+	}
+
+	_, err := q.AccountsWithIDPrefix(tt.Ctx, "GAOQ", 10)
+	tt.Assert.EqualError(err, "prefix must be at least 5 characters long")
+}
+
+func TestAccountsWithLiabilities(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+	q := &Q{tt.HorizonSession()}
+
+	// account2 without liabilities
+	noLiabilitiesAccount := account2
+	noLiabilitiesAccountEntry := *account2.Data.Account
+	noLiabilitiesAccountEntry.Ext = xdr.AccountEntryExt{}
+	noLiabilitiesAccount.Data.Account = &noLiabilitiesAccountEntry
+
+	// account3 without liabilities in its native balance but with liabilities
+	// in a trust line
+	traderAccount := account3
+	traderAccountEntry := *account3.Data.Account
+	traderAccountEntry.Ext = xdr.AccountEntryExt{}
+	traderAccount.Data.Account = &traderAccountEntry
+	traderTrustLine := eurTrustLine
+	traderTrustLineEntry := *eurTrustLine.Data.TrustLine
+	traderTrustLineEntry.AccountId = account3.Data.Account.AccountId
+	traderTrustLine.Data.TrustLine = &traderTrustLineEntry
+
+	batch := q.NewAccountsBatchInsertBuilder(0)
+	// account1's liabilities are 3/4
+	tt.Assert.NoError(batch.Add(tt.Ctx, account1))
+	tt.Assert.NoError(batch.Add(tt.Ctx, noLiabilitiesAccount))
+	tt.Assert.NoError(batch.Add(tt.Ctx, traderAccount))
+	tt.Assert.NoError(batch.Exec(tt.Ctx))
+	_, err := q.InsertTrustLine(tt.Ctx, traderTrustLine)
+	tt.Assert.NoError(err)
+
+	pq := db2.PageQuery{
+		Order:  db2.OrderAscending,
+		Limit:  db2.DefaultPageSize,
+		Cursor: "",
+	}
+	accounts, err := q.AccountsWithLiabilities(tt.Ctx, pq)
+	tt.Assert.NoError(err)
+	tt.Assert.Len(accounts, 2)
+	tt.Assert.Equal(account1.Data.Account.AccountId.Address(), accounts[0].AccountID)
+	tt.Assert.Equal(account3.Data.Account.AccountId.Address(), accounts[1].AccountID)
+
+	pq.Cursor = account1.Data.Account.AccountId.Address()
+	accounts, err = q.AccountsWithLiabilities(tt.Ctx, pq)
+	tt.Assert.NoError(err)
+	tt.Assert.Len(accounts, 1)
+	tt.Assert.Equal(account3.Data.Account.AccountId.Address(), accounts[0].AccountID)
+}
+
 func TestAccountsForSponsor(t *testing.T) {
 	tt := test.Start(t)
 	defer tt.Finish()
@@ -518,3 +1210,249 @@ func TestGetAccountByID(t *testing.T) {
 	assert.Equal(t, int64(3), resultAccount.BuyingLiabilities)
 	assert.Equal(t, int64(4), resultAccount.SellingLiabilities)
 }
+
+func TestAccountFromCore(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+	q := &Q{tt.HorizonSession()}
+
+	batch := q.NewAccountsBatchInsertBuilder(0)
+	assert.NoError(t, batch.Add(tt.Ctx, account1))
+	assert.NoError(t, batch.Add(tt.Ctx, account2))
+	assert.NoError(t, batch.Exec(tt.Ctx))
+
+	for _, entry := range []xdr.LedgerEntry{account1, account2} {
+		resultAccount, err := q.GetAccountByID(tt.Ctx, entry.Data.Account.AccountId.Address())
+		assert.NoError(t, err)
+
+		converted := AccountFromCore(entry)
+		assert.Equal(t, resultAccount.AccountID, converted.AccountID)
+		assert.Equal(t, resultAccount.Balance, converted.Balance)
+		assert.Equal(t, resultAccount.BuyingLiabilities, converted.BuyingLiabilities)
+		assert.Equal(t, resultAccount.SellingLiabilities, converted.SellingLiabilities)
+		assert.Equal(t, resultAccount.SequenceNumber, converted.SequenceNumber)
+		assert.Equal(t, resultAccount.NumSubEntries, converted.NumSubEntries)
+		assert.Equal(t, resultAccount.InflationDestination, converted.InflationDestination)
+		assert.Equal(t, resultAccount.HomeDomain, converted.HomeDomain)
+		assert.Equal(t, resultAccount.Flags, converted.Flags)
+		assert.Equal(t, resultAccount.MasterWeight, converted.MasterWeight)
+		assert.Equal(t, resultAccount.ThresholdLow, converted.ThresholdLow)
+		assert.Equal(t, resultAccount.ThresholdMedium, converted.ThresholdMedium)
+		assert.Equal(t, resultAccount.ThresholdHigh, converted.ThresholdHigh)
+		assert.Equal(t, resultAccount.LastModifiedLedger, converted.LastModifiedLedger)
+		assert.Equal(t, resultAccount.Sponsor, converted.Sponsor)
+		assert.Equal(t, resultAccount.NumSponsored, converted.NumSponsored)
+		assert.Equal(t, resultAccount.NumSponsoring, converted.NumSponsoring)
+		// the row id is assigned by the database, core entries don't have one
+		assert.NotZero(t, resultAccount.RowID)
+		assert.Zero(t, converted.RowID)
+		converted.RowID = resultAccount.RowID
+		assert.Equal(t, resultAccount, converted)
+	}
+}
+
+func TestAccountColumnsAllowlist(t *testing.T) {
+	var fields []string
+	entryType := reflect.TypeOf(AccountEntry{})
+	for i := 0; i < entryType.NumField(); i++ {
+		fields = append(fields, entryType.Field(i).Tag.Get("db"))
+	}
+
+	// every column loaded into AccountEntry must be explicitly allowed
+	assert.ElementsMatch(t, fields, accountColumns)
+}
+
+func TestAccountQueriesIgnoreUnlistedColumns(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+	q := &Q{tt.HorizonSession()}
+
+	_, err := q.ExecRaw(tt.Ctx, "ALTER TABLE accounts ADD COLUMN internal_notes text DEFAULT 'secret'")
+	tt.Assert.NoError(err)
+
+	batch := q.NewAccountsBatchInsertBuilder(0)
+	tt.Assert.NoError(batch.Add(tt.Ctx, account1))
+	tt.Assert.NoError(batch.Exec(tt.Ctx))
+
+	accounts, err := q.GetAccountsByIDs(tt.Ctx, []string{account1.Data.Account.AccountId.Address()})
+	tt.Assert.NoError(err)
+	tt.Assert.Len(accounts, 1)
+
+	_, err = q.CreateAccountSigner(tt.Ctx, account1.Data.Account.AccountId.Address(), account1.Data.Account.AccountId.Address(), 1, nil)
+	tt.Assert.NoError(err)
+	accounts, err = q.AccountEntriesForSigner(
+		tt.Ctx,
+		account1.Data.Account.AccountId.Address(),
+		db2.PageQuery{Order: "asc", Limit: 10},
+	)
+	tt.Assert.NoError(err)
+	tt.Assert.Len(accounts, 1)
+}
+
+func TestAccountsForAllSigners(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+	q := &Q{tt.HorizonSession()}
+
+	address1 := account1.Data.Account.AccountId.Address()
+	address2 := account2.Data.Account.AccountId.Address()
+	address3 := account3.Data.Account.AccountId.Address()
+
+	batch := q.NewAccountsBatchInsertBuilder(0)
+	tt.Assert.NoError(batch.Add(tt.Ctx, account1))
+	tt.Assert.NoError(batch.Add(tt.Ctx, account2))
+	tt.Assert.NoError(batch.Add(tt.Ctx, account3))
+	tt.Assert.NoError(batch.Exec(tt.Ctx))
+
+	// address3 signs for every account but only account1 is also signed by
+	// address1
+	for _, signer := range []AccountSigner{
+		{Account: address1, Signer: address1},
+		{Account: address1, Signer: address3},
+		{Account: address2, Signer: address2},
+		{Account: address2, Signer: address3},
+		{Account: address3, Signer: address3},
+	} {
+		_, err := q.CreateAccountSigner(tt.Ctx, signer.Account, signer.Signer, 1, nil)
+		tt.Assert.NoError(err)
+	}
+
+	pq := db2.PageQuery{
+		Order:  db2.OrderAscending,
+		Limit:  db2.DefaultPageSize,
+		Cursor: "",
+	}
+	for _, testCase := range []struct {
+		signers  []string
+		expected []string
+	}{
+		{[]string{address1, address3}, []string{address1}},
+		{[]string{address3, address1, address1}, []string{address1}},
+		{[]string{address2, address3}, []string{address2}},
+		{[]string{address3}, []string{address1, address2, address3}},
+		{[]string{address1, address2}, []string{}},
+	} {
+		accounts, err := q.AccountsForAllSigners(tt.Ctx, testCase.signers, pq)
+		tt.Assert.NoError(err)
+		ids := []string{}
+		for _, account := range accounts {
+			ids = append(ids, account.AccountID)
+		}
+		tt.Assert.Equal(testCase.expected, ids)
+	}
+
+	_, err := q.AccountsForAllSigners(tt.Ctx, []string{}, pq)
+	tt.Assert.EqualError(err, "at least one signer is required")
+}
+
+func TestAccountsModifiedBetween(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+	q := &Q{tt.HorizonSession()}
+
+	// account1 is modified at 1234, account2 and account3 at 1235
+	batch := q.NewAccountsBatchInsertBuilder(0)
+	tt.Assert.NoError(batch.Add(tt.Ctx, account1))
+	tt.Assert.NoError(batch.Add(tt.Ctx, account2))
+	tt.Assert.NoError(batch.Add(tt.Ctx, account3))
+	tt.Assert.NoError(batch.Exec(tt.Ctx))
+
+	pq := db2.PageQuery{
+		Order:  db2.OrderAscending,
+		Limit:  db2.DefaultPageSize,
+		Cursor: "",
+	}
+	for _, testCase := range []struct {
+		from, to uint32
+		expected []xdr.LedgerEntry
+	}{
+		{1234, 1234, []xdr.LedgerEntry{account1}},
+		{1235, 1235, []xdr.LedgerEntry{account2, account3}},
+		{1234, 1235, []xdr.LedgerEntry{account1, account2, account3}},
+		{1236, 2000, []xdr.LedgerEntry{}},
+	} {
+		accounts, err := q.AccountsModifiedBetween(tt.Ctx, testCase.from, testCase.to, pq)
+		tt.Assert.NoError(err)
+		ids := []string{}
+		for _, account := range accounts {
+			ids = append(ids, account.AccountID)
+		}
+		expected := []string{}
+		for _, entry := range testCase.expected {
+			expected = append(expected, entry.Data.Account.AccountId.Address())
+		}
+		tt.Assert.Equal(expected, ids)
+	}
+
+	// paging
+	pq.Limit = 1
+	pq.Cursor = account2.Data.Account.AccountId.Address()
+	accounts, err := q.AccountsModifiedBetween(tt.Ctx, 1234, 1235, pq)
+	tt.Assert.NoError(err)
+	tt.Assert.Len(accounts, 1)
+	tt.Assert.Equal(account3.Data.Account.AccountId.Address(), accounts[0].AccountID)
+}
+
+func TestCountSubentriesForAccounts(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+	q := &Q{tt.HorizonSession()}
+
+	address1 := account1.Data.Account.AccountId.Address()
+	address2 := account2.Data.Account.AccountId.Address()
+	address3 := account3.Data.Account.AccountId.Address()
+
+	// account1 has two signers, a trust line and two data entries, account2
+	// has a signer and a trust line and account3 has nothing
+	for _, signer := range []AccountSigner{
+		{Account: address1, Signer: address1, Weight: 1},
+		{Account: address1, Signer: address2, Weight: 2},
+		{Account: address2, Signer: address2, Weight: 3},
+	} {
+		_, err := q.CreateAccountSigner(tt.Ctx, signer.Account, signer.Signer, signer.Weight, nil)
+		tt.Assert.NoError(err)
+	}
+	usdEntry := usdTrustLine
+	trustLine := *usdTrustLine.Data.TrustLine
+	trustLine.AccountId = account2.Data.Account.AccountId
+	usdEntry.Data.TrustLine = &trustLine
+	for _, entry := range []xdr.LedgerEntry{eurTrustLine, usdEntry} {
+		_, err := q.InsertTrustLine(tt.Ctx, entry)
+		tt.Assert.NoError(err)
+	}
+	for _, entry := range []xdr.LedgerEntry{data1, data2} {
+		_, err := q.InsertAccountData(tt.Ctx, entry)
+		tt.Assert.NoError(err)
+	}
+
+	accounts := []string{address1, address2, address3}
+	signers, err := q.CountSignersForAccounts(tt.Ctx, accounts)
+	tt.Assert.NoError(err)
+	tt.Assert.Equal(map[string]AccountSignersCount{
+		address1: {Count: 2, TotalWeight: 3},
+		address2: {Count: 1, TotalWeight: 3},
+	}, signers)
+
+	trustLines, err := q.CountTrustLinesForAccounts(tt.Ctx, accounts)
+	tt.Assert.NoError(err)
+	tt.Assert.Equal(map[string]int{address1: 1, address2: 1}, trustLines)
+
+	data, err := q.CountDataForAccounts(tt.Ctx, accounts)
+	tt.Assert.NoError(err)
+	tt.Assert.Equal(map[string]int{address1: 2}, data)
+
+	for address, expected := range map[string]AccountSubentriesSummary{
+		address1: {NumSigners: 2, NumTrustLines: 1, NumData: 2, LastModifiedLedger: 1234},
+		address2: {NumSigners: 1, NumTrustLines: 1, LastModifiedLedger: 1235},
+		address3: {},
+	} {
+		summary, err := q.SummarizeAccountSubentries(tt.Ctx, address)
+		tt.Assert.NoError(err)
+		tt.Assert.Equal(expected, summary)
+	}
+}
@@ -172,6 +172,22 @@ func (q *EffectsQ) Select(ctx context.Context, dest interface{}) error {
 	return q.Err
 }
 
+// RecentSignerChanges loads the latest signer created, updated and removed
+// effects of the account, up to limit, latest first.
+func (q *Q) RecentSignerChanges(ctx context.Context, account string, limit uint64) ([]Effect, error) {
+	sql := selectEffect.
+		Where(sq.Eq{
+			"hacc.address": account,
+			"heff.type":    []EffectType{EffectSignerCreated, EffectSignerUpdated, EffectSignerRemoved},
+		}).
+		OrderBy("heff.history_operation_id desc, heff.order desc").
+		Limit(limit)
+
+	var effects []Effect
+	err := q.Select(ctx, &effects, sql)
+	return effects, err
+}
+
 // QEffects defines history_effects related queries.
 type QEffects interface {
 	QCreateAccountsHistory
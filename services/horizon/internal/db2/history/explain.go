@@ -0,0 +1,41 @@
+package history
+
+import (
+	"context"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"github.com/stellar/go/support/errors"
+)
+
+// QueryExplanation describes a query without running it.
+type QueryExplanation struct {
+	// SQL is the query using postgres placeholders ($1, $2...).
+	SQL  string
+	Args []interface{}
+	// Plan is the output of EXPLAIN for the query, only set when requested.
+	Plan []string
+}
+
+// explain builds the QueryExplanation of query. EXPLAIN (without ANALYZE)
+// only plans the query, so the query is never executed.
+func (q *Q) explain(ctx context.Context, query sq.SelectBuilder, withPlan bool) (QueryExplanation, error) {
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return QueryExplanation{}, errors.Wrap(err, "could not build query")
+	}
+
+	explanation := QueryExplanation{Args: args}
+	explanation.SQL, err = sq.Dollar.ReplacePlaceholders(sql)
+	if err != nil {
+		return QueryExplanation{}, errors.Wrap(err, "could not replace placeholders")
+	}
+
+	if withPlan {
+		if err = q.SelectRaw(ctx, &explanation.Plan, "EXPLAIN "+sql, args...); err != nil {
+			return QueryExplanation{}, errors.Wrap(err, "could not explain query")
+		}
+	}
+
+	return explanation, nil
+}
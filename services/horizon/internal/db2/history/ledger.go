@@ -50,6 +50,33 @@ func (q *Q) LedgersBySequence(ctx context.Context, dest interface{}, seqs ...int
 	return q.Select(ctx, dest, sql)
 }
 
+// LedgerCloseTimes loads the close times of the ledgers identified by the
+// sequences `seqs` in a single query. Sequences of ledgers not found in the
+// history are absent from the returned map.
+func (q *Q) LedgerCloseTimes(ctx context.Context, seqs []uint32) (map[uint32]time.Time, error) {
+	closeTimes := make(map[uint32]time.Time, len(seqs))
+	if len(seqs) == 0 {
+		return closeTimes, nil
+	}
+
+	sql := sq.Select("sequence", "closed_at").
+		From("history_ledgers").
+		Where(map[string]interface{}{"sequence": seqs})
+
+	var rows []struct {
+		Sequence uint32    `db:"sequence"`
+		ClosedAt time.Time `db:"closed_at"`
+	}
+	if err := q.Select(ctx, &rows, sql); err != nil {
+		return nil, errors.Wrap(err, "could not load ledger close times")
+	}
+
+	for _, row := range rows {
+		closeTimes[row.Sequence] = row.ClosedAt
+	}
+	return closeTimes, nil
+}
+
 // LedgerCapacityUsageStats returns ledger capacity stats for the last 5 ledgers.
 // Currently, we hard code the query to return the last 5 ledgers.
 // TODO: make the number of ledgers configurable.
@@ -55,6 +55,42 @@ func TestLedgerQueries(t *testing.T) {
 	}
 }
 
+func TestLedgerCloseTimes(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+	q := &Q{tt.HorizonSession()}
+
+	closeTimes, err := q.LedgerCloseTimes(tt.Ctx, []uint32{})
+	tt.Assert.NoError(err)
+	tt.Assert.Empty(closeTimes)
+
+	expected := map[uint32]time.Time{
+		1234: time.Unix(1600000000, 0).UTC(),
+		1235: time.Unix(1600000005, 0).UTC(),
+	}
+	for seq, closeTime := range expected {
+		_, err = q.InsertLedger(tt.Ctx, xdr.LedgerHeaderHistoryEntry{
+			Hash: xdr.Hash{byte(seq), byte(seq >> 8)},
+			Header: xdr.LedgerHeader{
+				LedgerSeq:          xdr.Uint32(seq),
+				PreviousLedgerHash: xdr.Hash{byte(seq - 1), byte((seq - 1) >> 8)},
+				ScpValue: xdr.StellarValue{
+					CloseTime: xdr.TimePoint(closeTime.Unix()),
+				},
+			},
+		}, 0, 0, 0, 0, 0)
+		tt.Assert.NoError(err)
+	}
+
+	closeTimes, err = q.LedgerCloseTimes(tt.Ctx, []uint32{1234, 1235, 1236})
+	tt.Assert.NoError(err)
+	tt.Assert.Len(closeTimes, 2)
+	for seq, closeTime := range expected {
+		tt.Assert.True(closeTime.Equal(closeTimes[seq]), "ledger %d", seq)
+	}
+}
+
 func TestInsertLedger(t *testing.T) {
 	tt := test.Start(t)
 	defer tt.Finish()
@@ -212,6 +212,11 @@ type AccountEntry struct {
 	Sponsor              null.String `db:"sponsor"`
 	NumSponsored         uint32      `db:"num_sponsored"`
 	NumSponsoring        uint32      `db:"num_sponsoring"`
+	// RowID is the internal id of the row, assigned by the database when
+	// the account is inserted. It isn't stable: an account removed and
+	// created again, or every account after the state tables are truncated
+	// and rebuilt, gets a new one.
+	RowID int64 `db:"row_id"`
 }
 
 type AccountsBatchInsertBuilder interface {
@@ -240,6 +245,7 @@ type IngestionQ interface {
 	CreateAssets(ctx context.Context, assets []xdr.Asset, batchSize int) (map[string]Asset, error)
 	QTransactions
 	QTrustLines
+	QTrustLineAuthorizations
 
 	Begin() error
 	BeginTx(*sql.TxOptions) error
@@ -715,6 +721,13 @@ type TrustLine struct {
 	Sponsor            null.String   `db:"sponsor"`
 }
 
+// TrustLineCountBucket is a bucket of the histogram of the number of trust
+// lines held by accounts.
+type TrustLineCountBucket struct {
+	Bucket   string `db:"bucket"`
+	Accounts int64  `db:"accounts"`
+}
+
 // QTrustLines defines trust lines related queries.
 type QTrustLines interface {
 	NewTrustLinesBatchInsertBuilder(maxBatchSize int) TrustLinesBatchInsertBuilder
@@ -730,6 +743,24 @@ type TrustLinesBatchInsertBuilder interface {
 	Exec(ctx context.Context) error
 }
 
+// TrustLineAuthorization is a row of data from the
+// `history_trust_lines_authorizations` table, recording the authorization
+// flags of a trust line at the ledger they were set.
+type TrustLineAuthorization struct {
+	LedgerSequence uint32        `db:"ledger_sequence"`
+	AccountID      string        `db:"account_id"`
+	AssetType      xdr.AssetType `db:"asset_type"`
+	AssetIssuer    string        `db:"asset_issuer"`
+	AssetCode      string        `db:"asset_code"`
+	Flags          uint32        `db:"flags"`
+}
+
+// QTrustLineAuthorizations defines trust line authorization history related
+// queries.
+type QTrustLineAuthorizations interface {
+	InsertTrustLineAuthorizations(ctx context.Context, ledgerSequence uint32, trustLines []xdr.LedgerEntry) error
+}
+
 // trustLinesBatchInsertBuilder is a simple wrapper around db.BatchInsertBuilder
 type trustLinesBatchInsertBuilder struct {
 	builder db.BatchInsertBuilder
@@ -823,6 +854,18 @@ func (q *Q) LatestLedgerBaseFeeAndSequence(ctx context.Context, dest interface{}
 	`)
 }
 
+// LatestLedgerBaseReserve loads the base reserve, in stroops, of the latest
+// known ledger.
+func (q *Q) LatestLedgerBaseReserve(ctx context.Context) (int32, error) {
+	var baseReserve int32
+	err := q.GetRaw(ctx, &baseReserve, `
+		SELECT base_reserve
+		FROM history_ledgers
+		WHERE sequence = (SELECT COALESCE(MAX(sequence), 0) FROM history_ledgers)
+	`)
+	return baseReserve, err
+}
+
 // CloneIngestionQ clones underlying db.Session and returns IngestionQ
 func (q *Q) CloneIngestionQ() IngestionQ {
 	return &Q{q.Clone()}
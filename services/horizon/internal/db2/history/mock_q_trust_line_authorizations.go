@@ -0,0 +1,20 @@
+package history
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+
+	"github.com/stellar/go/xdr"
+)
+
+// MockQTrustLineAuthorizations is a mock implementation of the
+// QTrustLineAuthorizations interface
+type MockQTrustLineAuthorizations struct {
+	mock.Mock
+}
+
+func (m *MockQTrustLineAuthorizations) InsertTrustLineAuthorizations(ctx context.Context, ledgerSequence uint32, trustLines []xdr.LedgerEntry) error {
+	a := m.Called(ctx, ledgerSequence, trustLines)
+	return a.Error(0)
+}
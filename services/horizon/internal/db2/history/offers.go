@@ -31,6 +31,20 @@ func (q *Q) CountOffers(ctx context.Context) (int, error) {
 	return count, nil
 }
 
+// CountOffersForAccount returns the number of open offers of the seller.
+func (q *Q) CountOffersForAccount(ctx context.Context, sellerID string) (int, error) {
+	sql := sq.Select("count(*)").From("offers").
+		Where("deleted = ?", false).
+		Where("seller_id = ?", sellerID)
+
+	var count int
+	if err := q.Get(ctx, &count, sql); err != nil {
+		return 0, errors.Wrap(err, "could not run select query")
+	}
+
+	return count, nil
+}
+
 // GetOfferByID loads a row from the `offers` table, selected by offerid.
 func (q *Q) GetOfferByID(ctx context.Context, id int64) (Offer, error) {
 	var offer Offer
@@ -279,6 +279,31 @@ func TestRemoveOffer(t *testing.T) {
 	tt.Assert.Len(updated, 0)
 }
 
+func TestCountOffersForAccount(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+	q := &Q{tt.HorizonSession()}
+
+	for _, offer := range []Offer{eurOffer, twoEurOffer, threeEurOffer} {
+		tt.Assert.NoError(insertOffer(tt, q, offer))
+	}
+
+	count, err := q.CountOffersForAccount(tt.Ctx, twoEurOfferSeller.Address())
+	tt.Assert.NoError(err)
+	tt.Assert.Equal(2, count)
+
+	_, err = q.RemoveOffers(tt.Ctx, []int64{threeEurOffer.OfferID}, 1235)
+	tt.Assert.NoError(err)
+	count, err = q.CountOffersForAccount(tt.Ctx, twoEurOfferSeller.Address())
+	tt.Assert.NoError(err)
+	tt.Assert.Equal(1, count)
+
+	count, err = q.CountOffersForAccount(tt.Ctx, "GCT2NQM5KJJEF55NPMY444C6M6CA7T33HRNCMA6ZFBIIXKNCRO6J25K7")
+	tt.Assert.NoError(err)
+	tt.Assert.Equal(0, count)
+}
+
 func TestGetOffers(t *testing.T) {
 	tt := test.Start(t)
 	defer tt.Finish()
@@ -357,6 +357,44 @@ func validateTransactionForOperation(transaction Transaction, operation Operatio
 	return nil
 }
 
+// AccountCreation identifies the operation which created an account.
+type AccountCreation struct {
+	OperationID int64 `db:"id"`
+}
+
+// LedgerSequence returns the ledger in which the account was created.
+func (c AccountCreation) LedgerSequence() int32 {
+	return toid.Parse(c.OperationID).LedgerSequence
+}
+
+// AccountCreationInfo returns the latest successful create_account operation
+// funding `addr`. It returns sql.ErrNoRows if the creation of the account
+// isn't in the history, e.g. when it predates the ingested ledgers.
+func (q *Q) AccountCreationInfo(ctx context.Context, addr string) (AccountCreation, error) {
+	var creation AccountCreation
+	var account Account
+	if err := q.AccountByAddress(ctx, &account, addr); err != nil {
+		return creation, err
+	}
+
+	// the operations of the account are looked up through the
+	// history_operation_participants.hist_op_p_id index, the details of
+	// only these operations are then checked
+	sql := sq.Select("hop.id").
+		From("history_operation_participants hopp").
+		Join("history_operations hop ON hop.id = hopp.history_operation_id").
+		LeftJoin("history_transactions ht ON ht.id = hop.transaction_id").
+		Where("hopp.history_account_id = ?", account.ID).
+		Where(sq.Eq{"hop.type": xdr.OperationTypeCreateAccount}).
+		Where("hop.details->>'account' = ?", addr).
+		Where("(ht.successful = true OR ht.successful IS NULL)").
+		OrderBy("hopp.history_operation_id DESC").
+		Limit(1)
+
+	err := q.Get(ctx, &creation, sql)
+	return creation, err
+}
+
 // QOperations defines history_operation related queries.
 type QOperations interface {
 	NewOperationBatchInsertBuilder(maxBatchSize int) OperationBatchInsertBuilder
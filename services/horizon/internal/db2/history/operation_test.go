@@ -1,11 +1,16 @@
 package history
 
 import (
+	"database/sql"
+	"encoding/json"
 	"testing"
 
 	sq "github.com/Masterminds/squirrel"
+	"github.com/guregu/null"
 	"github.com/stellar/go/services/horizon/internal/db2"
 	"github.com/stellar/go/services/horizon/internal/test"
+	"github.com/stellar/go/services/horizon/internal/toid"
+	"github.com/stellar/go/xdr"
 )
 
 func TestOperationQueries(t *testing.T) {
@@ -390,3 +395,60 @@ func TestValidateTransactionForOperation(t *testing.T) {
 	tt.Assert.Error(err)
 	tt.Assert.EqualError(err, "transaction successful flag false does not match transaction successful flag in operation true")
 }
+
+func TestAccountCreationInfo(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+	q := &Q{tt.HorizonSession()}
+
+	funder := "GAQAA5L65LSYH7CQ3VTJ7F3HHLGCL3DSLAR2Y47263D56MNNGHSQSTVY"
+	account := "GANFZDRBCNTUXIODCJEYMACPMCSZEVE4WZGZ3CZDZ3P2SXK4KH75IK6Y"
+
+	_, err := q.AccountCreationInfo(tt.Ctx, account)
+	tt.Assert.Equal(sql.ErrNoRows, err)
+
+	accounts, err := q.CreateAccounts(tt.Ctx, []string{funder, account}, 2)
+	tt.Assert.NoError(err)
+
+	builder := q.NewOperationBatchInsertBuilder(0)
+	participants := q.NewOperationParticipantBatchInsertBuilder(0)
+	addCreateAccount := func(sequence int32, destination string) {
+		details, err := json.Marshal(map[string]string{
+			"funder":           funder,
+			"account":          destination,
+			"starting_balance": "100.0000000",
+		})
+		tt.Assert.NoError(err)
+		tt.Assert.NoError(builder.Add(tt.Ctx,
+			toid.New(sequence, 1, 1).ToInt64(),
+			toid.New(sequence, 1, 0).ToInt64(),
+			1,
+			xdr.OperationTypeCreateAccount,
+			details,
+			funder,
+			null.String{},
+		))
+		for participant := range map[string]bool{funder: true, destination: true} {
+			tt.Assert.NoError(participants.Add(tt.Ctx,
+				toid.New(sequence, 1, 1).ToInt64(),
+				accounts[participant],
+			))
+		}
+	}
+	addCreateAccount(10, account)
+	addCreateAccount(11, funder)
+	// the account was merged and created again
+	addCreateAccount(12, account)
+	tt.Assert.NoError(builder.Exec(tt.Ctx))
+	tt.Assert.NoError(participants.Exec(tt.Ctx))
+
+	creation, err := q.AccountCreationInfo(tt.Ctx, account)
+	tt.Assert.NoError(err)
+	tt.Assert.Equal(toid.New(12, 1, 1).ToInt64(), creation.OperationID)
+	tt.Assert.Equal(int32(12), creation.LedgerSequence())
+
+	creation, err = q.AccountCreationInfo(tt.Ctx, funder)
+	tt.Assert.NoError(err)
+	tt.Assert.Equal(int32(11), creation.LedgerSequence())
+}
@@ -70,6 +70,99 @@ func (q *Q) AssetsForAddress(ctx context.Context, addy string) ([]xdr.Asset, []x
 	return assets, balances, err
 }
 
+// AssetsIssuedBy returns the distinct assets issued by `issuer` which are
+// held in at least one trust line, sorted by code.
+func (q *Q) AssetsIssuedBy(ctx context.Context, issuer string) ([]xdr.Asset, error) {
+	sql := sq.Select("DISTINCT asset_code").
+		From("trust_lines").
+		Where(sq.Eq{"asset_issuer": issuer}).
+		OrderBy("asset_code")
+
+	var codes []string
+	if err := q.Select(ctx, &codes, sql); err != nil {
+		return nil, errors.Wrap(err, "could not load issued assets")
+	}
+
+	assets := make([]xdr.Asset, 0, len(codes))
+	for _, code := range codes {
+		assets = append(assets, xdr.MustNewCreditAsset(code, issuer))
+	}
+	return assets, nil
+}
+
+// HasTrustline returns true if the account `addr` holds a trust line to the
+// asset. The trust line is looked up by its ledger key, without loading it.
+func (q *Q) HasTrustline(ctx context.Context, addr string, asset xdr.Asset) (bool, error) {
+	var accountID xdr.AccountId
+	if err := accountID.SetAddress(addr); err != nil {
+		return false, errors.Wrap(err, "invalid account id")
+	}
+	key, err := ledgerKeyTrustLineToString(xdr.LedgerKeyTrustLine{AccountId: accountID, Asset: asset})
+	if err != nil {
+		return false, err
+	}
+
+	sql := sq.Select("count(*)").From("trust_lines").Where(sq.Eq{"ledger_key": key})
+	var count int
+	if err = q.Get(ctx, &count, sql); err != nil {
+		return false, errors.Wrap(err, "could not run select query")
+	}
+
+	return count > 0, nil
+}
+
+// TrustLineAuthState is the authorization state of a trust line.
+type TrustLineAuthState string
+
+const (
+	// TrustLineAuthorized is the state of the trust lines authorized by the
+	// issuer.
+	TrustLineAuthorized TrustLineAuthState = "authorized"
+	// TrustLineAuthorizedToMaintainLiabilities is the state of the trust
+	// lines which can only maintain their existing offers.
+	TrustLineAuthorizedToMaintainLiabilities TrustLineAuthState = "authorized_to_maintain_liabilities"
+	// TrustLineNotAuthorized is the state of the trust lines which aren't
+	// authorized at all.
+	TrustLineNotAuthorized TrustLineAuthState = "none"
+)
+
+// AuthState returns the authorization state of the trust line.
+func (trustLine TrustLine) AuthState() TrustLineAuthState {
+	switch {
+	case trustLine.IsAuthorized():
+		return TrustLineAuthorized
+	case trustLine.IsAuthorizedToMaintainLiabilities():
+		return TrustLineAuthorizedToMaintainLiabilities
+	default:
+		return TrustLineNotAuthorized
+	}
+}
+
+// TrustlineAuthStates returns the authorization state of the trust lines
+// held by the accounts to asset, by account id. The accounts without a trust
+// line to the asset are left out.
+func (q *Q) TrustlineAuthStates(ctx context.Context, asset xdr.Asset, accounts []string) (map[string]TrustLineAuthState, error) {
+	keys := make([]xdr.LedgerKeyTrustLine, 0, len(accounts))
+	for _, account := range accounts {
+		var accountID xdr.AccountId
+		if err := accountID.SetAddress(account); err != nil {
+			return nil, errors.Wrap(err, "invalid account id")
+		}
+		keys = append(keys, xdr.LedgerKeyTrustLine{AccountId: accountID, Asset: asset})
+	}
+
+	trustLines, err := q.GetTrustLinesByKeys(ctx, keys)
+	if err != nil {
+		return nil, errors.Wrap(err, "loading trust lines")
+	}
+
+	states := make(map[string]TrustLineAuthState, len(trustLines))
+	for _, trustLine := range trustLines {
+		states[trustLine.AccountID] = trustLine.AuthState()
+	}
+	return states, nil
+}
+
 func (q *Q) CountTrustLines(ctx context.Context) (int, error) {
 	sql := sq.Select("count(*)").From("trust_lines")
 
@@ -81,6 +174,45 @@ func (q *Q) CountTrustLines(ctx context.Context) (int, error) {
 	return count, nil
 }
 
+// trustLineCountBuckets are the buckets of TrustlineCountHistogram, in order.
+var trustLineCountBuckets = []string{"0", "1", "2-10", "11+"}
+
+// TrustlineCountHistogram returns the number of accounts holding no trust
+// lines, one trust line, 2 to 10 trust lines and more than 10 trust lines.
+// Empty buckets are included.
+func (q *Q) TrustlineCountHistogram(ctx context.Context) ([]TrustLineCountBucket, error) {
+	counts := sq.Select(`CASE
+			WHEN count(trust_lines.account_id) = 0 THEN '0'
+			WHEN count(trust_lines.account_id) = 1 THEN '1'
+			WHEN count(trust_lines.account_id) <= 10 THEN '2-10'
+			ELSE '11+'
+		END AS bucket`).
+		From("accounts").
+		LeftJoin("trust_lines ON trust_lines.account_id = accounts.account_id").
+		GroupBy("accounts.account_id")
+	sql := sq.Select("bucket", "count(*) AS accounts").
+		FromSelect(counts, "counts").
+		GroupBy("bucket")
+
+	var rows []TrustLineCountBucket
+	if err := q.Select(ctx, &rows, sql); err != nil {
+		return nil, errors.Wrap(err, "could not run select query")
+	}
+
+	accounts := map[string]int64{}
+	for _, row := range rows {
+		accounts[row.Bucket] = row.Accounts
+	}
+	histogram := make([]TrustLineCountBucket, 0, len(trustLineCountBuckets))
+	for _, bucket := range trustLineCountBuckets {
+		histogram = append(histogram, TrustLineCountBucket{
+			Bucket:   bucket,
+			Accounts: accounts[bucket],
+		})
+	}
+	return histogram, nil
+}
+
 func (q *Q) GetSortedTrustLinesByAccountID(ctx context.Context, id string) ([]TrustLine, error) {
 	return q.GetSortedTrustLinesByAccountIDs(ctx, []string{id})
 }
@@ -258,6 +390,12 @@ func (q *Q) RemoveTrustLine(ctx context.Context, ledgerKey xdr.LedgerKeyTrustLin
 	return result.RowsAffected()
 }
 
+// CountTrustLinesForAccounts returns the number of trust lines of each of the
+// given accounts, without loading them.
+func (q *Q) CountTrustLinesForAccounts(ctx context.Context, accounts []string) (map[string]int, error) {
+	return q.countRowsByAccount(ctx, "trust_lines", accounts)
+}
+
 // GetSortedTrustLinesByAccountIDs loads trust lines for a list of accounts ID, ordered by asset and issuer
 func (q *Q) GetSortedTrustLinesByAccountIDs(ctx context.Context, id []string) ([]TrustLine, error) {
 	var data []TrustLine
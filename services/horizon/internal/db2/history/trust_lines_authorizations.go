@@ -0,0 +1,162 @@
+package history
+
+import (
+	"context"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/lib/pq"
+
+	"github.com/stellar/go/services/horizon/internal/db2"
+	"github.com/stellar/go/support/db"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/xdr"
+)
+
+// IsAuthorized returns true if issuer had authorized account to perform
+// transactions with its credit in the ledger of the record
+func (auth TrustLineAuthorization) IsAuthorized() bool {
+	return xdr.TrustLineFlags(auth.Flags).IsAuthorized()
+}
+
+// IsAuthorizedToMaintainLiabilities returns true if issuer had authorized the
+// account to maintain liabilities with its credit in the ledger of the record
+func (auth TrustLineAuthorization) IsAuthorizedToMaintainLiabilities() bool {
+	return xdr.TrustLineFlags(auth.Flags).IsAuthorizedToMaintainLiabilitiesFlag()
+}
+
+// InsertTrustLineAuthorizations records the authorization flags of the given
+// trust lines in the `history_trust_lines_authorizations` table. Recording the
+// same trust line twice for a ledger overwrites the previous row, so
+// reingesting a ledger is idempotent.
+func (q *Q) InsertTrustLineAuthorizations(
+	ctx context.Context,
+	ledgerSequence uint32,
+	trustLines []xdr.LedgerEntry,
+) error {
+	var accountID, assetIssuer, assetCode []string
+	var ledger, flags []xdr.Uint32
+	var assetType []xdr.AssetType
+
+	for _, entry := range trustLines {
+		if entry.Data.Type != xdr.LedgerEntryTypeTrustline {
+			return errors.Errorf("Invalid entry type: %d", entry.Data.Type)
+		}
+
+		m := trustLineToMap(entry)
+		ledger = append(ledger, xdr.Uint32(ledgerSequence))
+		accountID = append(accountID, m["account_id"].(string))
+		assetType = append(assetType, m["asset_type"].(xdr.AssetType))
+		assetIssuer = append(assetIssuer, m["asset_issuer"].(string))
+		assetCode = append(assetCode, m["asset_code"].(string))
+		flags = append(flags, m["flags"].(xdr.Uint32))
+	}
+
+	sql := `
+	WITH r AS
+		(SELECT
+			unnest(?::int[]),
+			unnest(?::text[]),
+			unnest(?::int[]),
+			unnest(?::text[]),
+			unnest(?::text[]),
+			unnest(?::int[])
+		)
+	INSERT INTO history_trust_lines_authorizations (
+		ledger_sequence,
+		account_id,
+		asset_type,
+		asset_issuer,
+		asset_code,
+		flags
+	)
+	SELECT * from r
+	ON CONFLICT (account_id, asset_type, asset_issuer, asset_code, ledger_sequence) DO UPDATE SET
+		flags = excluded.flags`
+
+	_, err := q.ExecRaw(
+		context.WithValue(ctx, &db.QueryTypeContextKey, db.UpsertQueryType),
+		sql,
+		pq.Array(ledger),
+		pq.Array(accountID),
+		pq.Array(assetType),
+		pq.Array(assetIssuer),
+		pq.Array(assetCode),
+		pq.Array(flags))
+	return err
+}
+
+// DeleteTrustLineAuthorizationsBefore removes the authorization changes
+// recorded before the given ledger. It is only meant for the reaper:
+// reingesting a range of ledgers doesn't rebuild these rows, so they aren't
+// cleared with the rest of the history.
+func (q *Q) DeleteTrustLineAuthorizationsBefore(ctx context.Context, ledgerSequence uint32) error {
+	sql := sq.Delete("history_trust_lines_authorizations").
+		Where("ledger_sequence < ?", ledgerSequence)
+	_, err := q.Exec(ctx, sql)
+	return err
+}
+
+// TrustlineAuthHistory returns the authorization changes of the trust line
+// held by accountID for asset, paged by ledger sequence.
+func (q *Q) TrustlineAuthHistory(
+	ctx context.Context,
+	accountID string,
+	asset xdr.Asset,
+	page db2.PageQuery,
+) ([]TrustLineAuthorization, error) {
+	var assetType xdr.AssetType
+	var assetCode, assetIssuer string
+	if err := asset.Extract(&assetType, &assetCode, &assetIssuer); err != nil {
+		return nil, errors.Wrap(err, "could not extract asset")
+	}
+
+	sql := selectTrustLineAuthorizations.Where(map[string]interface{}{
+		"account_id":   accountID,
+		"asset_type":   assetType,
+		"asset_code":   assetCode,
+		"asset_issuer": assetIssuer,
+	})
+	sql, err := page.ApplyTo(sql, "ledger_sequence")
+	if err != nil {
+		return nil, errors.Wrap(err, "could not apply query to page")
+	}
+
+	var results []TrustLineAuthorization
+	if err := q.Select(ctx, &results, sql); err != nil {
+		return nil, errors.Wrap(err, "could not run select query")
+	}
+
+	return results, nil
+}
+
+// LastAuthorizedLedgers returns, for every trust line of accountID which was
+// ever authorized, the latest recorded change authorizing it.
+func (q *Q) LastAuthorizedLedgers(ctx context.Context, accountID string) ([]TrustLineAuthorization, error) {
+	return q.LastAuthorizedLedgersForAccounts(ctx, []string{accountID})
+}
+
+// LastAuthorizedLedgersForAccounts is like LastAuthorizedLedgers but loads
+// the trust lines of all the given accounts in a single query.
+func (q *Q) LastAuthorizedLedgersForAccounts(ctx context.Context, accountIDs []string) ([]TrustLineAuthorization, error) {
+	sql := selectTrustLineAuthorizations.
+		Options("DISTINCT ON (account_id, asset_type, asset_issuer, asset_code)").
+		Where(sq.Eq{"account_id": accountIDs}).
+		Where("flags & ? != 0", uint32(xdr.TrustLineFlagsAuthorizedFlag)).
+		OrderBy("account_id", "asset_type", "asset_issuer", "asset_code", "ledger_sequence desc")
+
+	var results []TrustLineAuthorization
+	if err := q.Select(ctx, &results, sql); err != nil {
+		return nil, errors.Wrap(err, "could not run select query")
+	}
+
+	return results, nil
+}
+
+var selectTrustLineAuthorizations = sq.Select(`
+	ledger_sequence,
+	account_id,
+	asset_type,
+	asset_issuer,
+	asset_code,
+	flags
+`).From("history_trust_lines_authorizations")
@@ -0,0 +1,94 @@
+package history
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stellar/go/services/horizon/internal/db2"
+	"github.com/stellar/go/services/horizon/internal/test"
+	"github.com/stellar/go/xdr"
+)
+
+func TestInsertTrustLineAuthorizations(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+	q := &Q{tt.HorizonSession()}
+
+	deauthorized := eurTrustLine
+	trustLine := *eurTrustLine.Data.TrustLine
+	trustLine.Flags = 0
+	deauthorized.Data.TrustLine = &trustLine
+
+	assert.NoError(t, q.InsertTrustLineAuthorizations(tt.Ctx, 10, []xdr.LedgerEntry{eurTrustLine, usdTrustLine}))
+	assert.NoError(t, q.InsertTrustLineAuthorizations(tt.Ctx, 11, []xdr.LedgerEntry{deauthorized}))
+	// reingesting a ledger overwrites its rows
+	assert.NoError(t, q.InsertTrustLineAuthorizations(tt.Ctx, 11, []xdr.LedgerEntry{deauthorized}))
+
+	page := db2.PageQuery{Order: "asc", Limit: 10}
+	auths, err := q.TrustlineAuthHistory(
+		tt.Ctx,
+		eurTrustLine.Data.TrustLine.AccountId.Address(),
+		eurTrustLine.Data.TrustLine.Asset,
+		page,
+	)
+	assert.NoError(t, err)
+	if assert.Len(t, auths, 2) {
+		assert.Equal(t, uint32(10), auths[0].LedgerSequence)
+		assert.True(t, auths[0].IsAuthorized())
+		assert.Equal(t, uint32(11), auths[1].LedgerSequence)
+		assert.False(t, auths[1].IsAuthorized())
+	}
+
+	auths, err = q.TrustlineAuthHistory(
+		tt.Ctx,
+		usdTrustLine.Data.TrustLine.AccountId.Address(),
+		usdTrustLine.Data.TrustLine.Asset,
+		page,
+	)
+	assert.NoError(t, err)
+	assert.Len(t, auths, 1)
+}
+
+func TestLastAuthorizedLedgers(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+	q := &Q{tt.HorizonSession()}
+
+	deauthorized := eurTrustLine
+	trustLine := *eurTrustLine.Data.TrustLine
+	trustLine.Flags = 0
+	deauthorized.Data.TrustLine = &trustLine
+
+	// eurTrustLine is authorized at 10 and 12, usdTrustLine (held by another
+	// account) is recorded unauthorized at 11
+	assert.NoError(t, q.InsertTrustLineAuthorizations(tt.Ctx, 9, []xdr.LedgerEntry{deauthorized}))
+	assert.NoError(t, q.InsertTrustLineAuthorizations(tt.Ctx, 10, []xdr.LedgerEntry{eurTrustLine}))
+	assert.NoError(t, q.InsertTrustLineAuthorizations(tt.Ctx, 11, []xdr.LedgerEntry{deauthorized, usdTrustLine}))
+	assert.NoError(t, q.InsertTrustLineAuthorizations(tt.Ctx, 12, []xdr.LedgerEntry{eurTrustLine}))
+	assert.NoError(t, q.InsertTrustLineAuthorizations(tt.Ctx, 13, []xdr.LedgerEntry{deauthorized}))
+
+	auths, err := q.LastAuthorizedLedgers(tt.Ctx, eurTrustLine.Data.TrustLine.AccountId.Address())
+	assert.NoError(t, err)
+	if assert.Len(t, auths, 1) {
+		assert.Equal(t, uint32(12), auths[0].LedgerSequence)
+		assert.Equal(t, "EUR", auths[0].AssetCode)
+	}
+
+	// usdTrustLine was never authorized
+	auths, err = q.LastAuthorizedLedgers(tt.Ctx, usdTrustLine.Data.TrustLine.AccountId.Address())
+	assert.NoError(t, err)
+	assert.Len(t, auths, 0)
+
+	auths, err = q.LastAuthorizedLedgersForAccounts(tt.Ctx, []string{
+		eurTrustLine.Data.TrustLine.AccountId.Address(),
+		usdTrustLine.Data.TrustLine.AccountId.Address(),
+	})
+	assert.NoError(t, err)
+	if assert.Len(t, auths, 1) {
+		assert.Equal(t, eurTrustLine.Data.TrustLine.AccountId.Address(), auths[0].AccountID)
+		assert.Equal(t, uint32(12), auths[0].LedgerSequence)
+	}
+}
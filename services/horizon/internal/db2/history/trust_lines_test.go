@@ -2,6 +2,7 @@ package history
 
 import (
 	"database/sql"
+	"fmt"
 	"testing"
 
 	"github.com/guregu/null"
@@ -479,3 +480,163 @@ func TestAssetsForAddress(t *testing.T) {
 
 	tt.Assert.Equal(expected, assetsToBalance)
 }
+
+func TestTrustlineCountHistogram(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+	q := &Q{tt.HorizonSession()}
+
+	histogram, err := q.TrustlineCountHistogram(tt.Ctx)
+	tt.Assert.NoError(err)
+	tt.Assert.Equal([]TrustLineCountBucket{
+		{Bucket: "0", Accounts: 0},
+		{Bucket: "1", Accounts: 0},
+		{Bucket: "2-10", Accounts: 0},
+		{Bucket: "11+", Accounts: 0},
+	}, histogram)
+
+	withoutTrustLines := account3
+	accountEntry := *account3.Data.Account
+	accountEntry.AccountId = inflationDest
+	withoutTrustLines.Data.Account = &accountEntry
+
+	batch := q.NewAccountsBatchInsertBuilder(0)
+	for _, account := range []xdr.LedgerEntry{account1, account2, account3, withoutTrustLines} {
+		tt.Assert.NoError(batch.Add(tt.Ctx, account))
+	}
+	tt.Assert.NoError(batch.Exec(tt.Ctx))
+
+	trustLineFor := func(account xdr.AccountId, code string) xdr.LedgerEntry {
+		return xdr.LedgerEntry{
+			LastModifiedLedgerSeq: 1234,
+			Data: xdr.LedgerEntryData{
+				Type: xdr.LedgerEntryTypeTrustline,
+				TrustLine: &xdr.TrustLineEntry{
+					AccountId: account,
+					Asset:     xdr.MustNewCreditAsset(code, trustLineIssuer.Address()),
+					Balance:   1,
+					Limit:     10,
+				},
+			},
+		}
+	}
+
+	// account1 holds 1 trust line, account2 holds 3 and account3 holds 11
+	trustLines := []xdr.LedgerEntry{eurTrustLine}
+	for i := 0; i < 3; i++ {
+		trustLines = append(trustLines, trustLineFor(account2.Data.Account.AccountId, fmt.Sprintf("A%d", i)))
+	}
+	for i := 0; i < 11; i++ {
+		trustLines = append(trustLines, trustLineFor(account3.Data.Account.AccountId, fmt.Sprintf("B%d", i)))
+	}
+	tt.Assert.NoError(q.UpsertTrustLines(tt.Ctx, trustLines))
+
+	histogram, err = q.TrustlineCountHistogram(tt.Ctx)
+	tt.Assert.NoError(err)
+	tt.Assert.Equal([]TrustLineCountBucket{
+		{Bucket: "0", Accounts: 1},
+		{Bucket: "1", Accounts: 1},
+		{Bucket: "2-10", Accounts: 1},
+		{Bucket: "11+", Accounts: 1},
+	}, histogram)
+}
+
+func TestAssetsIssuedBy(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+	q := &Q{tt.HorizonSession()}
+
+	assets, err := q.AssetsIssuedBy(tt.Ctx, trustLineIssuer.Address())
+	tt.Assert.NoError(err)
+	tt.Assert.Empty(assets)
+
+	// usdTrustLine and usdTrustLine2 hold the same asset
+	for _, entry := range []xdr.LedgerEntry{eurTrustLine, usdTrustLine, usdTrustLine2} {
+		_, err = q.InsertTrustLine(tt.Ctx, entry)
+		tt.Assert.NoError(err)
+	}
+
+	assets, err = q.AssetsIssuedBy(tt.Ctx, trustLineIssuer.Address())
+	tt.Assert.NoError(err)
+	tt.Assert.Equal([]xdr.Asset{
+		eurTrustLine.Data.TrustLine.Asset,
+		usdTrustLine.Data.TrustLine.Asset,
+	}, assets)
+
+	assets, err = q.AssetsIssuedBy(tt.Ctx, account1.Data.Account.AccountId.Address())
+	tt.Assert.NoError(err)
+	tt.Assert.Empty(assets)
+}
+
+func TestTrustlineAuthStates(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+	q := &Q{tt.HorizonSession()}
+
+	// usdTrustLine isn't authorized, usdTrustLine2 is and account1 can only
+	// maintain its liabilities
+	authorized := usdTrustLine2
+	authorizedEntry := *usdTrustLine2.Data.TrustLine
+	authorizedEntry.Flags = xdr.Uint32(xdr.TrustLineFlagsAuthorizedFlag)
+	authorized.Data.TrustLine = &authorizedEntry
+
+	maintaining := usdTrustLine
+	maintainingEntry := *usdTrustLine.Data.TrustLine
+	maintainingEntry.AccountId = account1.Data.Account.AccountId
+	maintainingEntry.Flags = xdr.Uint32(xdr.TrustLineFlagsAuthorizedToMaintainLiabilitiesFlag)
+	maintaining.Data.TrustLine = &maintainingEntry
+
+	for _, entry := range []xdr.LedgerEntry{usdTrustLine, authorized, maintaining} {
+		_, err := q.InsertTrustLine(tt.Ctx, entry)
+		tt.Assert.NoError(err)
+	}
+
+	unauthorizedHolder := usdTrustLine.Data.TrustLine.AccountId.Address()
+	authorizedHolder := usdTrustLine2.Data.TrustLine.AccountId.Address()
+	maintainingHolder := account1.Data.Account.AccountId.Address()
+	// account2 doesn't hold a trust line to USD
+	nonHolder := account2.Data.Account.AccountId.Address()
+
+	states, err := q.TrustlineAuthStates(
+		tt.Ctx,
+		usdTrustLine.Data.TrustLine.Asset,
+		[]string{unauthorizedHolder, authorizedHolder, maintainingHolder, nonHolder},
+	)
+	tt.Assert.NoError(err)
+	tt.Assert.Equal(map[string]TrustLineAuthState{
+		unauthorizedHolder: TrustLineNotAuthorized,
+		authorizedHolder:   TrustLineAuthorized,
+		maintainingHolder:  TrustLineAuthorizedToMaintainLiabilities,
+	}, states)
+
+	_, err = q.TrustlineAuthStates(tt.Ctx, usdTrustLine.Data.TrustLine.Asset, []string{"GINVALID"})
+	tt.Assert.Error(err)
+}
+
+func TestHasTrustline(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+	q := &Q{tt.HorizonSession()}
+
+	_, err := q.InsertTrustLine(tt.Ctx, eurTrustLine)
+	tt.Assert.NoError(err)
+	_, err = q.InsertTrustLine(tt.Ctx, usdTrustLine)
+	tt.Assert.NoError(err)
+
+	holder := usdTrustLine.Data.TrustLine.AccountId.Address()
+	exists, err := q.HasTrustline(tt.Ctx, holder, usdTrustLine.Data.TrustLine.Asset)
+	tt.Assert.NoError(err)
+	tt.Assert.True(exists)
+
+	// the EUR trust line belongs to another account
+	exists, err = q.HasTrustline(tt.Ctx, holder, eurTrustLine.Data.TrustLine.Asset)
+	tt.Assert.NoError(err)
+	tt.Assert.False(exists)
+
+	_, err = q.HasTrustline(tt.Ctx, "GINVALID", usdTrustLine.Data.TrustLine.Asset)
+	tt.Assert.Error(err)
+}
@@ -64,6 +64,12 @@ func (p PageQuery) ApplyTo(
 
 // ApplyToUsingCursor returns a new SelectBuilder after applying the paging effects of
 // `p` to `sql`.  This method allows any type of cursor by a single column
+//
+// Accounts are paged by account id, but `/accounts` with a single `signer`
+// or with the `asset` filter (without `top`) also accepts the numeric row id
+// of an account as cursor, which pages by accounts.row_id instead. See
+// history.ParseAccountRowIDCursor. Unlike account ids, row ids are
+// reassigned when the state is rebuilt.
 func (p PageQuery) ApplyToUsingCursor(
 	sql sq.SelectBuilder,
 	col string,
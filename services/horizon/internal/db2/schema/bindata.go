@@ -41,6 +41,11 @@
 // migrations/44_asset_stat_accounts_and_balances.sql (439B)
 // migrations/45_add_claimable_balances_history.sql (2.163kB)
 // migrations/46_add_muxed_accounts.sql (465B)
+// migrations/47_add_history_trust_lines_authorizations.sql (614B)
+// migrations/48_add_accounts_balance_index.sql (548B)
+// migrations/49_add_accounts_last_modified_ledger_index.sql (604B)
+// migrations/50_add_accounts_row_id.sql (293B)
+// migrations/51_add_accounts_row_id_index.sql (265B)
 // migrations/4_add_protocol_version.sql (188B)
 // migrations/5_create_trades_table.sql (1.1kB)
 // migrations/6_create_assets_table.sql (366B)
@@ -936,6 +941,106 @@ func migrations46_add_muxed_accountsSql() (*asset, error) {
 	return a, nil
 }
 
+var _migrations47_add_history_trust_lines_authorizationsSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\x03\x8d\x92\x4d\x4f\x84\x30\x10\x86\xef\xfd\x15\x93\x3d\x41\x84\x83\x26\x7a\xd9\x13\x0a\x31\x44\x84\x0d\x42\xe2\x9e\x9a\x5a\x46\x68\x82\xed\xda\x0e\x2a\xfe\x7a\xc9\x1e\x14\x89\x1b\x99\xe3\xcc\x93\x77\xde\xf9\x08\x43\x38\x7b\x51\xad\x15\x84\x50\x1f\x18\xbb\x29\x93\xa8\x4a\xa0\x8a\xae\xb3\x04\x3a\xe5\xc8\xd8\x91\x93\x1d\x1c\xf1\x5e\x69\x74\x5c\x0c\xd4\x19\xab\x3e\x05\x29\xa3\x1d\x78\x0c\xa6\xe8\xb1\x69\xd1\x72\x87\xaf\x03\x6a\x89\xa0\x34\xe1\x94\x80\xbc\xa8\x20\xaf\xb3\x2c\x38\x52\x42\x4a\x33\x68\xe2\xaa\x01\xd9\x09\x2b\x24\x4d\xc8\x9b\xb0\xa3\xd2\xad\x77\x79\xe5\x2f\x71\xe7\x90\x38\x8d\x87\x93\x7a\x47\x40\x39\x37\x4c\xa5\xf5\x8a\xd2\x34\xf8\x07\x7e\x7e\xb1\xc4\x9f\x7b\xd1\xba\x13\xbd\x77\x65\x7a\x1f\x95\x7b\xb8\x4b\xf6\xe0\xfd\x0c\x16\xcc\x5c\x07\xbf\x0c\x06\xb3\xee\xc1\x72\x61\x3e\xf3\xb7\xdf\xbb\x4f\xf3\x38\x79\x84\x8d\xd2\x0d\x7e\xf0\xff\x4f\xc0\x8d\xe6\x0b\xb9\x0d\x14\xf9\x9a\xe3\xd5\x0f\x69\x7e\x0b\x4f\x64\x11\xc1\x5b\x5a\x9a\x0c\x85\xb3\xe7\x88\xcd\xbb\x66\x2c\x2e\x8b\xdd\xfa\xe7\x90\xc2\x49\xd1\xe0\x96\x7d\x01\x4d\x2c\xdd\x80\x66\x02\x00\x00")
+
+func migrations47_add_history_trust_lines_authorizationsSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations47_add_history_trust_lines_authorizationsSql,
+		"migrations/47_add_history_trust_lines_authorizations.sql",
+	)
+}
+
+func migrations47_add_history_trust_lines_authorizationsSql() (*asset, error) {
+	bytes, err := migrations47_add_history_trust_lines_authorizationsSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/47_add_history_trust_lines_authorizations.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x51, 0xa8, 0x62, 0xc7, 0xdd, 0x77, 0x86, 0x95, 0xec, 0x35, 0x96, 0xdc, 0x28, 0x2e, 0x60, 0x4f, 0xf4, 0x60, 0xe8, 0x5a, 0x26, 0x88, 0x6a, 0x51, 0x98, 0x49, 0x76, 0x16, 0x93, 0xd2, 0x72, 0x23}}
+	return a, nil
+}
+
+var _migrations48_add_accounts_balance_indexSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\x03\x8d\x51\x41\x4e\xc3\x40\x0c\xbc\xe7\x15\xbe\x01\xa2\xe9\x07\x7a\x82\x36\x82\x4a\x28\xa9\x42\x2a\xc1\xa9\xda\x6c\xdc\x64\xd5\xc4\xae\x76\x37\x0d\xf9\x3d\xde\x88\xb4\x12\xe2\xc0\xd5\x1e\xcf\x8c\x67\xe2\x18\x1e\x3b\x53\x5b\xe5\x11\xf6\x67\x20\xf6\x56\x91\x53\xda\x1b\xa6\x28\x8a\x63\xd8\xa9\x1a\x1d\xf0\x11\x94\xd6\xdc\x93\x77\xe0\xd8\x7a\xac\xa0\x1c\x81\x94\x37\x17\x84\x52\xb5\x8a\x34\x82\x43\x3c\x01\x13\xdc\xff\x0c\x16\xf3\xcd\xc1\x54\x0f\x8b\x40\x36\x18\xdf\x70\xef\xc1\x37\x08\x86\x2a\xfc\x02\xbc\xa0\x1d\xe1\x2c\x22\x13\xaf\x03\xd5\xb6\xd3\x7a\x96\x5b\x8a\xad\x4a\xdc\x39\xd0\x8d\xa2\xda\x50\x1d\xd6\x81\x6c\x96\x0d\xde\x68\xc6\x2f\xa0\x63\xe7\xc3\x4c\x50\x1d\x38\x13\x10\x47\x94\x73\x65\x51\x74\x4c\x05\x47\xcb\x1d\x18\x41\x6a\x45\x77\x1e\xca\x89\xec\x35\x2b\x84\x65\xec\x58\x50\x8a\x2a\xb1\xe1\x18\x06\x6b\x24\x16\xcf\x37\xbf\xcb\x80\x2d\xae\xee\x8d\x83\xb2\x37\xad\x07\xcd\xa4\x7b\x6b\x91\x7c\x3b\xca\x23\xb2\x96\xd4\x42\x86\x02\x99\x44\x5a\xd6\x27\x09\xad\x43\x45\x43\x63\x5a\x5c\x46\xeb\x3c\x79\x2a\x12\xd8\xa6\x9b\xe4\x03\xd6\x59\xba\xde\xe7\x79\x92\x16\x6f\x9f\xd7\xd7\x0f\xe5\x78\x98\x9f\xcc\xd2\x5b\x01\xfb\xf7\x6d\xfa\x02\xcf\x45\x9e\x24\x7f\x46\xbd\x9a\x8a\xbb\xd6\xba\xe1\x81\x7e\x17\xbb\xc9\xb3\xdd\x3f\xa5\x57\xd1\x37\x79\xd5\xaa\xd0\x24\x02\x00\x00")
+
+func migrations48_add_accounts_balance_indexSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations48_add_accounts_balance_indexSql,
+		"migrations/48_add_accounts_balance_index.sql",
+	)
+}
+
+func migrations48_add_accounts_balance_indexSql() (*asset, error) {
+	bytes, err := migrations48_add_accounts_balance_indexSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/48_add_accounts_balance_index.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x97, 0x29, 0x21, 0x86, 0x2e, 0x72, 0x8e, 0xce, 0x4, 0x36, 0x9e, 0x52, 0xd, 0x6c, 0x6, 0x9c, 0x2b, 0x9d, 0x88, 0x45, 0xd5, 0xd7, 0xed, 0xf0, 0xc5, 0x6d, 0xc1, 0xc5, 0xa5, 0x17, 0x70, 0x3c}}
+	return a, nil
+}
+
+var _migrations49_add_accounts_last_modified_ledger_indexSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\x03\x9d\x92\x41\x4f\x83\x40\x10\x85\xef\xfc\x8a\xb9\xa9\xb1\xd4\x1f\xd0\x93\xb6\x44\x9b\x18\x30\x48\x13\x3d\x91\x65\x19\xca\xc6\x65\xb6\xd9\x5d\x44\xfe\xbd\xb3\x54\x30\x31\x3d\x79\x65\xde\x7c\xef\xbd\x61\xe3\x18\x6e\x3b\x75\xb4\xc2\x23\x1c\x4e\x40\xc6\x5b\x41\x4e\x48\xaf\x0c\x45\x51\x1c\x43\xd1\x22\x74\xa6\x56\x8d\xc2\xba\x6c\xac\xe9\x40\x50\xfd\xfb\xc5\x1b\x68\x94\xf6\x68\x1d\x98\x06\xee\x84\x94\xa6\x27\xef\xc0\xa1\x46\xe9\x41\x00\xe3\x8e\xc8\xb3\xc0\xd2\xc2\xf9\x65\x15\x34\xd6\x47\xde\x5b\xc1\xa0\x7c\x6b\x7a\x0f\x9e\xad\x14\xd5\xf8\x05\xf8\x89\x76\x84\x93\xe0\x4d\x27\x39\x0f\x08\xad\xc3\x38\x40\x66\x8b\xf5\x84\x2b\x97\x24\x67\x1c\xc8\x36\x18\x72\x1a\xfa\xa1\xf4\xa7\x3a\x94\xe3\x74\x82\xe6\xe5\x15\x38\x13\x58\x64\x68\x9a\x30\xba\x03\x36\x82\x0a\xe1\x29\x2b\x58\x39\x76\xc6\xe2\x54\x35\x58\xcf\x92\xc1\x2a\x46\x71\xe7\x25\xea\x7a\xbe\xd1\x39\xb8\x72\x50\xf5\x7c\x0f\x90\x86\x64\x6f\x2d\x92\xd7\x23\x9b\xf1\x98\x43\x85\xa3\xb2\x84\xae\x3c\x54\xda\xc8\x0f\x3e\x42\x87\x82\x86\x56\x69\x5c\x47\xdb\x3c\xb9\x2f\x12\xd8\xa7\xbb\xe4\x0d\xb6\x59\xba\x3d\xe4\x79\x92\x16\xcf\xef\x4b\xe5\xb2\x1a\xcb\x8b\xa5\xb3\x74\xd1\xc0\xe1\x75\x9f\x3e\xc2\x43\x91\x27\xc9\xf5\x25\xf1\x6a\x96\x96\xaa\xbe\xd9\x4c\xff\x78\x79\x01\x3b\x33\xd0\xdf\x37\xb0\xcb\xb3\x97\xff\x84\xda\x44\xdf\x3e\xdf\x97\xd0\x5c\x02\x00\x00")
+
+func migrations49_add_accounts_last_modified_ledger_indexSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations49_add_accounts_last_modified_ledger_indexSql,
+		"migrations/49_add_accounts_last_modified_ledger_index.sql",
+	)
+}
+
+func migrations49_add_accounts_last_modified_ledger_indexSql() (*asset, error) {
+	bytes, err := migrations49_add_accounts_last_modified_ledger_indexSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/49_add_accounts_last_modified_ledger_index.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xa0, 0x5b, 0xb9, 0xcd, 0xc4, 0xa4, 0x1c, 0x95, 0x2c, 0xc1, 0xce, 0x34, 0x6d, 0x5a, 0xc8, 0x49, 0xf6, 0xe3, 0x18, 0xe2, 0x4d, 0xe9, 0xef, 0xf0, 0x5, 0xd2, 0x49, 0xc7, 0xa9, 0x9, 0x4d, 0x57}}
+	return a, nil
+}
+
+var _migrations50_add_accounts_row_idSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\x03\x6d\xcf\x41\x6e\xc2\x30\x10\x05\xd0\xbd\x4f\xf1\xf7\x34\x5c\x80\x55\xda\xb0\x40\x0a\x4d\x85\x60\x5d\x39\xf6\x90\x8c\x70\xc6\xad\x63\xcb\x70\xfb\x3a\x54\x02\x55\xea\xf2\x8f\x46\x6f\xe6\x57\x15\x56\x13\x0f\x41\x47\xc2\xe9\x4b\xa9\xaa\x42\x6d\x2d\xcb\x00\x0d\xe3\x5d\x9a\x04\x99\xe3\x58\xd2\x4c\xdf\x89\xc4\x10\x2c\x9d\x75\x72\x11\x81\x72\xe0\x48\x33\xe2\x48\xd0\xc6\xf8\x24\xb1\x04\xdd\x3b\x7a\x41\x1e\xd9\x8c\x8b\xc6\x33\x9c\x37\x17\xb2\x98\x48\x4b\x19\x3b\x5a\x63\x57\x16\x93\x70\x01\xc1\x62\xe9\xba\x6c\xf5\x89\x0b\x6a\xbc\x98\x14\x02\x49\x74\x37\xf4\xb7\xbb\x2d\x74\x8d\x0b\xf5\xfb\x27\x7b\x59\xab\xba\x3d\x6e\x0f\x38\xd6\xaf\xed\xf6\x79\xba\x6e\x1a\xbc\x75\xed\x69\xff\x8e\xe0\xf3\x27\x5b\xf4\x3c\xcc\x14\x58\xbb\xcd\xbd\xd9\xa3\x69\xe3\xb3\xa8\xff\x91\xe6\xd0\x7d\xfc\x55\x36\xea\x07\xee\x6d\x8b\x08\x25\x01\x00\x00")
+
+func migrations50_add_accounts_row_idSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations50_add_accounts_row_idSql,
+		"migrations/50_add_accounts_row_id.sql",
+	)
+}
+
+func migrations50_add_accounts_row_idSql() (*asset, error) {
+	bytes, err := migrations50_add_accounts_row_idSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/50_add_accounts_row_id.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xe7, 0xb2, 0xf6, 0x52, 0x3e, 0x54, 0x1f, 0xf0, 0x99, 0x6c, 0x3b, 0x58, 0x4a, 0x21, 0x90, 0xb2, 0xcb, 0x98, 0x74, 0x13, 0xd9, 0x18, 0xfe, 0x49, 0x35, 0xa9, 0xee, 0xe1, 0xcd, 0xa0, 0x59, 0x67}}
+	return a, nil
+}
+
+var _migrations51_add_accounts_row_id_indexSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\x03\x8d\x8e\x4d\x0a\xc2\x30\x18\x44\xf7\x3d\xc5\xec\x54\xa4\x5e\xc0\x95\xb6\x41\x0a\x92\x6a\x6c\x40\x57\x25\x4d\x83\x06\xeb\x17\x49\x52\xaa\xb7\xf7\x0f\x5c\xb8\x72\x3b\x6f\x86\x79\x69\x8a\xe9\xc5\x1e\xbd\x8a\x06\xf2\x0a\x72\xd1\x2b\x0a\x4a\x47\xeb\x28\x49\xd2\x14\xd5\xc9\xc0\x52\x6b\x6e\xb0\x01\x4d\x6f\xbb\x08\xed\x48\xf7\xde\x1b\x8a\xdd\x1d\xc1\x3d\xf1\xd1\x84\xd7\xe0\x59\xa1\x51\x44\xd3\x39\x7d\x36\x2d\x2e\x46\xd1\x70\xb2\x9d\x99\x25\x99\x60\x8b\x8a\x41\xf2\x62\x2b\x19\x0a\x9e\xb3\x3d\xb2\x92\x67\x52\x08\xc6\xab\xf5\x01\x4a\x6b\xd7\x53\x0c\x75\x73\xaf\xbd\x1b\x6a\xdb\xa2\xe4\xdf\x14\x72\x57\xf0\x15\x96\x95\x60\x6c\xfc\xc1\x93\xf9\x5b\xef\x2b\x9f\xbb\x81\x7e\xf5\x73\x51\x6e\xfe\x3b\x9b\x27\x0f\xfc\x12\xce\x9b\x09\x01\x00\x00")
+
+func migrations51_add_accounts_row_id_indexSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations51_add_accounts_row_id_indexSql,
+		"migrations/51_add_accounts_row_id_index.sql",
+	)
+}
+
+func migrations51_add_accounts_row_id_indexSql() (*asset, error) {
+	bytes, err := migrations51_add_accounts_row_id_indexSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/51_add_accounts_row_id_index.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xe0, 0x10, 0xec, 0xe, 0x3f, 0xfa, 0x48, 0x71, 0x22, 0x9c, 0x4b, 0x56, 0xdd, 0x1a, 0x8e, 0xe0, 0xb1, 0xa5, 0x94, 0x9, 0x12, 0x38, 0xe6, 0xe, 0xc0, 0x97, 0x46, 0xa3, 0x8c, 0xed, 0x1e, 0x96}}
+	return a, nil
+}
+
 var _migrations4_add_protocol_versionSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x84\xcd\xb1\x0a\xc2\x30\x10\x06\xe0\x3d\x4f\xf1\xef\x52\x70\xef\x14\x4d\x9d\xce\x44\x4a\x32\x38\x15\xd1\xa3\x06\x6a\xae\x5c\x82\xe2\xdb\xbb\xba\x88\x4f\xf0\x75\x1d\x36\x8f\x3c\xeb\xa5\x31\xd2\x6a\x2c\xc5\x61\x44\xb4\x3b\x1a\x10\x3c\x9d\x71\xcf\xb5\x89\xbe\xa7\x85\x6f\x33\x6b\x85\x01\xac\x73\xd8\x07\x4a\x47\x8f\x55\xa5\xc9\x55\x96\xe9\xc9\x5a\xb3\x14\xe4\xd2\x78\x66\x85\x1b\x0e\x36\x51\xc4\x16\x3e\x44\xf8\x44\xd4\x1b\xf3\x6d\x39\x79\x95\xff\x9a\x1b\xc3\xe9\x97\xd5\x9b\x4f\x00\x00\x00\xff\xff\x83\xbb\x30\x2e\xbc\x00\x00\x00")
 
 func migrations4_add_protocol_versionSqlBytes() ([]byte, error) {
@@ -1208,6 +1313,11 @@ var _bindata = map[string]func() (*asset, error){
 	"migrations/44_asset_stat_accounts_and_balances.sql":                 migrations44_asset_stat_accounts_and_balancesSql,
 	"migrations/45_add_claimable_balances_history.sql":                   migrations45_add_claimable_balances_historySql,
 	"migrations/46_add_muxed_accounts.sql":                               migrations46_add_muxed_accountsSql,
+	"migrations/47_add_history_trust_lines_authorizations.sql":           migrations47_add_history_trust_lines_authorizationsSql,
+	"migrations/48_add_accounts_balance_index.sql":                       migrations48_add_accounts_balance_indexSql,
+	"migrations/49_add_accounts_last_modified_ledger_index.sql":          migrations49_add_accounts_last_modified_ledger_indexSql,
+	"migrations/50_add_accounts_row_id.sql":                              migrations50_add_accounts_row_idSql,
+	"migrations/51_add_accounts_row_id_index.sql":                        migrations51_add_accounts_row_id_indexSql,
 	"migrations/4_add_protocol_version.sql":                              migrations4_add_protocol_versionSql,
 	"migrations/5_create_trades_table.sql":                               migrations5_create_trades_tableSql,
 	"migrations/6_create_assets_table.sql":                               migrations6_create_assets_tableSql,
@@ -1221,11 +1331,13 @@ var _bindata = map[string]func() (*asset, error){
 // directory embedded in the file by go-bindata.
 // For example if you run go-bindata on data/... and data contains the
 // following hierarchy:
-//     data/
-//       foo.txt
-//       img/
-//         a.png
-//         b.png
+//
+//	data/
+//	  foo.txt
+//	  img/
+//	    a.png
+//	    b.png
+//
 // then AssetDir("data") would return []string{"foo.txt", "img"},
 // AssetDir("data/img") would return []string{"a.png", "b.png"},
 // AssetDir("foo.txt") and AssetDir("notexist") would return an error, and
@@ -1300,6 +1412,11 @@ var _bintree = &bintree{nil, map[string]*bintree{
 		"44_asset_stat_accounts_and_balances.sql":                 &bintree{migrations44_asset_stat_accounts_and_balancesSql, map[string]*bintree{}},
 		"45_add_claimable_balances_history.sql":                   &bintree{migrations45_add_claimable_balances_historySql, map[string]*bintree{}},
 		"46_add_muxed_accounts.sql":                               &bintree{migrations46_add_muxed_accountsSql, map[string]*bintree{}},
+		"47_add_history_trust_lines_authorizations.sql":           &bintree{migrations47_add_history_trust_lines_authorizationsSql, map[string]*bintree{}},
+		"48_add_accounts_balance_index.sql":                       &bintree{migrations48_add_accounts_balance_indexSql, map[string]*bintree{}},
+		"49_add_accounts_last_modified_ledger_index.sql":          &bintree{migrations49_add_accounts_last_modified_ledger_indexSql, map[string]*bintree{}},
+		"50_add_accounts_row_id.sql":                              &bintree{migrations50_add_accounts_row_idSql, map[string]*bintree{}},
+		"51_add_accounts_row_id_index.sql":                        &bintree{migrations51_add_accounts_row_id_indexSql, map[string]*bintree{}},
 		"4_add_protocol_version.sql":                              &bintree{migrations4_add_protocol_versionSql, map[string]*bintree{}},
 		"5_create_trades_table.sql":                               &bintree{migrations5_create_trades_tableSql, map[string]*bintree{}},
 		"6_create_assets_table.sql":                               &bintree{migrations6_create_assets_tableSql, map[string]*bintree{}},
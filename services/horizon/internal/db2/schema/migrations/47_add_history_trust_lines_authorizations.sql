@@ -0,0 +1,17 @@
+-- +migrate Up
+
+CREATE TABLE history_trust_lines_authorizations (
+    ledger_sequence integer NOT NULL,
+    account_id character varying(56) NOT NULL,
+    asset_type integer NOT NULL,
+    asset_issuer character varying(56) NOT NULL,
+    asset_code character varying(12) NOT NULL,
+    flags integer NOT NULL,
+    PRIMARY KEY (account_id, asset_type, asset_issuer, asset_code, ledger_sequence)
+);
+
+CREATE INDEX "index_history_trust_lines_authorizations_on_ledger_sequence" ON history_trust_lines_authorizations USING btree (ledger_sequence);
+
+-- +migrate Down
+
+DROP TABLE history_trust_lines_authorizations cascade;
@@ -0,0 +1,12 @@
+-- +migrate Up notransaction
+
+-- Pages of accounts sorted by native balance seek on (balance, account_id),
+-- without the index every page sorts all the accounts. Updates changing the
+-- balance of an account, most of them since fees are paid from it, can't be
+-- HOT anymore and also write to the index.
+-- The index is built concurrently so ingestion isn't blocked meanwhile.
+CREATE INDEX CONCURRENTLY accounts_by_balance ON accounts USING BTREE(balance, account_id);
+
+-- +migrate Down notransaction
+
+DROP INDEX CONCURRENTLY accounts_by_balance;
@@ -0,0 +1,12 @@
+-- +migrate Up notransaction
+
+-- The modified_from and modified_to filters of /accounts select a range of
+-- last modified ledgers, without the index every page scans all the
+-- accounts. last_modified_ledger changes on every update of an account, so
+-- none of them can be HOT anymore and all of them write to the index.
+-- The index is built concurrently so ingestion isn't blocked meanwhile.
+CREATE INDEX CONCURRENTLY accounts_by_last_modified_ledger ON accounts USING BTREE(last_modified_ledger, account_id);
+
+-- +migrate Down notransaction
+
+DROP INDEX CONCURRENTLY accounts_by_last_modified_ledger;
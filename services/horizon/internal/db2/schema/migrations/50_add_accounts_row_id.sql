@@ -0,0 +1,10 @@
+-- +migrate Up
+
+-- Adding a column with a sequence default rewrites the accounts table, which
+-- is locked meanwhile. Its unique index is built concurrently by the next
+-- migration.
+ALTER TABLE accounts ADD COLUMN row_id bigserial;
+
+-- +migrate Down
+
+ALTER TABLE accounts DROP COLUMN row_id;
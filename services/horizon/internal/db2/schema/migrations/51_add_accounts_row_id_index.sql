@@ -0,0 +1,8 @@
+-- +migrate Up notransaction
+
+-- The index is built concurrently so ingestion isn't blocked meanwhile.
+CREATE UNIQUE INDEX CONCURRENTLY accounts_by_row_id ON accounts USING BTREE(row_id);
+
+-- +migrate Down notransaction
+
+DROP INDEX CONCURRENTLY accounts_by_row_id;
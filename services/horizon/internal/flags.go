@@ -12,6 +12,7 @@ import (
 
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
+	"github.com/stellar/go/services/horizon/internal/actions"
 	"github.com/stellar/go/services/horizon/internal/db2/schema"
 	apkg "github.com/stellar/go/support/app"
 	support "github.com/stellar/go/support/config"
@@ -105,6 +106,14 @@ func Flags() (*Config, support.ConfigOptions) {
 			Required:  false,
 			Usage:     "horizon postgres read-replica to connect with, when set it will return stale history error when replica is behind primary",
 		},
+		&support.ConfigOption{
+			Name:        "ro-database-lag-tolerance",
+			ConfigKey:   &config.ReplicaLagTolerance,
+			OptType:     types.Uint,
+			FlagDefault: uint(0),
+			Required:    false,
+			Usage:       "the maximum number of ledgers the read-replica is allowed to lag behind the primary before horizon returns a stale history error",
+		},
 		&support.ConfigOption{
 			Name:        StellarCoreBinaryPathName,
 			OptType:     types.String,
@@ -305,6 +314,20 @@ func Flags() (*Config, support.ConfigOptions) {
 			},
 			Usage: "max count of requests allowed in a one hour period, by remote ip address",
 		},
+		&support.ConfigOption{
+			Name:        "accounts-filter-rate-limit",
+			ConfigKey:   &config.AccountsFilterRateQuotas,
+			OptType:     types.String,
+			FlagDefault: "",
+			CustomSetValue: func(co *support.ConfigOption) {
+				quotas, err := actions.ParseAccountsFilterRateQuotas(viper.GetString(co.Name))
+				if err != nil {
+					stdLog.Fatalf("Could not parse %s: %v", co.Name, err)
+				}
+				*(co.ConfigKey.(*map[actions.AccountsFilterType]throttled.RateQuota)) = quotas
+			},
+			Usage: "comma separated list of filter=count pairs (e.g. asset=600,weak_thresholds=60), the max count of requests to /accounts using the filter allowed in a one hour period, by remote ip address",
+		},
 		&support.ConfigOption{
 			Name:           "friendbot-url",
 			ConfigKey:      &config.FriendbotURL,
@@ -414,6 +437,13 @@ func Flags() (*Config, support.ConfigOptions) {
 			FlagDefault: uint(0),
 			Usage:       "the maximum number of ledgers the history db is allowed to be out of date from the connected stellar-core db before horizon considers history stale",
 		},
+		&support.ConfigOption{
+			Name:        "strict-accounts-params",
+			ConfigKey:   &config.StrictAccountsParams,
+			OptType:     types.Bool,
+			FlagDefault: false,
+			Usage:       "reject requests to /accounts with unrecognized query parameters instead of ignoring them",
+		},
 		&support.ConfigOption{
 			Name:        "skip-cursor-update",
 			ConfigKey:   &config.SkipCursorUpdate,
@@ -15,6 +15,7 @@ import (
 	"github.com/stellar/go/support/errors"
 	"github.com/stellar/go/support/render/hal"
 	"github.com/stellar/go/support/render/httpjson"
+	"github.com/stellar/go/support/render/msgpack"
 	"github.com/stellar/go/support/render/problem"
 )
 
@@ -52,6 +53,60 @@ func (handler ObjectActionHandler) ServeHTTP(
 	problem.Render(r.Context(), w, hProblem.NotAcceptable)
 }
 
+// headAction checks the resource of a HEAD request and sets its headers.
+type headAction interface {
+	Head(w actions.HeaderWriter, r *http.Request) error
+}
+
+// headActionHandler responds to HEAD requests with the status and the
+// headers of the resource, without loading the resource itself.
+type headActionHandler struct {
+	action headAction
+}
+
+func (handler headActionHandler) ServeHTTP(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+	w = headResponseWriter{w}
+	if mimeType := render.Negotiate(r); mimeType != render.MimeHal && mimeType != render.MimeJSON {
+		problem.Render(r.Context(), w, hProblem.NotAcceptable)
+		return
+	}
+
+	err := handler.action.Head(w, r)
+	if err == actions.ErrNotModified {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	if err != nil {
+		problem.Render(r.Context(), w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/hal+json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+}
+
+// headHandler responds to HEAD requests like the wrapped handler responds to
+// GET requests, discarding the body.
+type headHandler struct {
+	http.Handler
+}
+
+func (handler headHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	handler.Handler.ServeHTTP(headResponseWriter{w}, r)
+}
+
+// headResponseWriter discards the body of the response.
+type headResponseWriter struct {
+	http.ResponseWriter
+}
+
+func (w headResponseWriter) Write(b []byte) (int, error) {
+	return len(b), nil
+}
+
 const defaultObjectStreamLimit = 10
 
 type streamableObjectAction interface {
@@ -65,6 +120,9 @@ type streamableObjectActionHandler struct {
 	action        streamableObjectAction
 	streamHandler sse.StreamHandler
 	limit         int
+	// msgpack enables responses encoded in MessagePack, for clients
+	// sending `Accept: application/msgpack`.
+	msgpack bool
 }
 
 func (handler streamableObjectActionHandler) ServeHTTP(
@@ -74,6 +132,10 @@ func (handler streamableObjectActionHandler) ServeHTTP(
 	switch render.Negotiate(r) {
 	case render.MimeHal, render.MimeJSON:
 		response, err := handler.action.GetResource(w, r)
+		if err == actions.ErrNotModified {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
 		if err != nil {
 			problem.Render(r.Context(), w, err)
 			return
@@ -88,11 +150,33 @@ func (handler streamableObjectActionHandler) ServeHTTP(
 	case render.MimeEventStream:
 		handler.renderStream(w, r)
 		return
+	case render.MimeMsgpack:
+		if handler.msgpack {
+			handler.renderMsgpack(w, r)
+			return
+		}
 	}
 
 	problem.Render(r.Context(), w, hProblem.NotAcceptable)
 }
 
+func (handler streamableObjectActionHandler) renderMsgpack(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+	response, err := handler.action.GetResource(w, r)
+	if err == actions.ErrNotModified {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	if err != nil {
+		problem.Render(r.Context(), w, err)
+		return
+	}
+
+	msgpack.Render(w, response)
+}
+
 func repeatableReadStream(
 	r *http.Request,
 	generateEvents sse.GenerateEventsFunc,
@@ -310,8 +394,11 @@ type rawAction interface {
 	WriteRawResponse(w io.Writer, r *http.Request) error
 }
 
+// HandleRaw writes the raw response of the action as
+// application/octet-stream, whatever the content of the response looks like.
 func HandleRaw(action rawAction) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", render.MimeRaw)
 		if err := action.WriteRawResponse(w, r); err != nil {
 			problem.Render(r.Context(), w, err)
 		}
@@ -327,6 +327,10 @@ func (m *StateMiddleware) WrapFunc(h http.HandlerFunc) http.HandlerFunc {
 			ReadOnly:  true,
 		})
 		if err != nil {
+			if actions.IsHistoryUnavailable(err) {
+				problem.Render(ctx, w, hProblem.HistoryUnavailable)
+				return
+			}
 			err = supportErrors.Wrap(err, "Error starting ingestion read transaction")
 			problem.Render(ctx, w, err)
 			return
@@ -386,10 +390,17 @@ func (m *StateMiddleware) Wrap(h http.Handler) http.Handler {
 	return m.WrapFunc(h.ServeHTTP)
 }
 
+// ReplicaLagHeader is the response header with the number of ledgers the
+// read-replica lags behind the primary database.
+const ReplicaLagHeader = "X-Replica-Lag-Ledgers"
+
 type ReplicaSyncCheckMiddleware struct {
 	PrimaryHistoryQ *history.Q
 	ReplicaHistoryQ *history.Q
 	ServerMetrics   *ServerMetrics
+	// LagTolerance is the number of ledgers the replica is allowed to lag
+	// behind the primary before requests fail with a stale history error.
+	LagTolerance uint32
 }
 
 // WrapFunc executes the middleware on a given HTTP handler function
@@ -409,6 +420,11 @@ func (m *ReplicaSyncCheckMiddleware) WrapFunc(h http.HandlerFunc) http.HandlerFu
 			}
 
 			if replicaIngestLedger >= primaryIngestLedger {
+				w.Header().Set(ReplicaLagHeader, "0")
+				break
+			}
+			if lag := primaryIngestLedger - replicaIngestLedger; lag <= m.LagTolerance {
+				w.Header().Set(ReplicaLagHeader, strconv.FormatUint(uint64(lag), 10))
 				break
 			}
 
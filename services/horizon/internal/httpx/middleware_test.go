@@ -1,9 +1,17 @@
 package httpx
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/stellar/go/services/horizon/internal/db2/history"
+	hProblem "github.com/stellar/go/services/horizon/internal/render/problem"
+	"github.com/stellar/go/support/db"
 )
 
 func TestMiddlewareSanitizesRoutesForPrometheus(t *testing.T) {
@@ -45,3 +53,48 @@ func TestMiddlewareSanitizesRoutesForPrometheus(t *testing.T) {
 	}
 
 }
+
+func TestReplicaSyncCheckMiddleware(t *testing.T) {
+	lastLedgerSession := func(ledger string) *db.MockSession {
+		session := &db.MockSession{}
+		session.On("Get", mock.Anything, mock.AnythingOfType("*string"), mock.Anything).
+			Run(func(args mock.Arguments) {
+				*args.Get(1).(*string) = ledger
+			}).
+			Return(nil)
+		return session
+	}
+
+	for _, setup := range []struct {
+		name           string
+		replicaLedger  string
+		tolerance      uint32
+		expectedStatus int
+		expectedLag    string
+	}{
+		{"replica in sync", "100", 0, http.StatusOK, "0"},
+		{"lagging replica within tolerance", "98", 2, http.StatusOK, "2"},
+		{"lagging replica beyond tolerance", "97", 2, hProblem.StaleHistory.Status, ""},
+	} {
+		t.Run(setup.name, func(t *testing.T) {
+			m := ReplicaSyncCheckMiddleware{
+				PrimaryHistoryQ: &history.Q{lastLedgerSession("100")},
+				ReplicaHistoryQ: &history.Q{lastLedgerSession(setup.replicaLedger)},
+				ServerMetrics: &ServerMetrics{
+					ReplicaLagErrorsCounter: prometheus.NewCounter(
+						prometheus.CounterOpts{Name: "replica_lag_errors_count"},
+					),
+				},
+				LagTolerance: setup.tolerance,
+			}
+			handler := m.Wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/accounts", nil))
+			assert.Equal(t, setup.expectedStatus, w.Code)
+			assert.Equal(t, setup.expectedLag, w.Header().Get(ReplicaLagHeader))
+		})
+	}
+}
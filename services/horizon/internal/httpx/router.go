@@ -38,6 +38,8 @@ type RouterConfig struct {
 	BehindAWSLoadBalancer bool
 	SSEUpdateFrequency    time.Duration
 	StaleThreshold        uint
+	ReplicaLagTolerance   uint
+	StrictAccountsParams  bool
 	ConnectionTimeout     time.Duration
 	NetworkPassphrase     string
 	MaxPathLength         uint
@@ -47,6 +49,10 @@ type RouterConfig struct {
 	HorizonVersion        string
 	FriendbotURL          *url.URL
 	HealthCheck           http.Handler
+
+	// AccountsFilterRateQuotas are the quotas of the requests to /accounts
+	// per filter and remote IP, filters without a quota aren't throttled.
+	AccountsFilterRateQuotas map[actions.AccountsFilterType]throttled.RateQuota
 }
 
 type Router struct {
@@ -67,8 +73,19 @@ func NewRouter(config *RouterConfig, serverMetrics *ServerMetrics, ledgerState *
 			return nil, fmt.Errorf("unable to create RateLimiter: %v", err)
 		}
 	}
+	var accountsFilterRateLimiter actions.AccountsFilterRateLimiter
+	if len(config.AccountsFilterRateQuotas) > 0 {
+		var err error
+		accountsFilterRateLimiter, err = actions.NewGCRAAccountsFilterRateLimiter(
+			config.AccountsFilterRateQuotas,
+			VaryByRemoteIP{},
+		)
+		if err != nil {
+			return nil, fmt.Errorf("unable to create accounts filter RateLimiter: %v", err)
+		}
+	}
 	result.addMiddleware(config, rateLimiter, serverMetrics)
-	result.addRoutes(config, rateLimiter, ledgerState)
+	result.addRoutes(config, rateLimiter, accountsFilterRateLimiter, ledgerState)
 	return &result, nil
 }
 
@@ -106,6 +123,7 @@ func (r *Router) addMiddleware(config *RouterConfig,
 			PrimaryHistoryQ: &history.Q{config.PrimaryDBSession},
 			ReplicaHistoryQ: &history.Q{config.DBSession},
 			ServerMetrics:   serverMetrics,
+			LagTolerance:    uint32(config.ReplicaLagTolerance),
 		}
 		r.Use(replicaSyncMiddleware.Wrap)
 	}
@@ -116,7 +134,12 @@ func (r *Router) addMiddleware(config *RouterConfig,
 	r.Internal.Use(loggerMiddleware(serverMetrics))
 }
 
-func (r *Router) addRoutes(config *RouterConfig, rateLimiter *throttled.HTTPRateLimiter, ledgerState *ledger.State) {
+func (r *Router) addRoutes(
+	config *RouterConfig,
+	rateLimiter *throttled.HTTPRateLimiter,
+	accountsFilterRateLimiter actions.AccountsFilterRateLimiter,
+	ledgerState *ledger.State,
+) {
 	stateMiddleware := StateMiddleware{
 		HorizonSession: config.DBSession,
 	}
@@ -140,7 +163,20 @@ func (r *Router) addRoutes(config *RouterConfig, rateLimiter *throttled.HTTPRate
 	// State endpoints behind stateMiddleware
 	r.Group(func(r chi.Router) {
 		r.Route("/accounts", func(r chi.Router) {
-			r.With(stateMiddleware.Wrap).Method(http.MethodGet, "/", restPageHandler(ledgerState, actions.GetAccountsHandler{LedgerState: ledgerState}))
+			accountsHandler := restPageHandler(ledgerState, actions.GetAccountsHandler{
+				LedgerState:       ledgerState,
+				FilterRateLimiter: accountsFilterRateLimiter,
+				StaleThreshold:    config.StaleThreshold,
+				StrictParams:      config.StrictAccountsParams,
+			})
+			r.With(stateMiddleware.Wrap).Method(http.MethodGet, "/", accountsHandler)
+			r.With(stateMiddleware.Wrap).Method(http.MethodHead, "/", headHandler{accountsHandler})
+			r.With(stateMiddleware.Wrap).Method(http.MethodGet, "/trustline_count_histogram", ObjectActionHandler{actions.GetTrustLineCountHistogramHandler{
+				LedgerState: ledgerState,
+				Cache:       &actions.TrustLineCountHistogramCache{},
+			}})
+			r.With(stateMiddleware.Wrap).Method(http.MethodGet, "/trustline_auth_states", ObjectActionHandler{actions.GetTrustLineAuthStatesHandler{}})
+			r.With(stateMiddleware.Wrap).Method(http.MethodGet, "/id_prefix", ObjectActionHandler{actions.GetAccountIDPrefixHandler{}})
 			r.Route("/{account_id}", func(r chi.Router) {
 				r.With(stateMiddleware.Wrap).Method(
 					http.MethodGet,
@@ -148,14 +184,25 @@ func (r *Router) addRoutes(config *RouterConfig, rateLimiter *throttled.HTTPRate
 					streamableObjectActionHandler{
 						streamHandler: streamHandler,
 						action:        actions.GetAccountByIDHandler{},
+						msgpack:       true,
 					},
 				)
+				r.With(stateMiddleware.Wrap).Method(http.MethodHead, "/", headActionHandler{actions.GetAccountByIDHandler{}})
+				r.With(stateMiddleware.Wrap).Method(http.MethodGet, "/data", restPageHandler(ledgerState, actions.GetAccountDataEntriesHandler{LedgerState: ledgerState}))
 				accountData := actions.GetAccountDataHandler{}
 				r.With(stateMiddleware.Wrap).Method(http.MethodGet, "/data/{key}", WrapRaw(
 					streamableObjectActionHandler{streamHandler: streamHandler, action: accountData},
 					accountData,
 				))
+				r.With(stateMiddleware.Wrap).Method(http.MethodGet, "/sequence", streamableObjectActionHandler{
+					streamHandler: streamHandler,
+					action:        actions.GetAccountSequenceHandler{},
+				})
 				r.With(stateMiddleware.Wrap).Method(http.MethodGet, "/offers", streamableStatePageHandler(ledgerState, actions.GetAccountOffersHandler{LedgerState: ledgerState}, streamHandler))
+				r.With(stateMiddleware.Wrap).Method(http.MethodGet, "/balances", restPageHandler(ledgerState, actions.GetAccountBalancesHandler{LedgerState: ledgerState}))
+				r.With(stateMiddleware.Wrap).Method(http.MethodGet, "/trustlines/{asset}/exists", ObjectActionHandler{actions.GetTrustLineExistsHandler{}})
+				r.With(stateMiddleware.Wrap).Method(http.MethodGet, "/summary", ObjectActionHandler{actions.GetAccountSummaryHandler{}})
+				r.With(stateMiddleware.Wrap).Method(http.MethodPost, "/simulate-threshold", ObjectActionHandler{actions.SimulateThresholdHandler{}})
 			})
 		})
 
@@ -214,6 +261,7 @@ func (r *Router) addRoutes(config *RouterConfig, rateLimiter *throttled.HTTPRate
 		}, streamHandler))
 		r.With(historyMiddleware).Method(http.MethodGet, "/accounts/{account_id:\\w+}/trades", streamableHistoryPageHandler(ledgerState, actions.GetTradesHandler{LedgerState: ledgerState}, streamHandler))
 		r.With(historyMiddleware).Method(http.MethodGet, "/accounts/{account_id:\\w+}/transactions", streamableHistoryPageHandler(ledgerState, actions.GetTransactionsHandler{LedgerState: ledgerState}, streamHandler))
+		r.With(historyMiddleware).Method(http.MethodGet, "/accounts/{account_id:\\w+}/trustline_authorizations", restPageHandler(ledgerState, actions.GetTrustLineAuthorizationsHandler{LedgerState: ledgerState}))
 	})
 	// ledger actions
 	r.Route("/ledgers", func(r chi.Router) {
@@ -313,6 +361,7 @@ func (r *Router) addRoutes(config *RouterConfig, rateLimiter *throttled.HTTPRate
 	})
 
 	// internal
+	r.Internal.With(stateMiddleware.Wrap).Method(http.MethodGet, "/accounts", ObjectActionHandler{actions.ExplainAccountsHandler{LedgerState: ledgerState}})
 	r.Internal.Get("/metrics", promhttp.HandlerFor(config.PrometheusRegistry, promhttp.HandlerOpts{}).ServeHTTP)
 	r.Internal.Get("/debug/pprof/heap", pprof.Index)
 	r.Internal.Get("/debug/pprof/profile", pprof.Profile)
@@ -239,6 +239,7 @@ type mockDBQ struct {
 	history.MockQSigners
 	history.MockQTransactions
 	history.MockQTrustLines
+	history.MockQTrustLineAuthorizations
 }
 
 func (m *mockDBQ) Begin() error {
@@ -104,16 +104,23 @@ func (s *ProcessorRunner) buildChangeProcessor(
 	}
 
 	useLedgerCache := source == ledgerSource
-	return newGroupChangeProcessors([]horizonChangeProcessor{
+	changeProcessors := []horizonChangeProcessor{
 		statsChangeProcessor,
 		processors.NewAccountDataProcessor(s.historyQ),
 		processors.NewAccountsProcessor(s.historyQ),
 		processors.NewOffersProcessor(s.historyQ, ledgerSequence),
 		processors.NewAssetStatsProcessor(s.historyQ, useLedgerCache),
-		processors.NewSignersProcessor(s.historyQ, useLedgerCache),
+		processors.NewSignersProcessor(s.historyQ, useLedgerCache, s.config.SignerChangeNotifier),
 		processors.NewTrustLinesProcessor(s.historyQ),
 		processors.NewClaimableBalancesChangeProcessor(s.historyQ),
-	})
+	}
+	if source == ledgerSource {
+		changeProcessors = append(
+			changeProcessors,
+			processors.NewTrustLineAuthorizationsProcessor(s.historyQ, ledgerSequence),
+		)
+	}
+	return newGroupChangeProcessors(changeProcessors)
 }
 
 func (s *ProcessorRunner) buildTransactionProcessor(
@@ -254,6 +254,7 @@ func TestProcessorRunnerBuildChangeProcessor(t *testing.T) {
 	assert.True(t, reflect.ValueOf(processor.processors[5]).
 		Elem().FieldByName("useLedgerEntryCache").Bool())
 	assert.IsType(t, &processors.TrustLinesProcessor{}, processor.processors[6])
+	assert.IsType(t, &processors.TrustLineAuthorizationsProcessor{}, processor.processors[8])
 
 	runner = ProcessorRunner{
 		ctx:      ctx,
@@ -274,6 +275,7 @@ func TestProcessorRunnerBuildChangeProcessor(t *testing.T) {
 	assert.False(t, reflect.ValueOf(processor.processors[5]).
 		Elem().FieldByName("useLedgerEntryCache").Bool())
 	assert.IsType(t, &processors.TrustLinesProcessor{}, processor.processors[6])
+	assert.Len(t, processor.processors, 8)
 }
 
 func TestProcessorRunnerBuildTransactionProcessor(t *testing.T) {
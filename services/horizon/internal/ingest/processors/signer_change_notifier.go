@@ -0,0 +1,24 @@
+package processors
+
+import "context"
+
+// SignerChangeNotifier is notified by SignersProcessor when the signers of
+// an account change in an ingested ledger. It allows operators to forward
+// signer changes to external systems, like webhooks.
+type SignerChangeNotifier interface {
+	// SignersChanged is called after the signers of account are updated in
+	// the database but before the ingestion transaction is committed, so a
+	// notification can be repeated if the ledger is ingested again. pre and
+	// post map signer keys to weights; pre is nil when the account was created
+	// and post is nil when it was removed. Implementations must not block
+	// ingestion: slow deliveries should be done asynchronously.
+	SignersChanged(ctx context.Context, account string, pre, post map[string]int32)
+}
+
+// NoopSignerChangeNotifier is a SignerChangeNotifier ignoring all changes.
+// It is used when no notifier is configured.
+type NoopSignerChangeNotifier struct{}
+
+// SignersChanged implements SignerChangeNotifier.
+func (NoopSignerChangeNotifier) SignersChanged(context.Context, string, map[string]int32, map[string]int32) {
+}
@@ -12,9 +12,18 @@ import (
 	"github.com/stellar/go/services/horizon/internal/db2/history"
 	"github.com/stellar/go/support/errors"
 	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 )
 
+type mockSignerChangeNotifier struct {
+	mock.Mock
+}
+
+func (m *mockSignerChangeNotifier) SignersChanged(ctx context.Context, account string, pre, post map[string]int32) {
+	m.Called(ctx, account, pre, post)
+}
+
 func TestAccountsSignerProcessorTestSuiteState(t *testing.T) {
 	suite.Run(t, new(AccountsSignerProcessorTestSuiteState))
 }
@@ -36,7 +45,7 @@ func (s *AccountsSignerProcessorTestSuiteState) SetupTest() {
 		On("NewAccountSignersBatchInsertBuilder", maxBatchSize).
 		Return(s.mockBatchInsertBuilder).Once()
 
-	s.processor = NewSignersProcessor(s.mockQ, false)
+	s.processor = NewSignersProcessor(s.mockQ, false, nil)
 }
 
 func (s *AccountsSignerProcessorTestSuiteState) TearDownTest() {
@@ -168,7 +177,7 @@ func (s *AccountsSignerProcessorTestSuiteLedger) SetupTest() {
 		On("NewAccountSignersBatchInsertBuilder", maxBatchSize).
 		Return(&history.MockAccountSignersBatchInsertBuilder{}).Once()
 
-	s.processor = NewSignersProcessor(s.mockQ, true)
+	s.processor = NewSignersProcessor(s.mockQ, true, nil)
 }
 
 func (s *AccountsSignerProcessorTestSuiteLedger) TearDownTest() {
@@ -209,6 +218,51 @@ func (s *AccountsSignerProcessorTestSuiteLedger) TestNewAccount() {
 	s.Assert().NoError(s.processor.Commit(s.ctx))
 }
 
+func (s *AccountsSignerProcessorTestSuiteLedger) TestSignerChangeNotifier() {
+	notifier := &mockSignerChangeNotifier{}
+	defer notifier.AssertExpectations(s.T())
+	s.mockQ.
+		On("NewAccountSignersBatchInsertBuilder", maxBatchSize).
+		Return(&history.MockAccountSignersBatchInsertBuilder{}).Once()
+	s.processor = NewSignersProcessor(s.mockQ, true, notifier)
+
+	s.mockQ.
+		On(
+			"CreateAccountSigner",
+			s.ctx,
+			"GC3C4AKRBQLHOJ45U4XG35ESVWRDECWO5XLDGYADO6DPR3L7KIDVUMML",
+			"GC3C4AKRBQLHOJ45U4XG35ESVWRDECWO5XLDGYADO6DPR3L7KIDVUMML",
+			int32(1),
+			(*string)(nil),
+		).
+		Return(int64(1), nil).Once()
+	notifier.
+		On(
+			"SignersChanged",
+			s.ctx,
+			"GC3C4AKRBQLHOJ45U4XG35ESVWRDECWO5XLDGYADO6DPR3L7KIDVUMML",
+			map[string]int32(nil),
+			map[string]int32{"GC3C4AKRBQLHOJ45U4XG35ESVWRDECWO5XLDGYADO6DPR3L7KIDVUMML": 1},
+		).
+		Once()
+
+	err := s.processor.ProcessChange(s.ctx, ingest.Change{
+		Type: xdr.LedgerEntryTypeAccount,
+		Pre:  nil,
+		Post: &xdr.LedgerEntry{
+			Data: xdr.LedgerEntryData{
+				Type: xdr.LedgerEntryTypeAccount,
+				Account: &xdr.AccountEntry{
+					AccountId:  xdr.MustAddress("GC3C4AKRBQLHOJ45U4XG35ESVWRDECWO5XLDGYADO6DPR3L7KIDVUMML"),
+					Thresholds: [4]byte{1, 1, 1, 1},
+				},
+			},
+		},
+	})
+	s.Assert().NoError(err)
+	s.Assert().NoError(s.processor.Commit(s.ctx))
+}
+
 func (s *AccountsSignerProcessorTestSuiteLedger) TestNoUpdatesWhenNoSignerChanges() {
 	err := s.processor.ProcessChange(s.ctx, ingest.Change{
 		Type: xdr.LedgerEntryTypeAccount,
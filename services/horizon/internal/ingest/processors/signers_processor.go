@@ -20,12 +20,24 @@ type SignersProcessor struct {
 	// add signers to a batch, then we Exec all signers in one insert query.
 	// This is done to make history buckets processing faster (batch inserting).
 	useLedgerEntryCache bool
+	// notifier is called when the signers of an account change. It's only
+	// used with the ledger cache as history buckets don't contain changes.
+	notifier SignerChangeNotifier
 }
 
+// NewSignersProcessor creates a SignersProcessor. notifier is optional, when
+// nil signer changes are not notified.
 func NewSignersProcessor(
-	signersQ history.QSigners, useLedgerEntryCache bool,
+	signersQ history.QSigners, useLedgerEntryCache bool, notifier SignerChangeNotifier,
 ) *SignersProcessor {
-	p := &SignersProcessor{signersQ: signersQ, useLedgerEntryCache: useLedgerEntryCache}
+	if notifier == nil {
+		notifier = NoopSignerChangeNotifier{}
+	}
+	p := &SignersProcessor{
+		signersQ:            signersQ,
+		useLedgerEntryCache: useLedgerEntryCache,
+		notifier:            notifier,
+	}
 	p.reset()
 	return p
 }
@@ -96,11 +108,16 @@ func (p *SignersProcessor) Commit(ctx context.Context) error {
 			continue
 		}
 
+		var account string
+		var preSigners, postSigners map[string]int32
+
 		// The code below removes all Pre signers adds Post signers but
 		// can be improved by finding a diff (check performance first).
 		if change.Pre != nil {
 			preAccountEntry := change.Pre.Data.MustAccount()
-			for signer := range preAccountEntry.SignerSummary() {
+			account = preAccountEntry.AccountId.Address()
+			preSigners = preAccountEntry.SignerSummary()
+			for signer := range preSigners {
 				rowsAffected, err := p.signersQ.RemoveAccountSigner(ctx, preAccountEntry.AccountId.Address(), signer)
 				if err != nil {
 					return errors.Wrap(err, "Error removing a signer")
@@ -119,8 +136,10 @@ func (p *SignersProcessor) Commit(ctx context.Context) error {
 
 		if change.Post != nil {
 			postAccountEntry := change.Post.Data.MustAccount()
+			account = postAccountEntry.AccountId.Address()
+			postSigners = postAccountEntry.SignerSummary()
 			sponsorsPerSigner := postAccountEntry.SponsorPerSigner()
-			for signer, weight := range postAccountEntry.SignerSummary() {
+			for signer, weight := range postSigners {
 
 				// Ignore master key
 				var sponsor *string
@@ -151,6 +170,8 @@ func (p *SignersProcessor) Commit(ctx context.Context) error {
 				}
 			}
 		}
+
+		p.notifier.SignersChanged(ctx, account, preSigners, postSigners)
 	}
 
 	return nil
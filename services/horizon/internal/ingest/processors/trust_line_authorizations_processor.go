@@ -0,0 +1,92 @@
+package processors
+
+import (
+	"context"
+
+	"github.com/stellar/go/ingest"
+	"github.com/stellar/go/services/horizon/internal/db2/history"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/xdr"
+)
+
+const trustLineAuthorizationFlags = xdr.TrustLineFlagsAuthorizedFlag |
+	xdr.TrustLineFlagsAuthorizedToMaintainLiabilitiesFlag
+
+// TrustLineAuthorizationsProcessor records the authorization flags of trust
+// lines created in a ledger or whose authorization changed in a ledger. It
+// must only process ledger changes: changes read from history archives do not
+// correspond to authorization transitions.
+type TrustLineAuthorizationsProcessor struct {
+	authorizationsQ history.QTrustLineAuthorizations
+	sequence        uint32
+
+	cache *ingest.ChangeCompactor
+}
+
+func NewTrustLineAuthorizationsProcessor(
+	authorizationsQ history.QTrustLineAuthorizations,
+	sequence uint32,
+) *TrustLineAuthorizationsProcessor {
+	p := &TrustLineAuthorizationsProcessor{
+		authorizationsQ: authorizationsQ,
+		sequence:        sequence,
+	}
+	p.reset()
+	return p
+}
+
+func (p *TrustLineAuthorizationsProcessor) reset() {
+	p.cache = ingest.NewChangeCompactor()
+}
+
+func (p *TrustLineAuthorizationsProcessor) ProcessChange(ctx context.Context, change ingest.Change) error {
+	if change.Type != xdr.LedgerEntryTypeTrustline {
+		return nil
+	}
+
+	err := p.cache.AddChange(change)
+	if err != nil {
+		return errors.Wrap(err, "error adding to ledgerCache")
+	}
+
+	if p.cache.Size() > maxBatchSize {
+		err = p.Commit(ctx)
+		if err != nil {
+			return errors.Wrap(err, "error in Commit")
+		}
+		p.reset()
+	}
+
+	return nil
+}
+
+func (p *TrustLineAuthorizationsProcessor) Commit(ctx context.Context) error {
+	authorizations := []xdr.LedgerEntry{}
+
+	for _, change := range p.cache.GetChanges() {
+		if change.Post == nil {
+			// Removed trust lines are not authorization transitions
+			continue
+		}
+
+		post := change.Post.Data.MustTrustLine().Flags
+		if change.Pre != nil {
+			pre := change.Pre.Data.MustTrustLine().Flags
+			if xdr.TrustLineFlags(pre)&trustLineAuthorizationFlags ==
+				xdr.TrustLineFlags(post)&trustLineAuthorizationFlags {
+				continue
+			}
+		}
+
+		authorizations = append(authorizations, *change.Post)
+	}
+
+	if len(authorizations) > 0 {
+		err := p.authorizationsQ.InsertTrustLineAuthorizations(ctx, p.sequence, authorizations)
+		if err != nil {
+			return errors.Wrap(err, "errors in InsertTrustLineAuthorizations")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,99 @@
+//lint:file-ignore U1001 Ignore all unused code, staticcheck doesn't understand testify/suite
+
+package processors
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/stellar/go/ingest"
+	"github.com/stellar/go/services/horizon/internal/db2/history"
+	"github.com/stellar/go/xdr"
+)
+
+func TestTrustLineAuthorizationsProcessorTestSuite(t *testing.T) {
+	suite.Run(t, new(TrustLineAuthorizationsProcessorTestSuite))
+}
+
+type TrustLineAuthorizationsProcessorTestSuite struct {
+	suite.Suite
+	ctx       context.Context
+	processor *TrustLineAuthorizationsProcessor
+	mockQ     *history.MockQTrustLineAuthorizations
+}
+
+func (s *TrustLineAuthorizationsProcessorTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.mockQ = &history.MockQTrustLineAuthorizations{}
+	s.processor = NewTrustLineAuthorizationsProcessor(s.mockQ, 123)
+}
+
+func (s *TrustLineAuthorizationsProcessorTestSuite) TearDownTest() {
+	s.mockQ.AssertExpectations(s.T())
+}
+
+func trustLineEntryWithFlags(flags xdr.TrustLineFlags) *xdr.LedgerEntry {
+	return &xdr.LedgerEntry{
+		Data: xdr.LedgerEntryData{
+			Type: xdr.LedgerEntryTypeTrustline,
+			TrustLine: &xdr.TrustLineEntry{
+				AccountId: xdr.MustAddress("GAOQJGUAB7NI7K7I62ORBXMN3J4SSWQUQ7FOEPSDJ322W2HMCNWPHXFB"),
+				Asset:     xdr.MustNewCreditAsset("EUR", trustLineIssuer.Address()),
+				Flags:     xdr.Uint32(flags),
+			},
+		},
+		LastModifiedLedgerSeq: 123,
+	}
+}
+
+func (s *TrustLineAuthorizationsProcessorTestSuite) TestCreateTrustLine() {
+	post := trustLineEntryWithFlags(xdr.TrustLineFlagsAuthorizedFlag)
+	s.Assert().NoError(s.processor.ProcessChange(s.ctx, ingest.Change{
+		Type: xdr.LedgerEntryTypeTrustline,
+		Pre:  nil,
+		Post: post,
+	}))
+
+	s.mockQ.On("InsertTrustLineAuthorizations", s.ctx, uint32(123), []xdr.LedgerEntry{*post}).
+		Return(nil).Once()
+	s.Assert().NoError(s.processor.Commit(s.ctx))
+}
+
+func (s *TrustLineAuthorizationsProcessorTestSuite) TestDeauthorizeTrustLine() {
+	post := trustLineEntryWithFlags(xdr.TrustLineFlagsAuthorizedToMaintainLiabilitiesFlag)
+	s.Assert().NoError(s.processor.ProcessChange(s.ctx, ingest.Change{
+		Type: xdr.LedgerEntryTypeTrustline,
+		Pre:  trustLineEntryWithFlags(xdr.TrustLineFlagsAuthorizedFlag),
+		Post: post,
+	}))
+
+	s.mockQ.On("InsertTrustLineAuthorizations", s.ctx, uint32(123), []xdr.LedgerEntry{*post}).
+		Return(nil).Once()
+	s.Assert().NoError(s.processor.Commit(s.ctx))
+}
+
+func (s *TrustLineAuthorizationsProcessorTestSuite) TestUpdateWithoutAuthorizationChange() {
+	s.Assert().NoError(s.processor.ProcessChange(s.ctx, ingest.Change{
+		Type: xdr.LedgerEntryTypeTrustline,
+		Pre:  trustLineEntryWithFlags(xdr.TrustLineFlagsAuthorizedFlag),
+		Post: trustLineEntryWithFlags(
+			xdr.TrustLineFlagsAuthorizedFlag | xdr.TrustLineFlagsTrustlineClawbackEnabledFlag,
+		),
+	}))
+
+	// InsertTrustLineAuthorizations is not called
+	s.Assert().NoError(s.processor.Commit(s.ctx))
+}
+
+func (s *TrustLineAuthorizationsProcessorTestSuite) TestRemoveTrustLine() {
+	s.Assert().NoError(s.processor.ProcessChange(s.ctx, ingest.Change{
+		Type: xdr.LedgerEntryTypeTrustline,
+		Pre:  trustLineEntryWithFlags(xdr.TrustLineFlagsAuthorizedFlag),
+		Post: nil,
+	}))
+
+	// InsertTrustLineAuthorizations is not called
+	s.Assert().NoError(s.processor.Commit(s.ctx))
+}
@@ -76,5 +76,10 @@ func (r *System) clearBefore(ctx context.Context, seq int32) error {
 		return err
 	}
 
+	err = r.HistoryQ.DeleteTrustLineAuthorizationsBefore(ctx, uint32(seq))
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
@@ -3,8 +3,10 @@ package reap
 import (
 	"testing"
 
+	"github.com/stellar/go/services/horizon/internal/db2/history"
 	"github.com/stellar/go/services/horizon/internal/ledger"
 	"github.com/stellar/go/services/horizon/internal/test"
+	"github.com/stellar/go/xdr"
 )
 
 func TestDeleteUnretainedHistory(t *testing.T) {
@@ -49,3 +51,37 @@ func TestDeleteUnretainedHistory(t *testing.T) {
 		tt.Assert.Equal(1, cur)
 	}
 }
+
+func TestDeleteUnretainedTrustLineAuthorizations(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	tt.Scenario("kahuna")
+	ledgerState := &ledger.State{}
+	ledgerState.SetStatus(tt.LoadLedgerStatus())
+
+	db := tt.HorizonSession()
+	q := &history.Q{db}
+
+	trustLine := xdr.LedgerEntry{
+		Data: xdr.LedgerEntryData{
+			Type: xdr.LedgerEntryTypeTrustline,
+			TrustLine: &xdr.TrustLineEntry{
+				AccountId: xdr.MustAddress("GAOQJGUAB7NI7K7I62ORBXMN3J4SSWQUQ7FOEPSDJ322W2HMCNWPHXFB"),
+				Asset:     xdr.MustNewCreditAsset("USD", "GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H"),
+				Flags:     xdr.Uint32(xdr.TrustLineFlagsAuthorizedFlag),
+			},
+		},
+	}
+	elder := ledgerState.CurrentStatus().HistoryElder
+	latest := ledgerState.CurrentStatus().HistoryLatest
+	tt.Require.NoError(q.InsertTrustLineAuthorizations(tt.Ctx, uint32(elder), []xdr.LedgerEntry{trustLine}))
+	tt.Require.NoError(q.InsertTrustLineAuthorizations(tt.Ctx, uint32(latest), []xdr.LedgerEntry{trustLine}))
+
+	sys := New(1, db, ledgerState)
+	tt.Require.NoError(sys.DeleteUnretainedHistory(tt.Ctx))
+
+	var ledgers []int32
+	err := db.SelectRaw(tt.Ctx, &ledgers, `SELECT ledger_sequence FROM history_trust_lines_authorizations`)
+	tt.Require.NoError(err)
+	tt.Assert.Equal([]int32{latest}, ledgers)
+}
@@ -10,4 +10,6 @@ const (
 	MimeJSON = "application/json"
 	//MimeRaw is the mime type for "application/octet-stream"
 	MimeRaw = "application/octet-stream"
+	//MimeMsgpack is the mime type for "application/msgpack"
+	MimeMsgpack = "application/msgpack"
 )
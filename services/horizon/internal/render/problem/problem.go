@@ -113,4 +113,14 @@ var (
 		Detail: "Data cannot be presented because it's still being ingested. Please " +
 			"wait for several minutes before trying your request again.",
 	}
+
+	// HistoryUnavailable is a well-known problem type.  Use it as a shortcut
+	// in your actions.
+	HistoryUnavailable = problem.P{
+		Type:   "history_unavailable",
+		Title:  "History Database Unavailable",
+		Status: http.StatusServiceUnavailable,
+		Detail: "This horizon instance cannot reach its history database at the " +
+			"moment. Please try again later.",
+	}
 )
@@ -2,17 +2,50 @@ package resourceadapter
 
 import (
 	"context"
+	"encoding/hex"
 	"fmt"
 	"strconv"
 
+	"github.com/stellar/go/amount"
 	protocol "github.com/stellar/go/protocols/horizon"
 	horizonContext "github.com/stellar/go/services/horizon/internal/context"
 	"github.com/stellar/go/services/horizon/internal/db2/history"
+	"github.com/stellar/go/strkey"
 	"github.com/stellar/go/support/errors"
 	"github.com/stellar/go/support/render/hal"
 	"github.com/stellar/go/xdr"
 )
 
+// clawbackProtocolVersion is the protocol version which introduced the
+// AUTH_CLAWBACK_ENABLED flag.
+const clawbackProtocolVersion = 17
+
+// PreClawbackAccountFlags are the flags of the accounts last modified before
+// protocol 17, which introduced AUTH_CLAWBACK_ENABLED.
+type PreClawbackAccountFlags struct {
+	AuthRequired  bool `json:"auth_required"`
+	AuthRevocable bool `json:"auth_revocable"`
+	AuthImmutable bool `json:"auth_immutable"`
+}
+
+// AccountFlagsPredateClawback reports whether an account last modified in
+// ledger predates AUTH_CLAWBACK_ENABLED. Its flags are then rendered as
+// PreClawbackAccountFlags, leaving out the flag which didn't exist yet
+// instead of rendering it as false. The protocol version is unknown when the
+// ledger isn't ingested, all the flags are rendered then.
+func AccountFlagsPredateClawback(ledger *history.Ledger) bool {
+	return ledger != nil && ledger.ProtocolVersion < clawbackProtocolVersion
+}
+
+// NewPreClawbackAccountFlags returns flags without AUTH_CLAWBACK_ENABLED.
+func NewPreClawbackAccountFlags(flags protocol.AccountFlags) PreClawbackAccountFlags {
+	return PreClawbackAccountFlags{
+		AuthRequired:  flags.AuthRequired,
+		AuthRevocable: flags.AuthRevocable,
+		AuthImmutable: flags.AuthImmutable,
+	}
+}
+
 // PopulateAccountEntry fills out the resource's fields
 func PopulateAccountEntry(
 	ctx context.Context,
@@ -27,6 +60,10 @@ func PopulateAccountEntry(
 	dest.PT = account.AccountID
 	dest.AccountID = account.AccountID
 	dest.Sequence = strconv.FormatInt(account.SequenceNumber, 10)
+	// Sequence numbers start at the ledger in which the account was created
+	// shifted by 32 bits, the lower bits are bumped by every transaction.
+	dest.SequenceLedger = uint32(account.SequenceNumber >> 32)
+	dest.SequenceBump = uint32(account.SequenceNumber)
 	dest.SubentryCount = int32(account.NumSubEntries)
 	dest.InflationDestination = account.InflationDestination
 	dest.HomeDomain = account.HomeDomain
@@ -35,34 +72,19 @@ func PopulateAccountEntry(
 		dest.LastModifiedTime = &ledger.ClosedAt
 	}
 
-	dest.Flags.AuthRequired = account.IsAuthRequired()
-	dest.Flags.AuthRevocable = account.IsAuthRevocable()
-	dest.Flags.AuthImmutable = account.IsAuthImmutable()
-	dest.Flags.AuthClawbackEnabled = account.IsAuthClawbackEnabled()
+	PopulateAccountFlags(&dest.Flags, account)
 
 	dest.Thresholds.LowThreshold = account.ThresholdLow
 	dest.Thresholds.MedThreshold = account.ThresholdMedium
 	dest.Thresholds.HighThreshold = account.ThresholdHigh
+	dest.MasterKeyWeight = int32(account.MasterWeight)
+	dest.MasterKeyDisabled = account.MasterWeight == 0
 
-	// populate balances
-	dest.Balances = make([]protocol.Balance, len(trustLines)+1)
-	for i, tl := range trustLines {
-		err := PopulateBalance(&dest.Balances[i], tl)
-		if err != nil {
-			return errors.Wrap(err, "populating balance")
-		}
-	}
-
-	// add native balance
-	err := PopulateNativeBalance(
-		&dest.Balances[len(dest.Balances)-1],
-		xdr.Int64(account.Balance),
-		xdr.Int64(account.BuyingLiabilities),
-		xdr.Int64(account.SellingLiabilities),
-	)
+	balances, err := PopulateAccountBalances(account, trustLines)
 	if err != nil {
-		return errors.Wrap(err, "populating native balance")
+		return err
 	}
+	dest.Balances = balances
 
 	// populate data
 	dest.Data = make(map[string]string)
@@ -81,6 +103,9 @@ func PopulateAccountEntry(
 		if signer.Sponsor.Valid {
 			dest.Signers[i].Sponsor = signer.Sponsor.String
 		}
+		if err = populateSignerHash(&dest.Signers[i]); err != nil {
+			return err
+		}
 
 		if account.AccountID == signer.Signer {
 			masterKeyIncluded = true
@@ -95,6 +120,8 @@ func PopulateAccountEntry(
 		})
 	}
 
+	dest.IsImmutable = isAccountImmutable(dest.Signers, account.ThresholdLow)
+
 	dest.NumSponsoring = account.NumSponsoring
 	dest.NumSponsored = account.NumSponsored
 	if account.Sponsor.Valid {
@@ -114,3 +141,287 @@ func PopulateAccountEntry(
 	dest.Links.Data.PopulateTemplated()
 	return nil
 }
+
+// PopulateAccountSummary fills out the resource's fields like
+// PopulateAccountEntry, except the signers, the trust lines and the data of
+// the account are only counted. The signers and the data are left out and the
+// balances only include the native balance.
+func PopulateAccountSummary(
+	ctx context.Context,
+	dest *protocol.Account,
+	account history.AccountEntry,
+	signers history.AccountSignersCount,
+	numTrustLines int,
+	numData int,
+	ledger *history.Ledger,
+) error {
+	if err := PopulateAccountEntry(ctx, dest, account, nil, nil, nil, ledger); err != nil {
+		return err
+	}
+
+	dest.Signers = nil
+	dest.Data = nil
+	dest.IsImmutable = isWeightTooLow(signers.TotalWeight, account.ThresholdLow)
+	dest.Summary = &protocol.AccountSummary{
+		NumSigners:    signers.Count,
+		NumTrustLines: numTrustLines,
+		NumData:       numData,
+	}
+	return nil
+}
+
+// PopulateAccountActivitySummary fills out the compact summary of an account
+// from its entry and the counts of its sub-entries.
+func PopulateAccountActivitySummary(
+	dest *protocol.AccountActivitySummary,
+	account history.AccountEntry,
+	subentries history.AccountSubentriesSummary,
+) {
+	dest.AccountID = account.AccountID
+	// the native balance is always included
+	dest.NumBalances = subentries.NumTrustLines + 1
+	dest.NativeBalance = amount.StringFromInt64(account.Balance)
+	dest.NumSigners = subentries.NumSigners
+	dest.NumData = subentries.NumData
+	PopulateAccountFlags(&dest.Flags, account)
+	dest.LastActivityLedger = account.LastModifiedLedger
+	if subentries.LastModifiedLedger > dest.LastActivityLedger {
+		dest.LastActivityLedger = subentries.LastModifiedLedger
+	}
+}
+
+// PopulateMinBalance sets the minimum balance of the account given the base
+// reserve: two base entries plus one per sub-entry and per entry sponsored
+// by the account, minus the entries sponsored by others. It never goes below
+// the two base entries.
+func PopulateMinBalance(dest *protocol.Account, baseReserve int32) {
+	entries := int64(2) + int64(dest.SubentryCount) +
+		int64(dest.NumSponsoring) - int64(dest.NumSponsored)
+	if entries < 2 {
+		entries = 2
+	}
+	dest.MinBalanceStroops = strconv.FormatInt(entries*int64(baseReserve), 10)
+}
+
+// PopulateSponsorshipReserves sets the reserve paid by the sponsors of the
+// signers and the trust lines of the account given the base reserve, every
+// sponsored entry costs its sponsor one base reserve. The entries which
+// aren't sponsored are left without reserve.
+func PopulateSponsorshipReserves(dest *protocol.Account, baseReserve int32) {
+	reserve := strconv.FormatInt(int64(baseReserve), 10)
+	for i := range dest.Signers {
+		if dest.Signers[i].Sponsor != "" {
+			dest.Signers[i].ReserveStroops = reserve
+		}
+	}
+	for i := range dest.Balances {
+		if dest.Balances[i].Sponsor != "" {
+			dest.Balances[i].ReserveStroops = reserve
+		}
+	}
+}
+
+// AccountEntryFromResource reconstructs the ledger entry of an account from
+// its resource, it is the inverse of PopulateAccountEntry. The master key is
+// taken out of the signers, the balance and the liabilities come from the
+// native balance and the extensions are only set when they hold values.
+func AccountEntryFromResource(account protocol.Account) (xdr.AccountEntry, error) {
+	var entry xdr.AccountEntry
+	if err := entry.AccountId.SetAddress(account.AccountID); err != nil {
+		return entry, errors.Wrap(err, "invalid account id")
+	}
+
+	sequence, err := strconv.ParseInt(account.Sequence, 10, 64)
+	if err != nil {
+		return entry, errors.Wrap(err, "invalid sequence")
+	}
+	entry.SeqNum = xdr.SequenceNumber(sequence)
+	entry.NumSubEntries = xdr.Uint32(account.SubentryCount)
+	if account.InflationDestination != "" {
+		var inflationDest xdr.AccountId
+		if err = inflationDest.SetAddress(account.InflationDestination); err != nil {
+			return entry, errors.Wrap(err, "invalid inflation destination")
+		}
+		entry.InflationDest = &inflationDest
+	}
+	entry.HomeDomain = xdr.String32(account.HomeDomain)
+
+	if account.Flags.AuthRequired {
+		entry.Flags |= xdr.Uint32(xdr.AccountFlagsAuthRequiredFlag)
+	}
+	if account.Flags.AuthRevocable {
+		entry.Flags |= xdr.Uint32(xdr.AccountFlagsAuthRevocableFlag)
+	}
+	if account.Flags.AuthImmutable {
+		entry.Flags |= xdr.Uint32(xdr.AccountFlagsAuthImmutableFlag)
+	}
+	if account.Flags.AuthClawbackEnabled {
+		entry.Flags |= xdr.Uint32(xdr.AccountFlagsAuthClawbackEnabledFlag)
+	}
+
+	entry.Thresholds = xdr.Thresholds{
+		byte(account.MasterKeyWeight),
+		account.Thresholds.LowThreshold,
+		account.Thresholds.MedThreshold,
+		account.Thresholds.HighThreshold,
+	}
+
+	var liabilities xdr.Liabilities
+	nativeBalanceFound := false
+	for _, balance := range account.Balances {
+		if balance.Type != "native" {
+			continue
+		}
+		nativeBalanceFound = true
+		if entry.Balance, err = amount.Parse(balance.Balance); err != nil {
+			return entry, errors.Wrap(err, "invalid native balance")
+		}
+		if balance.BuyingLiabilities != "" {
+			if liabilities.Buying, err = amount.Parse(balance.BuyingLiabilities); err != nil {
+				return entry, errors.Wrap(err, "invalid buying liabilities")
+			}
+		}
+		if balance.SellingLiabilities != "" {
+			if liabilities.Selling, err = amount.Parse(balance.SellingLiabilities); err != nil {
+				return entry, errors.Wrap(err, "invalid selling liabilities")
+			}
+		}
+	}
+	if !nativeBalanceFound {
+		return entry, errors.New("native balance is missing")
+	}
+
+	var sponsoringIDs []xdr.SponsorshipDescriptor
+	hasSponsoredSigners := false
+	for _, signer := range account.Signers {
+		if signer.Key == account.AccountID {
+			continue
+		}
+		var key xdr.SignerKey
+		if err = key.SetAddress(signer.Key); err != nil {
+			return entry, errors.Wrapf(err, "invalid signer %s", signer.Key)
+		}
+		entry.Signers = append(entry.Signers, xdr.Signer{
+			Key:    key,
+			Weight: xdr.Uint32(signer.Weight),
+		})
+
+		var sponsor xdr.SponsorshipDescriptor
+		if signer.Sponsor != "" {
+			var sponsorID xdr.AccountId
+			if err = sponsorID.SetAddress(signer.Sponsor); err != nil {
+				return entry, errors.Wrapf(err, "invalid sponsor of signer %s", signer.Key)
+			}
+			sponsor = &sponsorID
+			hasSponsoredSigners = true
+		}
+		sponsoringIDs = append(sponsoringIDs, sponsor)
+	}
+
+	hasSponsorships := account.NumSponsored > 0 || account.NumSponsoring > 0 || hasSponsoredSigners
+	if liabilities.Buying == 0 && liabilities.Selling == 0 && !hasSponsorships {
+		return entry, nil
+	}
+	v1 := xdr.AccountEntryExtensionV1{Liabilities: liabilities}
+	if hasSponsorships {
+		v1.Ext = xdr.AccountEntryExtensionV1Ext{
+			V: 2,
+			V2: &xdr.AccountEntryExtensionV2{
+				NumSponsored:        xdr.Uint32(account.NumSponsored),
+				NumSponsoring:       xdr.Uint32(account.NumSponsoring),
+				SignerSponsoringIDs: sponsoringIDs,
+			},
+		}
+	}
+	entry.Ext = xdr.AccountEntryExt{V: 1, V1: &v1}
+	return entry, nil
+}
+
+// populateSignerHash fills the hex encoded hash of sha256_hash and
+// preauth_tx signers from the payload of their strkey.
+func populateSignerHash(dest *protocol.Signer) error {
+	var versionByte strkey.VersionByte
+	var field *string
+	switch dest.Type {
+	case protocol.KeyTypeNames[strkey.VersionByteHashX]:
+		versionByte, field = strkey.VersionByteHashX, &dest.HashHex
+	case protocol.KeyTypeNames[strkey.VersionByteHashTx]:
+		versionByte, field = strkey.VersionByteHashTx, &dest.PreauthTxHashHex
+	default:
+		return nil
+	}
+
+	hash, err := strkey.Decode(versionByte, dest.Key)
+	if err != nil {
+		return errors.Wrapf(err, "decoding signer %s", dest.Key)
+	}
+	*field = hex.EncodeToString(hash)
+	return nil
+}
+
+// PopulateAccountFlags fills out the flags of the account.
+func PopulateAccountFlags(dest *protocol.AccountFlags, account history.AccountEntry) {
+	dest.AuthRequired = account.IsAuthRequired()
+	dest.AuthRevocable = account.IsAuthRevocable()
+	dest.AuthImmutable = account.IsAuthImmutable()
+	dest.AuthClawbackEnabled = account.IsAuthClawbackEnabled()
+}
+
+// OmitEmptyAccountSubresources drops the empty sub-resources of an account
+// populated by PopulateAccountEntry so they are left out of the response.
+// Only data can be empty: signers always include the master key and balances
+// always include the native balance.
+func OmitEmptyAccountSubresources(dest *protocol.Account) {
+	if len(dest.Data) == 0 {
+		dest.Data = nil
+	}
+}
+
+// HexEncodeAccountKeys replaces the strkeys of the account id and of the
+// signers of an account populated by PopulateAccountEntry with the hex
+// encoding of their raw bytes: the ed25519 public key or the hash.
+func HexEncodeAccountKeys(dest *protocol.Account) error {
+	accountID, err := hexKey(dest.AccountID)
+	if err != nil {
+		return err
+	}
+	dest.AccountID = accountID
+
+	for i := range dest.Signers {
+		if dest.Signers[i].Key, err = hexKey(dest.Signers[i].Key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func hexKey(key string) (string, error) {
+	_, raw, err := strkey.DecodeAny(key)
+	if err != nil {
+		return "", errors.Wrapf(err, "decoding key %s", key)
+	}
+	return hex.EncodeToString(raw), nil
+}
+
+// isAccountImmutable returns true if the combined weight of all the signers
+// (including the master key) can't reach the low threshold, in which case the
+// account can never authorize an operation again.
+func isAccountImmutable(signers []protocol.Signer, lowThreshold byte) bool {
+	var totalWeight int32
+	for _, signer := range signers {
+		totalWeight += signer.Weight
+	}
+
+	return isWeightTooLow(totalWeight, lowThreshold)
+}
+
+// isWeightTooLow returns true if the combined weight of the signers of an
+// account can't reach the low threshold.
+func isWeightTooLow(totalWeight int32, lowThreshold byte) bool {
+	neededWeight := int32(lowThreshold)
+	if neededWeight == 0 {
+		neededWeight = 1
+	}
+
+	return totalWeight < neededWeight
+}
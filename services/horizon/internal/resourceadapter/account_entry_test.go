@@ -4,7 +4,9 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"github.com/guregu/null"
+	"reflect"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -66,6 +68,7 @@ var (
 			}
 			return t
 		}(),
+		ProtocolVersion: 17,
 	}
 
 	trustLineIssuer = xdr.MustAddress("GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H")
@@ -179,6 +182,8 @@ func TestPopulateAccountEntry(t *testing.T) {
 		tt.Equal(amount.StringFromInt64(t.Balance), ht.Balance)
 		tt.Equal(amount.StringFromInt64(t.BuyingLiabilities), ht.BuyingLiabilities)
 		tt.Equal(amount.StringFromInt64(t.SellingLiabilities), ht.SellingLiabilities)
+		tt.Equal(strconv.FormatInt(t.BuyingLiabilities, 10), ht.BuyingLiabilitiesStroops)
+		tt.Equal(strconv.FormatInt(t.SellingLiabilities, 10), ht.SellingLiabilitiesStroops)
 		tt.Equal(amount.StringFromInt64(t.Limit), ht.Limit)
 		tt.Equal(t.LastModifiedLedger, ht.LastModifiedLedger)
 		tt.Equal(t.IsAuthorized(), *ht.IsAuthorized)
@@ -190,6 +195,8 @@ func TestPopulateAccountEntry(t *testing.T) {
 	tt.Equal("0.0020000", native.Balance)
 	tt.Equal("0.0000003", native.BuyingLiabilities)
 	tt.Equal("0.0000004", native.SellingLiabilities)
+	tt.Equal("3", native.BuyingLiabilitiesStroops)
+	tt.Equal("4", native.SellingLiabilitiesStroops)
 	tt.Equal("", native.Limit)
 	tt.Equal("", native.Issuer)
 	tt.Equal("", native.Code)
@@ -246,6 +253,126 @@ func TestPopulateAccountEntry(t *testing.T) {
 	tt.JSONEq(want, string(links))
 }
 
+func TestPopulateAccountEntrySequenceDecomposition(t *testing.T) {
+	tt := assert.New(t)
+	ctx, _ := test.ContextWithLogBuffer()
+	hAccount := Account{}
+
+	largeSequenceAccount := account
+	largeSequenceAccount.SequenceNumber = 36425703536721938
+	err := PopulateAccountEntry(ctx, &hAccount, largeSequenceAccount, nil, nil, nil, nil)
+	tt.NoError(err)
+
+	tt.Equal("36425703536721938", hAccount.Sequence)
+	tt.Equal(uint32(8481020), hAccount.SequenceLedger)
+	tt.Equal(uint32(18), hAccount.SequenceBump)
+}
+
+func TestPopulateAccountEntryImmutable(t *testing.T) {
+	tt := assert.New(t)
+	ctx, _ := test.ContextWithLogBuffer()
+
+	lockedAccount := account
+	lockedAccount.MasterWeight = 0
+	lockedAccount.ThresholdLow = 255
+	lockedAccount.ThresholdMedium = 255
+	lockedAccount.ThresholdHigh = 255
+
+	hAccount := Account{}
+	err := PopulateAccountEntry(ctx, &hAccount, lockedAccount, nil, nil, nil, nil)
+	tt.NoError(err)
+	tt.True(hAccount.IsImmutable)
+
+	// thresholds set to 0 still require a signature with some weight
+	lockedAccount.ThresholdLow = 0
+	hAccount = Account{}
+	err = PopulateAccountEntry(ctx, &hAccount, lockedAccount, nil, nil, nil, nil)
+	tt.NoError(err)
+	tt.True(hAccount.IsImmutable)
+
+	// an additional signer which can reach the low threshold
+	lockedAccount.ThresholdLow = 1
+	hAccount = Account{}
+	err = PopulateAccountEntry(ctx, &hAccount, lockedAccount, nil, []history.AccountSigner{
+		{
+			Account: lockedAccount.AccountID,
+			Signer:  "GCMQBJWOLTCSSMWNVDJAXL6E42SADH563IL5MN5B6RBBP4XP7TBRLJKE",
+			Weight:  1,
+		},
+	}, nil, nil)
+	tt.NoError(err)
+	tt.False(hAccount.IsImmutable)
+
+	unlockedAccount := account
+	unlockedAccount.MasterWeight = 1
+	hAccount = Account{}
+	err = PopulateAccountEntry(ctx, &hAccount, unlockedAccount, nil, nil, nil, nil)
+	tt.NoError(err)
+	tt.False(hAccount.IsImmutable)
+}
+
+func TestPopulateAccountEntryMasterKeyWeight(t *testing.T) {
+	tt := assert.New(t)
+	ctx, _ := test.ContextWithLogBuffer()
+
+	enabledAccount := account
+	enabledAccount.MasterWeight = 1
+	hAccount := Account{}
+	err := PopulateAccountEntry(ctx, &hAccount, enabledAccount, nil, nil, nil, nil)
+	tt.NoError(err)
+	tt.Equal(int32(1), hAccount.MasterKeyWeight)
+	tt.False(hAccount.MasterKeyDisabled)
+
+	disabledAccount := account
+	disabledAccount.MasterWeight = 0
+	hAccount = Account{}
+	err = PopulateAccountEntry(ctx, &hAccount, disabledAccount, nil, nil, nil, nil)
+	tt.NoError(err)
+	tt.Equal(int32(0), hAccount.MasterKeyWeight)
+	tt.True(hAccount.MasterKeyDisabled)
+}
+
+func TestPopulateMinBalance(t *testing.T) {
+	tt := assert.New(t)
+
+	// (2 + 10 subentries) * 0.5 XLM
+	hAccount := Account{SubentryCount: 10}
+	PopulateMinBalance(&hAccount, 5000000)
+	tt.Equal("60000000", hAccount.MinBalanceStroops)
+
+	// (2 + 10 subentries + 34 sponsoring - 12 sponsored) * 0.5 XLM
+	hAccount = Account{SubentryCount: 10, NumSponsoring: 34, NumSponsored: 12}
+	PopulateMinBalance(&hAccount, 5000000)
+	tt.Equal("170000000", hAccount.MinBalanceStroops)
+
+	// a sponsored account never goes below the two base entries
+	hAccount = Account{SubentryCount: 1, NumSponsored: 3}
+	PopulateMinBalance(&hAccount, 5000000)
+	tt.Equal("10000000", hAccount.MinBalanceStroops)
+}
+
+func TestAccountFlagsPredateClawback(t *testing.T) {
+	tt := assert.New(t)
+	ctx, _ := test.ContextWithLogBuffer()
+
+	// account has the clawback flag set, which was introduced in protocol 17
+	preClawbackLedger := *ledgerWithCloseTime
+	preClawbackLedger.ProtocolVersion = 16
+	hAccount := Account{}
+	err := PopulateAccountEntry(ctx, &hAccount, account, nil, nil, nil, &preClawbackLedger)
+	tt.NoError(err)
+	tt.True(hAccount.Flags.AuthClawbackEnabled)
+	tt.True(AccountFlagsPredateClawback(&preClawbackLedger))
+
+	marshaled, err := json.Marshal(NewPreClawbackAccountFlags(hAccount.Flags))
+	tt.NoError(err)
+	tt.Equal(`{"auth_required":true,"auth_revocable":false,"auth_immutable":false}`, string(marshaled))
+
+	// the protocol version is unknown when the ledger isn't ingested
+	tt.False(AccountFlagsPredateClawback(ledgerWithCloseTime))
+	tt.False(AccountFlagsPredateClawback(nil))
+}
+
 func TestPopulateAccountEntryMasterMissingInSigners(t *testing.T) {
 	tt := assert.New(t)
 	ctx, _ := test.ContextWithLogBuffer()
@@ -270,3 +397,132 @@ func TestPopulateAccountEntryMasterMissingInSigners(t *testing.T) {
 	tt.Equal(protocol.MustKeyTypeFromAddress(account.AccountID), signer.Type)
 	tt.Nil(hAccount.LastModifiedTime)
 }
+
+func TestPopulateAccountEntrySignerHashes(t *testing.T) {
+	tt := assert.New(t)
+	ctx, _ := test.ContextWithLogBuffer()
+	hAccount := Account{}
+
+	hashXSigner := "XDOI75S3CEURAZC3DTNKWFAGCT6GIZMB4G26GWP2U74GDTNGQSDXEVCH"
+	preauthTxSigner := "TD3VSDLM5OANOIUKL3EYZ4OEWSMIMRWA7HNUVSZ2E43HS7AU3AEFDVTN"
+	accountSigners := []history.AccountSigner{
+		{
+			Account: accountID.Address(),
+			Signer:  accountID.Address(),
+			Weight:  int32(1),
+		},
+		{
+			Account: accountID.Address(),
+			Signer:  hashXSigner,
+			Weight:  int32(2),
+		},
+		{
+			Account: accountID.Address(),
+			Signer:  preauthTxSigner,
+			Weight:  int32(3),
+		},
+	}
+	err := PopulateAccountEntry(ctx, &hAccount, account, nil, accountSigners, nil, nil)
+	tt.NoError(err)
+	tt.Len(hAccount.Signers, 3)
+
+	tt.Equal("ed25519_public_key", hAccount.Signers[0].Type)
+	tt.Empty(hAccount.Signers[0].HashHex)
+	tt.Empty(hAccount.Signers[0].PreauthTxHashHex)
+
+	tt.Equal("sha256_hash", hAccount.Signers[1].Type)
+	tt.Equal(
+		"dc8ff65b112910645b1cdaab140614fc646581e1b5e359faa7f861cda6848772",
+		hAccount.Signers[1].HashHex,
+	)
+	tt.Empty(hAccount.Signers[1].PreauthTxHashHex)
+
+	tt.Equal("preauth_tx", hAccount.Signers[2].Type)
+	tt.Empty(hAccount.Signers[2].HashHex)
+	tt.Equal(
+		"f7590d6ceb80d7228a5ec98cf1c4b4988646c0f9db4acb3a2736797c14d80851",
+		hAccount.Signers[2].PreauthTxHashHex,
+	)
+
+	marshaled, err := json.Marshal(hAccount.Signers[0])
+	tt.NoError(err)
+	tt.NotContains(string(marshaled), "hash_hex")
+}
+
+func TestOmitEmptyAccountSubresources(t *testing.T) {
+	tt := assert.New(t)
+	ctx, _ := test.ContextWithLogBuffer()
+
+	hAccount := Account{}
+	err := PopulateAccountEntry(ctx, &hAccount, account, nil, signers, nil, nil)
+	tt.NoError(err)
+	marshaled, err := json.Marshal(hAccount)
+	tt.NoError(err)
+	tt.Contains(string(marshaled), `"data":{}`)
+
+	OmitEmptyAccountSubresources(&hAccount)
+	marshaled, err = json.Marshal(hAccount)
+	tt.NoError(err)
+	var keys map[string]json.RawMessage
+	tt.NoError(json.Unmarshal(marshaled, &keys))
+	tt.NotContains(keys, "data")
+	tt.Contains(string(marshaled), `"signers":[`)
+	tt.Contains(string(marshaled), `"balances":[{`)
+
+	hAccount = Account{}
+	err = PopulateAccountEntry(ctx, &hAccount, account, data, signers, nil, nil)
+	tt.NoError(err)
+	OmitEmptyAccountSubresources(&hAccount)
+	tt.Len(hAccount.Data, 2)
+}
+
+// TestAccountResourceFields is a snapshot of the fields of the account
+// resource. A new field must be added here after checking it doesn't expose
+// internal data.
+func TestAccountResourceFields(t *testing.T) {
+	var fields []string
+	accountType := reflect.TypeOf(protocol.Account{})
+	for i := 0; i < accountType.NumField(); i++ {
+		name := strings.Split(accountType.Field(i).Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		fields = append(fields, name)
+	}
+
+	assert.Equal(t, []string{
+		"_links",
+		"id",
+		"account_id",
+		"sequence",
+		"sequence_ledger",
+		"sequence_bump",
+		"subentry_count",
+		"inflation_destination",
+		"home_domain",
+		"last_modified_ledger",
+		"last_modified_time",
+		"thresholds",
+		"master_key_weight",
+		"master_key_disabled",
+		"flags",
+		"balances",
+		"signers",
+		"data",
+		"num_sponsoring",
+		"num_sponsored",
+		"sponsor",
+		"sponsoring",
+		"summary",
+		"min_balance_stroops",
+		"is_immutable",
+		"issued_assets",
+		"created_at_operation_id",
+		"created_ledger",
+		"offers_count",
+		"partial",
+		"warnings",
+		"paging_token",
+		"_embedded",
+	}, fields)
+}
@@ -34,13 +34,7 @@ func PopulateAssetStat(
 	if err != nil {
 		return err
 	}
-	flags := int8(issuer.Flags)
-	res.Flags = protocol.AccountFlags{
-		(flags & int8(xdr.AccountFlagsAuthRequiredFlag)) != 0,
-		(flags & int8(xdr.AccountFlagsAuthRevocableFlag)) != 0,
-		(flags & int8(xdr.AccountFlagsAuthImmutableFlag)) != 0,
-		(flags & int8(xdr.AccountFlagsAuthClawbackEnabledFlag)) != 0,
-	}
+	PopulateAccountFlags(&res.Flags, issuer)
 	res.PT = row.PagingToken()
 
 	trimmed := strings.TrimSpace(issuer.HomeDomain)
@@ -1,6 +1,8 @@
 package resourceadapter
 
 import (
+	"strconv"
+
 	"github.com/stellar/go/amount"
 	protocol "github.com/stellar/go/protocols/horizon"
 	"github.com/stellar/go/services/horizon/internal/assets"
@@ -18,6 +20,8 @@ func PopulateBalance(dest *protocol.Balance, row history.TrustLine) (err error)
 	dest.Balance = amount.StringFromInt64(row.Balance)
 	dest.BuyingLiabilities = amount.StringFromInt64(row.BuyingLiabilities)
 	dest.SellingLiabilities = amount.StringFromInt64(row.SellingLiabilities)
+	dest.BuyingLiabilitiesStroops = strconv.FormatInt(row.BuyingLiabilities, 10)
+	dest.SellingLiabilitiesStroops = strconv.FormatInt(row.SellingLiabilities, 10)
 	dest.Limit = amount.StringFromInt64(row.Limit)
 	dest.Issuer = row.AssetIssuer
 	dest.Code = row.AssetCode
@@ -33,12 +37,62 @@ func PopulateBalance(dest *protocol.Balance, row history.TrustLine) (err error)
 	if isClawbackEnabled {
 		dest.IsClawbackEnabled = &isClawbackEnabled
 	}
+	dest.Flags = &protocol.TrustLineFlags{
+		Authorized:                      isAuthorized,
+		AuthorizedToMaintainLiabilities: isAuthorizedToMaintainLiabilities,
+		ClawbackEnabled:                 isClawbackEnabled,
+	}
+	isDisabled := row.Limit == 0 && row.Balance == 0
+	dest.IsDisabled = &isDisabled
+	// balance + buying liabilities >= limit, without overflowing
+	isAtLimit := row.BuyingLiabilities >= row.Limit-row.Balance
+	dest.IsAtLimit = &isAtLimit
 	if row.Sponsor.Valid {
 		dest.Sponsor = row.Sponsor.String
 	}
 	return
 }
 
+// ExcludeDisabledBalances returns the balances without the disabled trust
+// lines (the ones with a zero limit and a zero balance).
+func ExcludeDisabledBalances(balances []protocol.Balance) []protocol.Balance {
+	enabled := make([]protocol.Balance, 0, len(balances))
+	for _, balance := range balances {
+		if balance.IsDisabled != nil && *balance.IsDisabled {
+			continue
+		}
+		enabled = append(enabled, balance)
+	}
+	return enabled
+}
+
+// PopulateAccountBalances returns the balances of the account: one balance
+// per trust line, in the given order, followed by the native balance.
+func PopulateAccountBalances(
+	account history.AccountEntry,
+	trustLines []history.TrustLine,
+) ([]protocol.Balance, error) {
+	balances := make([]protocol.Balance, len(trustLines)+1)
+	for i, tl := range trustLines {
+		err := PopulateBalance(&balances[i], tl)
+		if err != nil {
+			return nil, errors.Wrap(err, "populating balance")
+		}
+	}
+
+	// add native balance
+	err := PopulateNativeBalance(
+		&balances[len(balances)-1],
+		xdr.Int64(account.Balance),
+		xdr.Int64(account.BuyingLiabilities),
+		xdr.Int64(account.SellingLiabilities),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "populating native balance")
+	}
+	return balances, nil
+}
+
 func PopulateNativeBalance(dest *protocol.Balance, stroops, buyingLiabilities, sellingLiabilities xdr.Int64) (err error) {
 	dest.Type, err = assets.String(xdr.AssetTypeAssetTypeNative)
 	if err != nil {
@@ -48,11 +102,16 @@ func PopulateNativeBalance(dest *protocol.Balance, stroops, buyingLiabilities, s
 	dest.Balance = amount.String(stroops)
 	dest.BuyingLiabilities = amount.String(buyingLiabilities)
 	dest.SellingLiabilities = amount.String(sellingLiabilities)
+	dest.BuyingLiabilitiesStroops = strconv.FormatInt(int64(buyingLiabilities), 10)
+	dest.SellingLiabilitiesStroops = strconv.FormatInt(int64(sellingLiabilities), 10)
 	dest.LastModifiedLedger = 0
 	dest.Limit = ""
 	dest.Issuer = ""
 	dest.Code = ""
 	dest.IsAuthorized = nil
 	dest.IsAuthorizedToMaintainLiabilities = nil
+	dest.Flags = nil
+	dest.IsDisabled = nil
+	dest.IsAtLimit = nil
 	return
 }
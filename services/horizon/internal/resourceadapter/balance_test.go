@@ -72,6 +72,100 @@ func TestPopulateBalance(t *testing.T) {
 	assert.Equal(t, testAssetCode2, want.Code)
 	assert.Equal(t, false, *want.IsAuthorized)
 	assert.Equal(t, false, *want.IsAuthorizedToMaintainLiabilities)
+	assert.Equal(t, false, *want.IsDisabled)
+}
+
+func TestPopulateBalanceFlags(t *testing.T) {
+	for _, testCase := range []struct {
+		flags    uint32
+		expected TrustLineFlags
+	}{
+		{0, TrustLineFlags{}},
+		{1, TrustLineFlags{Authorized: true}},
+		{2, TrustLineFlags{AuthorizedToMaintainLiabilities: true}},
+		{3, TrustLineFlags{Authorized: true, AuthorizedToMaintainLiabilities: true}},
+		{5, TrustLineFlags{Authorized: true, ClawbackEnabled: true}},
+		{6, TrustLineFlags{AuthorizedToMaintainLiabilities: true, ClawbackEnabled: true}},
+	} {
+		want := Balance{}
+		err := PopulateBalance(&want, history.TrustLine{
+			AccountID: "testID",
+			AssetType: xdr.AssetTypeAssetTypeCreditAlphanum4,
+			AssetCode: "USD",
+			Limit:     100,
+			Balance:   10,
+			Flags:     testCase.flags,
+		})
+		assert.NoError(t, err)
+		if assert.NotNil(t, want.Flags, "flags %d", testCase.flags) {
+			assert.Equal(t, testCase.expected, *want.Flags, "flags %d", testCase.flags)
+		}
+		// is_authorized is kept for backwards compatibility
+		assert.Equal(t, testCase.expected.Authorized, *want.IsAuthorized)
+	}
+
+	want := Balance{}
+	assert.NoError(t, PopulateNativeBalance(&want, 10, 0, 0))
+	assert.Nil(t, want.Flags)
+}
+
+func TestPopulateDisabledBalance(t *testing.T) {
+	disabledTrustline := history.TrustLine{
+		AccountID:   "testID",
+		AssetType:   xdr.AssetTypeAssetTypeCreditAlphanum4,
+		AssetIssuer: "",
+		AssetCode:   "USD",
+		Limit:       0,
+		Balance:     0,
+		Flags:       1,
+	}
+
+	want := Balance{}
+	err := PopulateBalance(&want, disabledTrustline)
+	assert.NoError(t, err)
+	assert.Equal(t, "0.0000000", want.Limit)
+	assert.Equal(t, true, *want.IsDisabled)
+
+	native := Balance{}
+	assert.NoError(t, PopulateNativeBalance(&native, 0, 0, 0))
+	assert.Nil(t, native.IsDisabled)
+
+	balances := ExcludeDisabledBalances([]Balance{want, native})
+	assert.Equal(t, []Balance{native}, balances)
+}
+
+func TestPopulateBalanceAtLimit(t *testing.T) {
+	// the values of eurTrustLine in the actions tests
+	trustline := history.TrustLine{
+		AccountID:         "testID",
+		AssetType:         xdr.AssetTypeAssetTypeCreditAlphanum4,
+		AssetIssuer:       "",
+		AssetCode:         "EUR",
+		Limit:             223456789,
+		Balance:           20000,
+		BuyingLiabilities: 3,
+		Flags:             1,
+	}
+
+	want := Balance{}
+	assert.NoError(t, PopulateBalance(&want, trustline))
+	assert.Equal(t, false, *want.IsAtLimit)
+
+	// the buying liabilities fill the remaining room
+	trustline.Balance = 223456780
+	trustline.BuyingLiabilities = 9
+	want = Balance{}
+	assert.NoError(t, PopulateBalance(&want, trustline))
+	assert.Equal(t, true, *want.IsAtLimit)
+
+	trustline.BuyingLiabilities = 8
+	want = Balance{}
+	assert.NoError(t, PopulateBalance(&want, trustline))
+	assert.Equal(t, false, *want.IsAtLimit)
+
+	native := Balance{}
+	assert.NoError(t, PopulateNativeBalance(&native, 10, 10, 10))
+	assert.Nil(t, native.IsAtLimit)
 }
 
 func TestPopulateNativeBalance(t *testing.T) {
@@ -2,7 +2,9 @@ package resourceadapter
 
 import (
 	"context"
+	"strconv"
 
+	protocol "github.com/stellar/go/protocols/horizon"
 	"github.com/stellar/go/protocols/horizon/base"
 	"github.com/stellar/go/protocols/horizon/effects"
 	horizonContext "github.com/stellar/go/services/horizon/internal/context"
@@ -299,3 +301,22 @@ func populateEffectType(this *effects.Base, row history.Effect) {
 		this.Type = "unknown"
 	}
 }
+
+// PopulateSignerChange fills a signer change from a signer created, updated
+// or removed effect.
+func PopulateSignerChange(dest *protocol.SignerChange, row history.Effect) error {
+	var details struct {
+		PublicKey string `json:"public_key"`
+		Weight    int32  `json:"weight"`
+	}
+	if err := row.UnmarshalDetails(&details); err != nil {
+		return err
+	}
+
+	dest.Type = EffectTypeNames[row.Type]
+	dest.Key = details.PublicKey
+	dest.Weight = details.Weight
+	dest.OperationID = strconv.FormatInt(row.HistoryOperationID, 10)
+	dest.Ledger = row.LedgerSequence()
+	return nil
+}
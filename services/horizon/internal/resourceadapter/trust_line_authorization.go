@@ -0,0 +1,38 @@
+package resourceadapter
+
+import (
+	"context"
+	"fmt"
+
+	protocol "github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/services/horizon/internal/assets"
+	horizonContext "github.com/stellar/go/services/horizon/internal/context"
+	"github.com/stellar/go/services/horizon/internal/db2/history"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/support/render/hal"
+)
+
+// PopulateTrustLineAuthorization fills out the resource's fields
+func PopulateTrustLineAuthorization(
+	ctx context.Context,
+	dest *protocol.TrustLineAuthorization,
+	row history.TrustLineAuthorization,
+) (err error) {
+	dest.Type, err = assets.String(row.AssetType)
+	if err != nil {
+		return errors.Wrap(err, "getting the string representation from the provided xdr asset type")
+	}
+
+	dest.PT = fmt.Sprintf("%d", row.LedgerSequence)
+	dest.Ledger = row.LedgerSequence
+	dest.AccountID = row.AccountID
+	dest.Code = row.AssetCode
+	dest.Issuer = row.AssetIssuer
+	dest.IsAuthorized = row.IsAuthorized()
+	// Fully authorized trust lines can also maintain liabilities
+	dest.IsAuthorizedToMaintainLiabilities = dest.IsAuthorized || row.IsAuthorizedToMaintainLiabilities()
+
+	lb := hal.LinkBuilder{horizonContext.BaseURL(ctx)}
+	dest.Links.Ledger = lb.Linkf("/ledgers/%d", row.LedgerSequence)
+	return nil
+}
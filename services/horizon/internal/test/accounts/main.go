@@ -0,0 +1,95 @@
+// Package accounts contains helpers inserting account fixtures in the
+// state tables of the horizon database.
+package accounts
+
+import (
+	"context"
+
+	"github.com/guregu/null"
+
+	"github.com/stellar/go/services/horizon/internal/db2/history"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/xdr"
+)
+
+// InsertFullAccount inserts the account entry along with its trust lines and
+// data entries, all last modified at the given ledger. The signers (including
+// the master key) and their sponsors are taken from the account entry, like
+// ingestion does.
+func InsertFullAccount(
+	ctx context.Context,
+	q *history.Q,
+	ledger xdr.Uint32,
+	account xdr.AccountEntry,
+	trustLines []xdr.TrustLineEntry,
+	data []xdr.DataEntry,
+) error {
+	batch := q.NewAccountsBatchInsertBuilder(0)
+	err := batch.Add(ctx, xdr.LedgerEntry{
+		LastModifiedLedgerSeq: ledger,
+		Data: xdr.LedgerEntryData{
+			Type:    xdr.LedgerEntryTypeAccount,
+			Account: &account,
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "could not add account")
+	}
+	if err = batch.Exec(ctx); err != nil {
+		return errors.Wrap(err, "could not insert account")
+	}
+
+	address := account.AccountId.Address()
+	signersBatch := q.NewAccountSignersBatchInsertBuilder(0)
+	sponsors := account.SponsorPerSigner()
+	for signer, weight := range account.SignerSummary() {
+		var sponsor null.String
+		if sponsorID, isSponsored := sponsors[signer]; isSponsored {
+			sponsor = null.StringFrom(sponsorID.Address())
+		}
+		err = signersBatch.Add(ctx, history.AccountSigner{
+			Account: address,
+			Signer:  signer,
+			Weight:  weight,
+			Sponsor: sponsor,
+		})
+		if err != nil {
+			return errors.Wrap(err, "could not add signer")
+		}
+	}
+	if err = signersBatch.Exec(ctx); err != nil {
+		return errors.Wrap(err, "could not insert signers")
+	}
+
+	for i := range trustLines {
+		trustLine := trustLines[i]
+		trustLine.AccountId = account.AccountId
+		_, err = q.InsertTrustLine(ctx, xdr.LedgerEntry{
+			LastModifiedLedgerSeq: ledger,
+			Data: xdr.LedgerEntryData{
+				Type:      xdr.LedgerEntryTypeTrustline,
+				TrustLine: &trustLine,
+			},
+		})
+		if err != nil {
+			return errors.Wrap(err, "could not insert trust line")
+		}
+	}
+
+	for i := range data {
+		entry := data[i]
+		entry.AccountId = account.AccountId
+		_, err = q.InsertAccountData(ctx, xdr.LedgerEntry{
+			LastModifiedLedgerSeq: ledger,
+			Data: xdr.LedgerEntryData{
+				Type: xdr.LedgerEntryTypeData,
+				Data: &entry,
+			},
+		})
+		if err != nil {
+			return errors.Wrap(err, "could not insert data")
+		}
+	}
+
+	return nil
+}
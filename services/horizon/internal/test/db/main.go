@@ -22,7 +22,7 @@ var (
 )
 
 // Horizon returns a connection to the horizon test database
-func Horizon(t *testing.T) *sqlx.DB {
+func Horizon(t testing.TB) *sqlx.DB {
 	if horizonDB != nil {
 		return horizonDB
 	}
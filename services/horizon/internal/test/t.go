@@ -67,7 +67,7 @@ func (t *T) ScenarioWithoutHorizon(name string) ledger.Status {
 }
 
 // ResetHorizonDB sets up a new horizon database with empty tables
-func ResetHorizonDB(t *testing.T, db *sqlx.DB) {
+func ResetHorizonDB(t testing.TB, db *sqlx.DB) {
 	clearHorizonDB(t, db)
 	_, err := schema.Migrate(db.DB, schema.MigrateUp, 0)
 	if err != nil {
@@ -75,7 +75,7 @@ func ResetHorizonDB(t *testing.T, db *sqlx.DB) {
 	}
 }
 
-func clearHorizonDB(t *testing.T, db *sqlx.DB) {
+func clearHorizonDB(t testing.TB, db *sqlx.DB) {
 	_, err := schema.Migrate(db.DB, schema.MigrateDown, 0)
 	if err != nil {
 		t.Fatalf("could not run migrations down on test db: %v", err)
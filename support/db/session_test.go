@@ -3,8 +3,10 @@ package db
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stellar/go/support/db/dbtest"
+	"github.com/stellar/go/support/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -91,3 +93,32 @@ func TestSession(t *testing.T) {
 		assert.Equal("$1 = $2 = $3 = ?", out)
 	}
 }
+
+func TestSessionCancelledQuery(t *testing.T) {
+	db := dbtest.Postgres(t).Load(testSchema)
+	defer db.Close()
+
+	assert := assert.New(t)
+	sess := &Session{DB: db.Open()}
+	defer sess.DB.Close()
+
+	// the deadline cancels the statement instead of waiting for it
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := sess.ExecRaw(ctx, "SELECT pg_sleep(10)")
+	assert.Error(err)
+	assert.True(time.Since(start) < 5*time.Second, "statement was not cancelled")
+	cause := errors.Cause(err)
+	assert.True(
+		cause == ErrCancelled || cause == context.DeadlineExceeded,
+		"unexpected error: %v", err,
+	)
+
+	// a cancelled context doesn't run the query at all
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	var count int
+	err = sess.GetRaw(ctx, &count, "SELECT COUNT(*) FROM people")
+	assert.Equal(context.Canceled, errors.Cause(err))
+}
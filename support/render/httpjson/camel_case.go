@@ -0,0 +1,69 @@
+package httpjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// MarshalCamelCase encodes v with json.Marshal, then converts the object keys
+// from snake_case to camelCase. Leading underscores are kept, so "_links"
+// stays "_links". The keys of the objects under a "data" key are data entry
+// names chosen by the users, so they are left untouched.
+//
+// The keys are renamed in the output of json.Marshal, so the MarshalJSON
+// methods and the json tags of v are honoured. The objects are re-encoded
+// with their keys sorted.
+func MarshalCamelCase(v interface{}) ([]byte, error) {
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	// keep the numbers as they were encoded, int64 values don't fit in a
+	// float64
+	decoder.UseNumber()
+	var decoded interface{}
+	if err = decoder.Decode(&decoded); err != nil {
+		return nil, err
+	}
+	return json.Marshal(camelCaseKeys(decoded))
+}
+
+// CamelCase converts a snake_case key to camelCase.
+func CamelCase(key string) string {
+	trimmed := strings.TrimLeft(key, "_")
+	prefix := key[:len(key)-len(trimmed)]
+	parts := strings.Split(trimmed, "_")
+	for i := 1; i < len(parts); i++ {
+		if parts[i] != "" {
+			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		}
+	}
+	return prefix + strings.Join(parts, "")
+}
+
+// camelCaseKeys renames the keys of the objects in the decoded json value v,
+// recursively.
+func camelCaseKeys(v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		renamed := make(map[string]interface{}, len(value))
+		for key, field := range value {
+			if key == "data" {
+				renamed[key] = field
+				continue
+			}
+			renamed[CamelCase(key)] = camelCaseKeys(field)
+		}
+		return renamed
+	case []interface{}:
+		for i, item := range value {
+			value[i] = camelCaseKeys(item)
+		}
+		return value
+	default:
+		return v
+	}
+}
@@ -0,0 +1,89 @@
+package httpjson
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestCamelCase(t *testing.T) {
+	cases := map[string]string{
+		"id":                                    "id",
+		"account_id":                            "accountId",
+		"is_authorized_to_maintain_liabilities": "isAuthorizedToMaintainLiabilities",
+		"_links":                                "_links",
+		"_embedded":                             "_embedded",
+		"paging_token":                          "pagingToken",
+	}
+	for key, want := range cases {
+		if got := CamelCase(key); got != want {
+			t.Errorf("CamelCase(%q) = %q, want %q", key, got, want)
+		}
+	}
+}
+
+type camelCaseEmbedded struct {
+	AssetType string `json:"asset_type"`
+}
+
+type camelCaseItem struct {
+	Weight  int32  `json:"weight"`
+	Sponsor string `json:"sponsor,omitempty"`
+	camelCaseEmbedded
+}
+
+type camelCaseResource struct {
+	Links struct {
+		Self string `json:"self_link"`
+	} `json:"_links"`
+	AccountID  string            `json:"account_id"`
+	Sequence   int64             `json:"sequence"`
+	Items      []camelCaseItem   `json:"balance_items"`
+	Data       map[string]string `json:"data"`
+	IsDisabled *bool             `json:"is_disabled,omitempty"`
+	ModifiedAt *time.Time        `json:"last_modified_time"`
+	Ignored    string            `json:"-"`
+}
+
+func TestMarshalCamelCase(t *testing.T) {
+	modifiedAt := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	resource := camelCaseResource{
+		AccountID:  "GABC",
+		Sequence:   9007199254740993,
+		Items:      []camelCaseItem{{Weight: 1, camelCaseEmbedded: camelCaseEmbedded{"native"}}},
+		Data:       map[string]string{"snake_case_name": "dmFsdWU="},
+		ModifiedAt: &modifiedAt,
+		Ignored:    "ignored",
+	}
+	resource.Links.Self = "/accounts/GABC"
+
+	got, err := MarshalCamelCase(&resource)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"_links":{"selfLink":"/accounts/GABC"},"accountId":"GABC",` +
+		`"balanceItems":[{"assetType":"native","weight":1}],` +
+		`"data":{"snake_case_name":"dmFsdWU="},` +
+		`"lastModifiedTime":"2020-01-02T03:04:05Z","sequence":9007199254740993}`
+	if string(got) != want {
+		t.Errorf("got: %s, want: %s", got, want)
+	}
+}
+
+type camelCaseMarshaler struct {
+	Weight int32 `json:"weight"`
+}
+
+func (m camelCaseMarshaler) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]int32{"custom_weight": m.Weight * 2})
+}
+
+func TestMarshalCamelCaseHonoursMarshalJSON(t *testing.T) {
+	got, err := MarshalCamelCase([]camelCaseMarshaler{{Weight: 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `[{"customWeight":2}]`; string(got) != want {
+		t.Errorf("got: %s, want: %s", got, want)
+	}
+}
@@ -0,0 +1,303 @@
+// Package msgpack encodes and decodes values in the MessagePack format.
+//
+// Values are encoded through their JSON representation, so the field names
+// (and MarshalJSON methods) of the resources rendered by horizon are kept and
+// only the JSON types (nil, bool, numbers, strings, arrays and maps) are
+// encoded.
+package msgpack
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"net/http"
+	"sort"
+
+	"github.com/stellar/go/support/errors"
+)
+
+// MimeType is the mime type of MessagePack bodies.
+const MimeType = "application/msgpack"
+
+// Marshal encodes v in MessagePack, using the same field names as its JSON
+// encoding. Map keys are sorted so the encoding is deterministic.
+func Marshal(v interface{}) ([]byte, error) {
+	js, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(js))
+	decoder.UseNumber()
+	var generic interface{}
+	if err = decoder.Decode(&generic); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err = encode(&buf, generic); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Render writes data encoded in MessagePack to the response.
+func Render(w http.ResponseWriter, data interface{}) {
+	body, err := Marshal(data)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", MimeType)
+	w.WriteHeader(http.StatusOK)
+	w.Write(body)
+}
+
+func encode(buf *bytes.Buffer, v interface{}) error {
+	switch value := v.(type) {
+	case nil:
+		buf.WriteByte(0xc0)
+	case bool:
+		if value {
+			buf.WriteByte(0xc3)
+		} else {
+			buf.WriteByte(0xc2)
+		}
+	case json.Number:
+		if i, err := value.Int64(); err == nil {
+			encodeInt(buf, i)
+			return nil
+		}
+		f, err := value.Float64()
+		if err != nil {
+			return errors.Wrapf(err, "invalid number %s", value)
+		}
+		buf.WriteByte(0xcb)
+		writeUint(buf, math.Float64bits(f), 8)
+	case string:
+		encodeHeader(buf, len(value), 0xa0, 32, 0xd9, 0xda, 0xdb)
+		buf.WriteString(value)
+	case []interface{}:
+		encodeHeader(buf, len(value), 0x90, 16, 0, 0xdc, 0xdd)
+		for _, item := range value {
+			if err := encode(buf, item); err != nil {
+				return err
+			}
+		}
+	case map[string]interface{}:
+		keys := make([]string, 0, len(value))
+		for key := range value {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		encodeHeader(buf, len(value), 0x80, 16, 0, 0xde, 0xdf)
+		for _, key := range keys {
+			if err := encode(buf, key); err != nil {
+				return err
+			}
+			if err := encode(buf, value[key]); err != nil {
+				return err
+			}
+		}
+	default:
+		return errors.Errorf("unsupported type %T", v)
+	}
+	return nil
+}
+
+// encodeHeader writes the type and the length of a string, an array or a
+// map, using the fixed format when the length is below fixedMax. Arrays and
+// maps have no 8 bit format, their code8 is 0.
+func encodeHeader(buf *bytes.Buffer, length int, fixed byte, fixedMax int, code8, code16, code32 byte) {
+	switch {
+	case length < fixedMax:
+		buf.WriteByte(fixed | byte(length))
+	case code8 != 0 && length <= math.MaxUint8:
+		buf.WriteByte(code8)
+		buf.WriteByte(byte(length))
+	case length <= math.MaxUint16:
+		buf.WriteByte(code16)
+		writeUint(buf, uint64(length), 2)
+	default:
+		buf.WriteByte(code32)
+		writeUint(buf, uint64(length), 4)
+	}
+}
+
+func encodeInt(buf *bytes.Buffer, i int64) {
+	switch {
+	case i >= 0 && i <= 0x7f:
+		buf.WriteByte(byte(i))
+	case i < 0 && i >= -32:
+		buf.WriteByte(byte(i))
+	case i >= math.MinInt8 && i <= math.MaxInt8:
+		buf.WriteByte(0xd0)
+		buf.WriteByte(byte(i))
+	case i >= math.MinInt16 && i <= math.MaxInt16:
+		buf.WriteByte(0xd1)
+		writeUint(buf, uint64(i), 2)
+	case i >= math.MinInt32 && i <= math.MaxInt32:
+		buf.WriteByte(0xd2)
+		writeUint(buf, uint64(i), 4)
+	default:
+		buf.WriteByte(0xd3)
+		writeUint(buf, uint64(i), 8)
+	}
+}
+
+func writeUint(buf *bytes.Buffer, v uint64, size int) {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], v)
+	buf.Write(b[8-size:])
+}
+
+// Unmarshal decodes a MessagePack value into nil, bool, int64, uint64,
+// float64, string, []interface{} or map[string]interface{} values. Only map
+// keys which are strings are supported.
+func Unmarshal(data []byte) (interface{}, error) {
+	d := decoder{data: data}
+	v, err := d.decode()
+	if err != nil {
+		return nil, err
+	}
+	if d.pos != len(d.data) {
+		return nil, errors.New("unexpected data after the value")
+	}
+	return v, nil
+}
+
+type decoder struct {
+	data []byte
+	pos  int
+}
+
+func (d *decoder) next(n int) ([]byte, error) {
+	if len(d.data)-d.pos < n {
+		return nil, errors.New("unexpected end of data")
+	}
+	b := d.data[d.pos : d.pos+n]
+	d.pos += n
+	return b, nil
+}
+
+func (d *decoder) readUint(size int) (uint64, error) {
+	b, err := d.next(size)
+	if err != nil {
+		return 0, err
+	}
+	var v uint64
+	for _, c := range b {
+		v = v<<8 | uint64(c)
+	}
+	return v, nil
+}
+
+func (d *decoder) decode() (interface{}, error) {
+	b, err := d.next(1)
+	if err != nil {
+		return nil, err
+	}
+	code := b[0]
+
+	switch {
+	case code <= 0x7f:
+		return int64(code), nil
+	case code >= 0xe0:
+		return int64(int8(code)), nil
+	case code&0xe0 == 0xa0:
+		return d.decodeString(int(code & 0x1f))
+	case code&0xf0 == 0x90:
+		return d.decodeArray(int(code & 0x0f))
+	case code&0xf0 == 0x80:
+		return d.decodeMap(int(code & 0x0f))
+	}
+
+	switch code {
+	case 0xc0:
+		return nil, nil
+	case 0xc2:
+		return false, nil
+	case 0xc3:
+		return true, nil
+	case 0xca:
+		v, err := d.readUint(4)
+		return float64(math.Float32frombits(uint32(v))), err
+	case 0xcb:
+		v, err := d.readUint(8)
+		return math.Float64frombits(v), err
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		return d.readUint(1 << (code - 0xcc))
+	case 0xd0:
+		v, err := d.readUint(1)
+		return int64(int8(v)), err
+	case 0xd1:
+		v, err := d.readUint(2)
+		return int64(int16(v)), err
+	case 0xd2:
+		v, err := d.readUint(4)
+		return int64(int32(v)), err
+	case 0xd3:
+		v, err := d.readUint(8)
+		return int64(v), err
+	case 0xd9, 0xda, 0xdb:
+		length, err := d.readUint(1 << (code - 0xd9))
+		if err != nil {
+			return nil, err
+		}
+		return d.decodeString(int(length))
+	case 0xdc, 0xdd:
+		length, err := d.readUint(2 << (code - 0xdc))
+		if err != nil {
+			return nil, err
+		}
+		return d.decodeArray(int(length))
+	case 0xde, 0xdf:
+		length, err := d.readUint(2 << (code - 0xde))
+		if err != nil {
+			return nil, err
+		}
+		return d.decodeMap(int(length))
+	}
+
+	return nil, errors.Errorf("unsupported type 0x%x", code)
+}
+
+func (d *decoder) decodeString(length int) (interface{}, error) {
+	b, err := d.next(length)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+func (d *decoder) decodeArray(length int) (interface{}, error) {
+	array := make([]interface{}, 0, length)
+	for i := 0; i < length; i++ {
+		item, err := d.decode()
+		if err != nil {
+			return nil, err
+		}
+		array = append(array, item)
+	}
+	return array, nil
+}
+
+func (d *decoder) decodeMap(length int) (interface{}, error) {
+	m := make(map[string]interface{}, length)
+	for i := 0; i < length; i++ {
+		key, err := d.decode()
+		if err != nil {
+			return nil, err
+		}
+		keyString, ok := key.(string)
+		if !ok {
+			return nil, errors.Errorf("unsupported map key type %T", key)
+		}
+		if m[keyString], err = d.decode(); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
@@ -0,0 +1,89 @@
+package msgpack
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarshalRoundTrip(t *testing.T) {
+	type nested struct {
+		Weight int32 `json:"weight"`
+	}
+	in := struct {
+		ID       string            `json:"id"`
+		Small    int               `json:"small"`
+		Negative int               `json:"negative"`
+		Large    int64             `json:"large"`
+		Fraction float64           `json:"fraction"`
+		Flag     bool              `json:"flag"`
+		Missing  *string           `json:"missing"`
+		Long     string            `json:"long"`
+		Items    []nested          `json:"items"`
+		Data     map[string]string `json:"data"`
+		Omitted  string            `json:"omitted,omitempty"`
+	}{
+		ID:       "GABGMPEKKDWR2WFH5AJOZV5PDKLJEHGCR3Q24ALETWR5H3A7GI3YTS7V",
+		Small:    7,
+		Negative: -1000,
+		Large:    223456789,
+		Fraction: 0.5,
+		Flag:     true,
+		Long:     strings.Repeat("a", 300),
+		Items:    []nested{{Weight: 1}, {Weight: 200}},
+		Data:     map[string]string{"name": "value"},
+	}
+
+	encoded, err := Marshal(in)
+	require.NoError(t, err)
+	decoded, err := Unmarshal(encoded)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]interface{}{
+		"id":       in.ID,
+		"small":    int64(7),
+		"negative": int64(-1000),
+		"large":    int64(223456789),
+		"fraction": 0.5,
+		"flag":     true,
+		"missing":  nil,
+		"long":     in.Long,
+		"items": []interface{}{
+			map[string]interface{}{"weight": int64(1)},
+			map[string]interface{}{"weight": int64(200)},
+		},
+		"data": map[string]interface{}{"name": "value"},
+	}, decoded)
+}
+
+func TestMarshalCompactEncoding(t *testing.T) {
+	encoded, err := Marshal(map[string]interface{}{"a": 1, "b": []bool{false}})
+	require.NoError(t, err)
+	// fixmap of 2, fixstr "a", fixint 1, fixstr "b", fixarray of 1, false
+	assert.Equal(t, []byte{0x82, 0xa1, 'a', 0x01, 0xa1, 'b', 0x91, 0xc2}, encoded)
+}
+
+func TestUnmarshalInvalid(t *testing.T) {
+	_, err := Unmarshal([]byte{0xa5, 'a'})
+	assert.EqualError(t, err, "unexpected end of data")
+
+	_, err = Unmarshal([]byte{0x01, 0x02})
+	assert.EqualError(t, err, "unexpected data after the value")
+
+	_, err = Unmarshal([]byte{0x81, 0x01, 0x01})
+	assert.EqualError(t, err, "unsupported map key type int64")
+}
+
+func TestRender(t *testing.T) {
+	w := httptest.NewRecorder()
+	Render(w, map[string]string{"id": "1"})
+
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, MimeType, w.Header().Get("Content-Type"))
+	decoded, err := Unmarshal(w.Body.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"id": "1"}, decoded)
+}
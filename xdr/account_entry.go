@@ -29,46 +29,58 @@ func (account *AccountEntry) ThresholdHigh() byte {
 	return account.Thresholds.ThresholdHigh()
 }
 
+// AccountEntryExtensions holds the fields of the extensions of an account
+// entry in a single struct. The fields of the extensions missing from the
+// entry are zero.
+type AccountEntryExtensions struct {
+	// Liabilities are set by the V1 extension.
+	Liabilities Liabilities
+	// NumSponsored, NumSponsoring and SignerSponsoringIDs are set by the V2
+	// extension.
+	NumSponsored  Uint32
+	NumSponsoring Uint32
+	// SignerSponsoringIDs has one descriptor per signer, all nil when the V2
+	// extension does not exist.
+	SignerSponsoringIDs []SponsorshipDescriptor
+}
+
+// NormalizedExt flattens the V1 and V2 extensions of the account, so the
+// callers don't need to check which extensions are set.
+func (account *AccountEntry) NormalizedExt() AccountEntryExtensions {
+	var ext AccountEntryExtensions
+	if v1 := account.Ext.V1; v1 != nil {
+		ext.Liabilities = v1.Liabilities
+		if v2 := v1.Ext.V2; v2 != nil {
+			ext.NumSponsored = v2.NumSponsored
+			ext.NumSponsoring = v2.NumSponsoring
+			ext.SignerSponsoringIDs = v2.SignerSponsoringIDs
+		}
+	}
+	if ext.SignerSponsoringIDs == nil {
+		ext.SignerSponsoringIDs = make([]SponsorshipDescriptor, len(account.Signers))
+	}
+	return ext
+}
+
 // Liabilities returns AccountEntry's liabilities
 func (account *AccountEntry) Liabilities() Liabilities {
-	var liabilities Liabilities
-	if account.Ext.V1 != nil {
-		liabilities = account.Ext.V1.Liabilities
-	}
-	return liabilities
+	return account.NormalizedExt().Liabilities
 }
 
 // NumSponsored returns NumSponsored value for account.
 func (account *AccountEntry) NumSponsored() Uint32 {
-	var numSponsored Uint32
-	if account.Ext.V1 != nil &&
-		account.Ext.V1.Ext.V2 != nil {
-		numSponsored = account.Ext.V1.Ext.V2.NumSponsored
-	}
-	return numSponsored
+	return account.NormalizedExt().NumSponsored
 }
 
 // NumSponsoring returns NumSponsoring value for account.
 func (account *AccountEntry) NumSponsoring() Uint32 {
-	var numSponsoring Uint32
-	if account.Ext.V1 != nil &&
-		account.Ext.V1.Ext.V2 != nil {
-		numSponsoring = account.Ext.V1.Ext.V2.NumSponsoring
-	}
-	return numSponsoring
+	return account.NormalizedExt().NumSponsoring
 }
 
 // SignerSponsoringIDs returns SignerSponsoringIDs value for account.
 // This will return a slice of nil values if V2 extension does not exist.
 func (account *AccountEntry) SignerSponsoringIDs() []SponsorshipDescriptor {
-	var ids []SponsorshipDescriptor
-	if account.Ext.V1 != nil &&
-		account.Ext.V1.Ext.V2 != nil {
-		ids = account.Ext.V1.Ext.V2.SignerSponsoringIDs
-	} else {
-		ids = make([]SponsorshipDescriptor, len(account.Signers))
-	}
-	return ids
+	return account.NormalizedExt().SignerSponsoringIDs
 }
 
 // SponsorPerSigner returns a mapping of signer to its sponsor
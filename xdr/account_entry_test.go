@@ -113,3 +113,73 @@ func TestAccountEntrySponsorships(t *testing.T) {
 	assert.Equal(t, desc, signerIDs[0])
 	assert.Equal(t, expectedSponsorsForSigners, account.SponsorPerSigner())
 }
+
+func TestAccountEntryNormalizedExt(t *testing.T) {
+	signer := MustSigner("GCA4M7QXVBVEVRBU53PJZPXANRNPESGKGOT7UZ4RR4CBVBMQHMFKLZ4W")
+	sponsor := MustAddress("GCO26ZSBD63TKYX45H2C7D2WOFWOUSG5BMTNC3BG4QMXM3PAYI6WHKVZ")
+	desc := SponsorshipDescriptor(&sponsor)
+	liabilities := Liabilities{Buying: 100, Selling: 101}
+
+	for _, testCase := range []struct {
+		name     string
+		ext      AccountEntryExt
+		expected AccountEntryExtensions
+	}{
+		{
+			name: "v0",
+			ext:  AccountEntryExt{},
+			expected: AccountEntryExtensions{
+				SignerSponsoringIDs: []SponsorshipDescriptor{nil},
+			},
+		},
+		{
+			name: "v1",
+			ext: AccountEntryExt{
+				V: 1,
+				V1: &AccountEntryExtensionV1{
+					Liabilities: liabilities,
+				},
+			},
+			expected: AccountEntryExtensions{
+				Liabilities:         liabilities,
+				SignerSponsoringIDs: []SponsorshipDescriptor{nil},
+			},
+		},
+		{
+			name: "v2",
+			ext: AccountEntryExt{
+				V: 1,
+				V1: &AccountEntryExtensionV1{
+					Liabilities: liabilities,
+					Ext: AccountEntryExtensionV1Ext{
+						V: 2,
+						V2: &AccountEntryExtensionV2{
+							NumSponsored:        1,
+							NumSponsoring:       2,
+							SignerSponsoringIDs: []SponsorshipDescriptor{desc},
+						},
+					},
+				},
+			},
+			expected: AccountEntryExtensions{
+				Liabilities:         liabilities,
+				NumSponsored:        1,
+				NumSponsoring:       2,
+				SignerSponsoringIDs: []SponsorshipDescriptor{desc},
+			},
+		},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			account := AccountEntry{
+				Signers: []Signer{{Key: signer}},
+				Ext:     testCase.ext,
+			}
+			ext := account.NormalizedExt()
+			assert.Equal(t, testCase.expected, ext)
+			assert.Equal(t, ext.Liabilities, account.Liabilities())
+			assert.Equal(t, ext.NumSponsored, account.NumSponsored())
+			assert.Equal(t, ext.NumSponsoring, account.NumSponsoring())
+			assert.Equal(t, ext.SignerSponsoringIDs, account.SignerSponsoringIDs())
+		})
+	}
+}